@@ -9,17 +9,31 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/rs/zerolog"
+	mongodriver "go.mongodb.org/mongo-driver/mongo"
+
 	"api-database/internal/application/data"
 	"api-database/internal/config"
+	"api-database/internal/domain/job"
+	"api-database/internal/infrastructure/connector"
+	"api-database/internal/infrastructure/localdisk"
+	"api-database/internal/infrastructure/memcache"
 	"api-database/internal/infrastructure/mongo"
+	"api-database/internal/infrastructure/mysql"
+	"api-database/internal/infrastructure/postgres"
 	"api-database/internal/infrastructure/rabbitmq"
+	"api-database/internal/infrastructure/sqlite"
 	httpserver "api-database/internal/presentation/http"
+	httpmiddleware "api-database/internal/presentation/http/middleware"
 	"api-database/internal/telemetry"
 )
 
 func main() {
 	cfg := config.Load()
 	logger := telemetry.NewLogger(cfg.LogLevel)
+	if !httpmiddleware.ValidAuthMode(cfg.Auth.Mode) {
+		logger.Fatal().Str("mode", cfg.Auth.Mode).Msg("invalid AUTH_MODE (expected required, optional or disabled)")
+	}
 
 	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
 	defer stop()
@@ -43,14 +57,50 @@ func main() {
 	dsRepo := mongo.NewDataSourceRepository(mongoClient, cfg.Mongo.DBName)
 	akRepo := mongo.NewAPIKeyRepository(mongoClient, cfg.Mongo.DBName)
 	jobsRepo := mongo.NewJobRepository(mongoClient, cfg.Mongo.DBName)
-	queryService := data.NewQueryService(dsRepo)
+	connectors := connector.NewFactory()
+	connectors.Register("postgres", connector.Driver{Open: postgres.Open, Translator: postgres.NewTranslator(), Inspector: postgres.NewInspector()})
+	connectors.Register("mongodb", connector.Driver{Open: mongo.Open, Translator: mongo.NewTranslator()})
+	connectors.Register("mysql", connector.Driver{Open: mysql.Open, Translator: mysql.NewTranslator(), Inspector: mysql.NewInspector()})
+	connectors.Register("sqlite", connector.Driver{Open: sqlite.Open, Translator: sqlite.NewTranslator(), Inspector: sqlite.NewInspector()})
+	defer connectors.Close()
+	asyncTimeout := time.Duration(cfg.Thresholds.AsyncQueryTimeoutMs) * time.Millisecond
+	queryService := data.NewQueryService(dsRepo, connectors).
+		WithTimeouts(time.Duration(cfg.Thresholds.QueryTimeoutMs)*time.Millisecond, asyncTimeout).
+		WithExportLimit(cfg.Thresholds.ExportMaxRows).
+		WithBatchWorkers(cfg.Thresholds.BatchWorkers)
+	if cfg.Auth.Mode == httpmiddleware.AuthModeDisabled {
+		logger.Warn().Msg("AUTH_MODE=disabled: API key permissions are not enforced")
+		queryService.DisableAuthorization()
+	}
+	switch {
+	case cfg.Pagination.CursorSecret != "":
+		queryService.WithCursorSecret([]byte(cfg.Pagination.CursorSecret))
+	case cfg.Env != "development":
+		// Sem chave compartilhada, cursores falham entre réplicas e após cada restart.
+		logger.Fatal().Str("env", cfg.Env).Msg("CURSOR_SECRET is required outside development")
+	default:
+		logger.Warn().Msg("CURSOR_SECRET not set: pagination cursors are only valid on this instance until restart")
+	}
+	if cfg.Cache.Enabled() {
+		queryService.WithCache(memcache.NewLRU(cfg.Cache.MaxItems, int64(cfg.Cache.MaxBytes),
+			time.Duration(cfg.Cache.TTLSeconds)*time.Second, cfg.Cache.TableQuota))
+	}
 	metrics := telemetry.NewMetrics(1000)
-	dataHandler := httpserver.NewDataHandler(queryService, metrics, jobsRepo, rabbitClient)
+	resultStore := newResultStore(cfg, mongoClient, logger)
+	if resultStore != nil {
+		go data.RunResultJanitor(ctx, resultStore, 10*time.Minute, logger)
+	}
+	dataHandler := httpserver.NewDataHandler(queryService, metrics, jobsRepo, rabbitClient, resultStore).
+		WithAutoAsync(cfg.Thresholds.AsyncSwitchP95Ms)
 
 	router := httpserver.NewRouter(cfg, logger, dataHandler, dsRepo, metrics, akRepo)
 
-	processor := data.NewJobProcessor(queryService, jobsRepo, metrics, logger)
-	if err := rabbitClient.Consume(ctx, processor.Handle); err != nil {
+	processor := data.NewJobProcessor(queryService, jobsRepo, akRepo, metrics, logger)
+	if resultStore != nil {
+		processor.WithResultStore(resultStore, time.Duration(cfg.Results.TTLSeconds)*time.Second)
+	}
+	// Folga além do orçamento da consulta para gravar resultado e status do job.
+	if err := rabbitClient.Consume(ctx, asyncTimeout+time.Minute, processor.Handle); err != nil {
 		logger.Fatal().Err(err).Msg("failed to start consumer")
 	}
 	logger.Info().Msg("async job consumer started")
@@ -80,3 +130,21 @@ func main() {
 		logger.Info().Msg("server stopped")
 	}
 }
+
+// newResultStore escolhe o adaptador de resultados de jobs conforme RESULT_STORE.
+func newResultStore(cfg config.Config, mongoClient *mongodriver.Client, logger zerolog.Logger) job.ResultStore {
+	maxBytes := int64(cfg.Results.MaxBytes)
+	switch cfg.Results.Store {
+	case "gridfs":
+		return mongo.NewResultStoreGridFS(mongoClient, cfg.Mongo.DBName, maxBytes)
+	case "disk":
+		store, err := localdisk.NewResultStore(cfg.Results.Dir, maxBytes)
+		if err != nil {
+			logger.Fatal().Err(err).Str("dir", cfg.Results.Dir).Msg("failed to initialize result store")
+		}
+		return store
+	default:
+		logger.Warn().Str("store", cfg.Results.Store).Msg("job results will not be persisted")
+		return nil
+	}
+}
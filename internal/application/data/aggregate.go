@@ -0,0 +1,151 @@
+package data
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"api-database/internal/domain"
+	"api-database/internal/domain/datasource"
+	"api-database/internal/domain/query"
+)
+
+// AggregateField define uma coluna calculada, ex.: {"function": "sum", "column": "amount", "alias": "total"}.
+// Sem column, apenas count é aceito (COUNT(*)). Sem alias, usa "função" ou "função_coluna".
+type AggregateField struct {
+	Function string `json:"function"`
+	Column   string `json:"column,omitempty"`
+	Alias    string `json:"alias,omitempty"`
+}
+
+var aggregateFunctions = map[string]query.AggregateFunc{
+	"count": query.AggCount,
+	"sum":   query.AggSum,
+	"avg":   query.AggAvg,
+	"min":   query.AggMin,
+	"max":   query.AggMax,
+}
+
+// aggregated indica se a requisição pede agregação em vez de linhas.
+func (r QueryRequest) aggregated() bool {
+	return len(r.GroupBy) > 0 || len(r.Aggregates) > 0
+}
+
+// aliases lista os nomes gerados pelos agregados (não são colunas da tabela).
+func (r QueryRequest) aliases() []string {
+	aliases := make([]string, len(r.Aggregates))
+	for i, a := range r.Aggregates {
+		aliases[i] = a.alias()
+	}
+	return aliases
+}
+
+func (a AggregateField) alias() string {
+	if a.Alias != "" {
+		return a.Alias
+	}
+	fn := strings.ToLower(a.Function)
+	if a.Column == "" || a.Column == "*" {
+		return fn
+	}
+	return fn + "_" + a.Column
+}
+
+// aggregation é a parte agrupada da consulta já validada.
+type aggregation struct {
+	groupBy    []string
+	aggregates []query.Aggregate
+	having     *query.Filter
+}
+
+// buildAggregation valida groupBy, aggregates e having com as mesmas regras de fields
+// (nome de coluna e blockedColumns). orderBy e having só podem usar colunas de groupBy
+// ou aliases.
+func buildAggregation(table string, ds *datasource.DataSource, req QueryRequest) (*aggregation, error) {
+	if !req.aggregated() {
+		if !req.Having.isEmpty() {
+			return nil, invalidInput("having requires groupBy or aggregates")
+		}
+		return nil, nil
+	}
+	if len(req.Fields) > 0 {
+		return nil, invalidInput("fields cannot be combined with groupBy/aggregates; group columns and aliases are returned")
+	}
+	if req.Cursor != nil {
+		return nil, invalidInput("cursor pagination is not supported for aggregations")
+	}
+
+	agg := &aggregation{}
+	outputs := make(map[string]bool)
+	for _, col := range req.GroupBy {
+		if err := checkAggregateColumn(table, ds, col); err != nil {
+			return nil, err
+		}
+		if outputs[col] {
+			return nil, invalidInput(fmt.Sprintf("duplicate groupBy column: %s", col))
+		}
+		outputs[col] = true
+		agg.groupBy = append(agg.groupBy, col)
+	}
+
+	for _, a := range req.Aggregates {
+		fn, ok := aggregateFunctions[strings.ToLower(a.Function)]
+		if !ok {
+			return nil, invalidInput(fmt.Sprintf("unsupported aggregate function: %s", a.Function)).
+				WithDetails(map[string]interface{}{"supported": []string{"count", "sum", "avg", "min", "max"}})
+		}
+		column := a.Column
+		if column == "*" {
+			column = ""
+		}
+		if column == "" && fn != query.AggCount {
+			return nil, invalidInput(fmt.Sprintf("aggregate %s requires a column", a.Function))
+		}
+		if column != "" {
+			if err := checkAggregateColumn(table, ds, column); err != nil {
+				return nil, err
+			}
+		}
+		alias := a.alias()
+		if !columnRegex.MatchString(alias) {
+			return nil, invalidInput(fmt.Sprintf("invalid alias: %s", alias))
+		}
+		// Um alias com nome de coluna bloqueada seria removido da resposta.
+		if outputs[alias] || isColumnBlocked(table, alias, ds.BlockedColumns) {
+			return nil, invalidInput(fmt.Sprintf("duplicate or reserved alias: %s", alias))
+		}
+		outputs[alias] = true
+		agg.aggregates = append(agg.aggregates, query.Aggregate{Func: fn, Column: column, Alias: alias})
+	}
+
+	for _, o := range req.OrderBy {
+		if !outputs[o.Field] {
+			return nil, invalidInput(fmt.Sprintf("orderBy must use a groupBy column or alias: %s", o.Field))
+		}
+	}
+	for _, col := range req.Having.columns() {
+		if !outputs[col] {
+			return nil, invalidInput(fmt.Sprintf("having must use a groupBy column or alias: %s", col))
+		}
+	}
+	having, err := buildFilter(req.Having, ds.Capabilities.MaxDepthLimit)
+	if err != nil {
+		return nil, err
+	}
+	agg.having = having
+	return agg, nil
+}
+
+func checkAggregateColumn(table string, ds *datasource.DataSource, col string) error {
+	if !columnRegex.MatchString(col) {
+		return invalidInput(fmt.Sprintf("invalid column name: %s", col))
+	}
+	if isColumnBlocked(table, col, ds.BlockedColumns) {
+		return domain.NewAppError(domain.ErrColumnBlocked, fmt.Sprintf("column blocked: %s", col), http.StatusForbidden)
+	}
+	return nil
+}
+
+func invalidInput(message string) *domain.AppError {
+	return domain.NewAppError(domain.ErrInvalidInput, message, http.StatusBadRequest)
+}
@@ -0,0 +1,91 @@
+package data
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"api-database/internal/domain"
+	"api-database/internal/domain/apikey"
+	"api-database/internal/domain/datasource"
+)
+
+func TestQueryTable_Aggregation(t *testing.T) {
+	ds := &datasource.DataSource{Name: "main", Type: "postgres", Limits: datasource.Limits{MaxRows: 50}}
+	svc, conn := newTestService(ds, nil)
+
+	var req QueryRequest
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"groupBy": ["role"],
+		"aggregates": [{"function": "count"}, {"function": "MAX", "column": "age", "alias": "oldest"}],
+		"having": {"count": {"$gt": 1}},
+		"orderBy": [{"field": "oldest", "direction": "desc"}],
+		"limit": 1000
+	}`), &req))
+
+	_, err := svc.QueryTable(context.Background(), adminKey, "main", "User", req)
+	require.NoError(t, err)
+	assert.Equal(t, `SELECT "role", COUNT(*) AS "count", MAX("age") AS "oldest" FROM "User" GROUP BY "role"`+
+		` HAVING COUNT(*) > $1 ORDER BY "oldest" DESC LIMIT $2 OFFSET $3`, conn.statements[0].Text)
+	assert.Equal(t, []any{float64(1), 50, 0}, conn.statements[0].Args)
+}
+
+func TestQueryTable_AggregationValidation(t *testing.T) {
+	ds := &datasource.DataSource{Name: "main", Type: "postgres", BlockedColumns: []string{"User.salary"}}
+	svc, conn := newTestService(ds, nil)
+	cursor := ""
+
+	cases := map[string]struct {
+		req  QueryRequest
+		code domain.ErrorCode
+	}{
+		"unknown function":    {QueryRequest{Aggregates: []AggregateField{{Function: "median", Column: "age"}}}, domain.ErrInvalidInput},
+		"sum without column":  {QueryRequest{Aggregates: []AggregateField{{Function: "sum"}}}, domain.ErrInvalidInput},
+		"invalid group col":   {QueryRequest{GroupBy: []string{"role;drop"}}, domain.ErrInvalidInput},
+		"blocked group col":   {QueryRequest{GroupBy: []string{"salary"}}, domain.ErrColumnBlocked},
+		"blocked agg column":  {QueryRequest{Aggregates: []AggregateField{{Function: "avg", Column: "salary"}}}, domain.ErrColumnBlocked},
+		"duplicate alias":     {QueryRequest{GroupBy: []string{"role"}, Aggregates: []AggregateField{{Function: "count", Alias: "role"}}}, domain.ErrInvalidInput},
+		"order by raw column": {QueryRequest{GroupBy: []string{"role"}, OrderBy: []OrderField{{Field: "age"}}}, domain.ErrInvalidInput},
+		"having raw column":   {QueryRequest{GroupBy: []string{"role"}, Having: Filter{Columns: map[string]FilterField{"age": {Gt: 1}}}}, domain.ErrInvalidInput},
+		"having alone":        {QueryRequest{Having: Filter{Columns: map[string]FilterField{"count": {Gt: 1}}}}, domain.ErrInvalidInput},
+		"with fields":         {QueryRequest{GroupBy: []string{"role"}, Fields: []string{"role"}}, domain.ErrInvalidInput},
+		"with cursor":         {QueryRequest{GroupBy: []string{"role"}, Cursor: &cursor}, domain.ErrInvalidInput},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := svc.QueryTable(context.Background(), adminKey, "main", "User", tc.req)
+			appErr, ok := err.(*domain.AppError)
+			require.True(t, ok, "got %v", err)
+			assert.Equal(t, tc.code, appErr.Code)
+		})
+	}
+	assert.Empty(t, conn.statements)
+}
+
+func TestAuthorizeQuery_Aggregation(t *testing.T) {
+	ak := &apikey.APIKey{Permissions: []apikey.Permission{{Resource: "racehub.User.role", Level: apikey.LevelColumn}}}
+
+	// Aliases não exigem permissão; colunas agregadas sim.
+	assert.NoError(t, authorizeQuery(ak, "racehub", "User", QueryRequest{
+		GroupBy:    []string{"role"},
+		Aggregates: []AggregateField{{Function: "count", Alias: "n"}},
+		OrderBy:    []OrderField{{Field: "n", Direction: "desc"}},
+		Having:     Filter{Columns: map[string]FilterField{"n": {Gt: 1}}},
+	}))
+	assertForbidden(t, authorizeQuery(ak, "racehub", "User", QueryRequest{
+		GroupBy:    []string{"role"},
+		Aggregates: []AggregateField{{Function: "sum", Column: "salary"}},
+	}))
+}
+
+func TestHashQueryRequest_Aggregation(t *testing.T) {
+	plain, err := HashQueryRequest(QueryRequest{})
+	require.NoError(t, err)
+	grouped, err := HashQueryRequest(QueryRequest{GroupBy: []string{"role"}})
+	require.NoError(t, err)
+	assert.NotEqual(t, plain, grouped)
+	assert.NotEqual(t, QueryShape(QueryRequest{}), QueryShape(QueryRequest{Aggregates: []AggregateField{{Function: "count"}}}))
+}
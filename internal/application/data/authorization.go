@@ -0,0 +1,91 @@
+package data
+
+import (
+	"fmt"
+	"net/http"
+
+	"api-database/internal/domain"
+	"api-database/internal/domain/apikey"
+)
+
+// Authorize valida se a chave pode consultar a tabela e todas as colunas referenciadas.
+// Hierarquia: "source" (database) → "source.table" (table) → "source.table.column" (column).
+func (s *QueryService) Authorize(ak *apikey.APIKey, sourceName, table string, req QueryRequest) error {
+	if s.skipAuthorization {
+		return nil
+	}
+	return authorizeQuery(ak, sourceName, table, req)
+}
+
+func authorizeQuery(ak *apikey.APIKey, sourceName, table string, req QueryRequest) error {
+	if ak == nil {
+		return forbidden("api key required", nil)
+	}
+	if !ak.HasPermissionWithin(sourceName) {
+		return forbidden(fmt.Sprintf("access denied to datasource: %s", sourceName), map[string]interface{}{
+			"resource": sourceName,
+		})
+	}
+
+	tableResource := sourceName + "." + table
+	if !ak.HasPermissionWithin(tableResource) {
+		return forbidden(fmt.Sprintf("access denied to table: %s", table), map[string]interface{}{
+			"resource": tableResource,
+		})
+	}
+
+	// Com permissão apenas em nível de coluna, SELECT * exporia colunas não autorizadas.
+	if len(req.Fields) == 0 && !req.aggregated() && !ak.HasPermission(tableResource) {
+		return forbidden("fields must be listed explicitly: key only has column-level access", map[string]interface{}{
+			"resource": tableResource,
+		})
+	}
+
+	for _, col := range referencedColumns(req) {
+		columnResource := tableResource + "." + col
+		if !ak.HasPermission(columnResource) {
+			return forbidden(fmt.Sprintf("access denied to column: %s", col), map[string]interface{}{
+				"resource": columnResource,
+			})
+		}
+	}
+	return nil
+}
+
+// referencedColumns lista as colunas usadas em fields, filter, orderBy e nas agregações.
+// Aliases de agregados (em orderBy/having) não são colunas e ficam de fora.
+func referencedColumns(req QueryRequest) []string {
+	aliases := make(map[string]bool)
+	for _, alias := range req.aliases() {
+		aliases[alias] = true
+	}
+
+	cols := make([]string, 0, len(req.Fields)+len(req.OrderBy)+len(req.GroupBy))
+	cols = append(cols, req.Fields...)
+	cols = append(cols, req.Filter.columns()...)
+	cols = append(cols, req.GroupBy...)
+	for _, a := range req.Aggregates {
+		if a.Column != "" && a.Column != "*" {
+			cols = append(cols, a.Column)
+		}
+	}
+	for _, o := range req.OrderBy {
+		if !aliases[o.Field] {
+			cols = append(cols, o.Field)
+		}
+	}
+	for _, col := range req.Having.columns() {
+		if !aliases[col] {
+			cols = append(cols, col)
+		}
+	}
+	return cols
+}
+
+func forbidden(message string, details map[string]interface{}) *domain.AppError {
+	err := domain.NewAppError(domain.ErrForbidden, message, http.StatusForbidden)
+	if details != nil {
+		err = err.WithDetails(details)
+	}
+	return err
+}
@@ -0,0 +1,58 @@
+package data
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"api-database/internal/domain"
+	"api-database/internal/domain/apikey"
+)
+
+func assertForbidden(t *testing.T, err error) {
+	t.Helper()
+	appErr, ok := err.(*domain.AppError)
+	if assert.True(t, ok) {
+		assert.Equal(t, domain.ErrForbidden, appErr.Code)
+	}
+}
+
+func TestAuthorizeQuery_NoKey(t *testing.T) {
+	assertForbidden(t, authorizeQuery(nil, "racehub", "User", QueryRequest{}))
+}
+
+func TestAuthorizeQuery_TableLevel(t *testing.T) {
+	ak := &apikey.APIKey{Permissions: []apikey.Permission{{Resource: "racehub.User", Level: "table"}}}
+
+	assert.NoError(t, authorizeQuery(ak, "racehub", "User", QueryRequest{}))
+	assertForbidden(t, authorizeQuery(ak, "racehub", "Team", QueryRequest{}))
+	assertForbidden(t, authorizeQuery(ak, "other", "User", QueryRequest{}))
+}
+
+func TestAuthorizeQuery_ColumnLevel(t *testing.T) {
+	ak := &apikey.APIKey{Permissions: []apikey.Permission{
+		{Resource: "racehub.User.id", Level: "column"},
+		{Resource: "racehub.User.email", Level: "column"},
+	}}
+
+	// SELECT * não é permitido com acesso apenas a colunas
+	assertForbidden(t, authorizeQuery(ak, "racehub", "User", QueryRequest{}))
+	assert.NoError(t, authorizeQuery(ak, "racehub", "User", QueryRequest{Fields: []string{"id", "email"}}))
+	assertForbidden(t, authorizeQuery(ak, "racehub", "User", QueryRequest{Fields: []string{"id", "passwordHash"}}))
+	assertForbidden(t, authorizeQuery(ak, "racehub", "User", QueryRequest{
+		Fields: []string{"id"},
+		Filter: Filter{Columns: map[string]FilterField{"role": {Eq: "PILOT"}}},
+	}))
+	assertForbidden(t, authorizeQuery(ak, "racehub", "User", QueryRequest{
+		Fields:  []string{"id"},
+		OrderBy: []OrderField{{Field: "createdAt"}},
+	}))
+	// Colunas dentro de grupos também são verificadas
+	assertForbidden(t, authorizeQuery(ak, "racehub", "User", QueryRequest{
+		Fields: []string{"id"},
+		Filter: Filter{Or: []Filter{
+			{Columns: map[string]FilterField{"email": {Eq: "a@b.c"}}},
+			{Not: &Filter{Columns: map[string]FilterField{"role": {Eq: "PILOT"}}}},
+		}},
+	}))
+}
@@ -0,0 +1,303 @@
+package data
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"api-database/internal/domain"
+	"api-database/internal/domain/apikey"
+	"api-database/internal/domain/datasource"
+	"api-database/internal/domain/query"
+)
+
+// maxBatchOperations limita o tamanho de uma transação de POST /batch.
+const maxBatchOperations = 50
+
+// batchQuery é a operação de leitura do batch; as demais são WriteOp.
+const batchQuery = "query"
+
+// isolationLevels aceitos em BatchRequest.Isolation (os mesmos de datasource.TxOptions).
+var isolationLevels = map[string]bool{
+	"":                 true,
+	"read uncommitted": true,
+	"read committed":   true,
+	"repeatable read":  true,
+	"serializable":     true,
+}
+
+// BatchRequest executa Operations em ordem, em uma única transação.
+type BatchRequest struct {
+	Isolation  string           `json:"isolation"`
+	ReadOnly   bool             `json:"readOnly"`
+	Operations []BatchOperation `json:"operations"`
+}
+
+// BatchOperation é uma leitura (op "query", Request no formato de QueryRequest) ou uma
+// escrita (insert, update, delete; Request no formato de WriteRequest).
+type BatchOperation struct {
+	Op      string          `json:"op"`
+	Table   string          `json:"table"`
+	Request json.RawMessage `json:"request"`
+}
+
+// BatchResponse traz um resultado por operação, na ordem do pedido.
+type BatchResponse struct {
+	Results  []BatchResult `json:"results"`
+	Metadata BatchMeta     `json:"metadata"`
+}
+
+// BatchResult é o resultado de uma operação: linhas lidas ou afetadas (RETURNING).
+type BatchResult struct {
+	Op    string           `json:"op"`
+	Table string           `json:"table"`
+	Data  []map[string]any `json:"data"`
+	Rows  int              `json:"rows"`
+	Total *int64           `json:"total,omitempty"`
+}
+
+// BatchMeta resume a transação.
+type BatchMeta struct {
+	Operations int   `json:"operations"`
+	TookMs     int64 `json:"tookMs"`
+}
+
+// ExecuteBatch valida todas as operações e as executa em uma transação com o isolamento e o
+// modo pedidos: qualquer erro desfaz o batch inteiro e informa o índice da operação em
+// details.operation. Exige Capabilities.SupportsTransactions no datasource.
+func (s *QueryService) ExecuteBatch(ctx context.Context, ak *apikey.APIKey, sourceName string, req BatchRequest) (_ *BatchResponse, err error) {
+	if len(req.Operations) == 0 {
+		return nil, invalidInput("batch requires at least one operation")
+	}
+	if len(req.Operations) > maxBatchOperations {
+		return nil, invalidInput(fmt.Sprintf("batch accepts at most %d operations", maxBatchOperations))
+	}
+	isolation := strings.ToLower(strings.TrimSpace(req.Isolation))
+	if !isolationLevels[isolation] {
+		return nil, invalidInput(fmt.Sprintf("unsupported isolation level: %s", req.Isolation))
+	}
+
+	ds, err := s.repo.GetByName(ctx, sourceName)
+	if err != nil {
+		return nil, domain.NewAppError(domain.ErrDataSourceNotFound, "datasource not found", http.StatusNotFound)
+	}
+	if !ds.Capabilities.SupportsTransactions {
+		return nil, domain.NewAppError(domain.ErrUnsupportedType, fmt.Sprintf("datasource does not support transactions: %s", sourceName), http.StatusBadRequest)
+	}
+	translator, ok := s.connectors.Translator(ds.Type)
+	if !ok {
+		return nil, domain.NewAppError(domain.ErrUnsupportedType, fmt.Sprintf("unsupported datasource type: %s", ds.Type), http.StatusBadRequest)
+	}
+
+	timeout := s.timeout(ctx, ds)
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	defer func() {
+		if err != nil {
+			err = timeoutError(ctx, err, timeout)
+		}
+	}()
+
+	plans := make([]batchPlan, len(req.Operations))
+	for i, op := range req.Operations {
+		if plans[i], err = s.planBatchOperation(ctx, ak, ds, translator, sourceName, op, req.ReadOnly); err != nil {
+			return nil, atOperation(ctx, err, i)
+		}
+	}
+
+	conn, release, err := s.connectors.Connector(ctx, ds)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	start := time.Now()
+	results := make([]BatchResult, len(plans))
+	err = conn.Transaction(ctx, datasource.TxOptions{Isolation: isolation, ReadOnly: req.ReadOnly}, func(tx datasource.Executor) error {
+		for i, plan := range plans {
+			result, err := plan.run(ctx, tx)
+			if err != nil {
+				return atOperation(ctx, timeoutError(ctx, err, timeout), i)
+			}
+			results[i] = result
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, plan := range plans {
+		if plan.write != nil {
+			s.invalidate(ds)
+			break
+		}
+	}
+
+	return &BatchResponse{
+		Results:  results,
+		Metadata: BatchMeta{Operations: len(results), TookMs: time.Since(start).Milliseconds()},
+	}, nil
+}
+
+// batchPlan é uma operação validada: leitura (stmt/count) ou escrita (write).
+type batchPlan struct {
+	op    string
+	table string
+	ds    *datasource.DataSource
+	stmt  query.Statement
+	count *query.Statement
+	write *writePlan
+}
+
+func (s *QueryService) planBatchOperation(ctx context.Context, ak *apikey.APIKey, ds *datasource.DataSource, translator query.Translator, sourceName string, op BatchOperation, readOnly bool) (batchPlan, error) {
+	plan := batchPlan{op: op.Op, table: op.Table, ds: ds}
+	if !tableNameRegex.MatchString(op.Table) {
+		return plan, domain.NewAppError(domain.ErrInvalidTable, "invalid table name", http.StatusBadRequest)
+	}
+	body := op.Request
+	if len(body) == 0 {
+		body = json.RawMessage("{}")
+	}
+
+	if op.Op == batchQuery {
+		var req QueryRequest
+		if err := json.Unmarshal(body, &req); err != nil {
+			return plan, decodeFailure(err)
+		}
+		err := s.planBatchRead(ctx, ak, ds, translator, sourceName, op.Table, req, &plan)
+		return plan, err
+	}
+
+	switch WriteOp(op.Op) {
+	case WriteInsert, WriteUpdate, WriteDelete:
+	default:
+		return plan, invalidInput(fmt.Sprintf("unsupported batch operation: %s", op.Op))
+	}
+	if readOnly {
+		return plan, invalidInput(fmt.Sprintf("%s is not allowed in a read-only batch", op.Op))
+	}
+	var req WriteRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		return plan, decodeFailure(err)
+	}
+	if req.Schema != "" && !tableNameRegex.MatchString(req.Schema) {
+		return plan, domain.NewAppError(domain.ErrInvalidSchema, "invalid schema name", http.StatusBadRequest)
+	}
+	if err := s.authorizeWrite(ak, sourceName, op.Table, WriteOp(op.Op), req); err != nil {
+		return plan, err
+	}
+	write, err := s.planWrite(ctx, ak, ds, translator, WriteOp(op.Op), op.Table, req)
+	plan.write = write
+	return plan, err
+}
+
+// planBatchRead aplica as validações de QueryTable; cursor e include não se aplicam no batch.
+func (s *QueryService) planBatchRead(ctx context.Context, ak *apikey.APIKey, ds *datasource.DataSource, translator query.Translator, sourceName, table string, req QueryRequest, plan *batchPlan) error {
+	if req.Schema != "" && !tableNameRegex.MatchString(req.Schema) {
+		return domain.NewAppError(domain.ErrInvalidSchema, "invalid schema name", http.StatusBadRequest)
+	}
+	if req.Cursor != nil || len(req.Include) > 0 {
+		return invalidInput("batch queries do not support cursor or include")
+	}
+	if err := s.Authorize(ak, sourceName, table, req); err != nil {
+		return err
+	}
+	if err := checkRequestColumns(table, ds, req); err != nil {
+		return err
+	}
+	agg, err := buildAggregation(table, ds, req)
+	if err != nil {
+		return err
+	}
+	if err := s.validateColumns(ctx, ak, ds, table, req, nil); err != nil {
+		return err
+	}
+	where, err := buildFilter(req.Filter, ds.Capabilities.MaxDepthLimit)
+	if err != nil {
+		return err
+	}
+
+	offset := req.Offset
+	if offset < 0 {
+		offset = 0
+	}
+	spec := query.Select{
+		Schema:  req.Schema,
+		Table:   table,
+		Columns: req.Fields,
+		Where:   where,
+		OrderBy: buildOrder(req.OrderBy),
+		Limit:   pageLimit(ds, req.Limit),
+		Offset:  offset,
+	}
+	if agg != nil {
+		spec.GroupBy = agg.groupBy
+		spec.Aggregates = agg.aggregates
+		spec.Having = agg.having
+	}
+	if plan.stmt, err = translator.Select(spec); err != nil {
+		return invalidInput(err.Error())
+	}
+	if req.CountTotal {
+		count, err := translator.Count(spec)
+		if err != nil {
+			return invalidInput(err.Error())
+		}
+		plan.count = &count
+	}
+	return nil
+}
+
+func (p batchPlan) run(ctx context.Context, tx datasource.Executor) (BatchResult, error) {
+	result := BatchResult{Op: p.op, Table: p.table}
+	if p.write != nil {
+		rows, err := p.write.run(ctx, tx)
+		if err != nil {
+			return result, err
+		}
+		result.Data = p.write.result(rows)
+		result.Rows = len(rows)
+		return result, nil
+	}
+
+	rows, err := tx.Query(ctx, p.stmt.Text, p.stmt.Args...)
+	if err != nil {
+		return result, err
+	}
+	if rows == nil {
+		rows = []map[string]any{}
+	}
+	stripBlocked(rows, p.table, p.ds.BlockedColumns)
+	result.Data = rows
+	result.Rows = len(rows)
+	if p.count != nil {
+		totalRows, err := tx.Query(ctx, p.count.Text, p.count.Args...)
+		if err != nil {
+			return result, err
+		}
+		result.Total = countFromRows(totalRows)
+	}
+	return result, nil
+}
+
+// atOperation acrescenta o índice da operação aos detalhes do erro (ver ClientError).
+func atOperation(ctx context.Context, err error, index int) error {
+	appErr := ClientError(ctx, err)
+	details := map[string]interface{}{"operation": index}
+	for k, v := range appErr.Details {
+		details[k] = v
+	}
+	return domain.NewAppError(appErr.Code, appErr.Message, appErr.Status()).WithDetails(details).WithCause(appErr.Unwrap())
+}
+
+// decodeFailure preserva AppErrors gerados na decodificação (ex.: filtros inválidos).
+func decodeFailure(err error) error {
+	var appErr *domain.AppError
+	if errors.As(err, &appErr) {
+		return appErr
+	}
+	return invalidInput("invalid operation request")
+}
@@ -0,0 +1,102 @@
+package data
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"api-database/internal/domain"
+)
+
+func newBatchService(t *testing.T) *QueryService {
+	t.Helper()
+	svc := newSQLiteService(t, sqliteUsers...)
+	svc.repo.(*fakeRepo).sources["main"].Capabilities.SupportsTransactions = true
+	return svc
+}
+
+func batchOp(op, table, body string) BatchOperation {
+	return BatchOperation{Op: op, Table: table, Request: json.RawMessage(body)}
+}
+
+func TestExecuteBatch_SQLite(t *testing.T) {
+	svc := newBatchService(t)
+	ctx := context.Background()
+
+	resp, err := svc.ExecuteBatch(ctx, writeKey, "main", BatchRequest{Operations: []BatchOperation{
+		batchOp("insert", "User", `{"values": {"id": 4, "email": "d@example.com", "role": "CREW"}, "fields": ["id"]}`),
+		batchOp("update", "User", `{"values": {"role": "CREW"}, "filter": {"id": {"$eq": 1}}, "fields": ["id", "role"]}`),
+		batchOp("query", "User", `{"filter": {"role": {"$eq": "CREW"}}, "orderBy": [{"field": "id"}], "countTotal": true}`),
+	}})
+	require.NoError(t, err)
+	require.Len(t, resp.Results, 3)
+	assert.Equal(t, 3, resp.Metadata.Operations)
+	assert.Equal(t, []map[string]any{{"id": int64(4)}}, resp.Results[0].Data)
+	assert.Equal(t, 1, resp.Results[1].Rows)
+
+	// A leitura enxerga as escritas anteriores da mesma transação
+	read := resp.Results[2]
+	assert.Equal(t, "query", read.Op)
+	require.Len(t, read.Data, 2)
+	assert.NotContains(t, read.Data[0], "passwordHash")
+	assert.Equal(t, int64(2), *read.Total)
+}
+
+func TestExecuteBatch_RollsBackOnFailure(t *testing.T) {
+	svc := newBatchService(t)
+	ctx := context.Background()
+
+	_, err := svc.ExecuteBatch(ctx, writeKey, "main", BatchRequest{Operations: []BatchOperation{
+		batchOp("delete", "User", `{"filter": {"id": {"$eq": 1}}}`),
+		// Chave primária duplicada: falha só na execução
+		batchOp("insert", "User", `{"values": {"id": 2, "email": "dup@example.com"}}`),
+	}})
+	require.Error(t, err)
+	appErr, ok := err.(*domain.AppError)
+	require.True(t, ok, "expected AppError, got %v", err)
+	assert.Equal(t, 1, appErr.Details["operation"])
+	// O erro do driver é classificado, mas a mensagem dele não chega ao cliente
+	assert.Equal(t, domain.ErrQueryFailed, appErr.Code)
+	assert.Equal(t, "query rejected by datasource", appErr.Message)
+	assert.Equal(t, 400, appErr.Status())
+	assert.NotContains(t, appErr.Message, "UNIQUE")
+
+	resp, err := svc.QueryTable(ctx, adminKey, "main", "User", QueryRequest{CountTotal: true})
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), *resp.Metadata.Total)
+}
+
+func TestExecuteBatch_Validation(t *testing.T) {
+	svc := newBatchService(t)
+	ctx := context.Background()
+	read := batchOp("query", "User", `{}`)
+	write := batchOp("delete", "User", `{"filter": {"id": {"$eq": 1}}}`)
+
+	_, err := svc.ExecuteBatch(ctx, writeKey, "main", BatchRequest{})
+	assertCode(t, err, domain.ErrInvalidInput)
+
+	_, err = svc.ExecuteBatch(ctx, writeKey, "main", BatchRequest{Isolation: "snapshot", Operations: []BatchOperation{read}})
+	assertCode(t, err, domain.ErrInvalidInput)
+
+	_, err = svc.ExecuteBatch(ctx, writeKey, "main", BatchRequest{ReadOnly: true, Operations: []BatchOperation{read, write}})
+	assertCode(t, err, domain.ErrInvalidInput)
+	assert.Equal(t, 1, err.(*domain.AppError).Details["operation"])
+
+	_, err = svc.ExecuteBatch(ctx, writeKey, "main", BatchRequest{Operations: []BatchOperation{batchOp("upsert", "User", `{}`)}})
+	assertCode(t, err, domain.ErrInvalidInput)
+
+	// Permissões são verificadas antes de abrir a transação
+	_, err = svc.ExecuteBatch(ctx, adminKey, "main", BatchRequest{Operations: []BatchOperation{read, write}})
+	assertForbidden(t, err)
+
+	resp, err := svc.ExecuteBatch(ctx, writeKey, "main", BatchRequest{Isolation: "Serializable", ReadOnly: true, Operations: []BatchOperation{read}})
+	require.NoError(t, err)
+	assert.Len(t, resp.Results[0].Data, 3)
+
+	svc.repo.(*fakeRepo).sources["main"].Capabilities.SupportsTransactions = false
+	_, err = svc.ExecuteBatch(ctx, writeKey, "main", BatchRequest{Operations: []BatchOperation{read}})
+	assertCode(t, err, domain.ErrUnsupportedType)
+}
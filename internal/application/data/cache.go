@@ -0,0 +1,66 @@
+package data
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"api-database/internal/domain/cache"
+	"api-database/internal/domain/datasource"
+)
+
+// Valores de QueryResponse.Cache (espelhados no header X-Cache).
+const (
+	CacheHit  = "HIT"
+	CacheMiss = "MISS"
+)
+
+// WithCache habilita o cache de resultados em frente a QueryTable.
+func (s *QueryService) WithCache(c cache.Cache) *QueryService {
+	s.cache = c
+	return s
+}
+
+// cacheKey combina source, table, DataSource.Version e o hash do payload; a versão
+// garante invalidação automática quando o datasource muda.
+func cacheKey(ds *datasource.DataSource, table string, req QueryRequest) (key, group string, ok bool) {
+	hash, err := HashQueryRequest(req)
+	if err != nil {
+		return "", "", false
+	}
+	group = ds.Name + "/" + table
+	return fmt.Sprintf("%s/v%d/%s", group, ds.Version, hash), group, true
+}
+
+// cachedResponse devolve a resposta em cache com Metadata.TookMs da própria leitura: o tempo
+// da execução original inflaria o p95 usado no roteamento automático para async.
+func (s *QueryService) cachedResponse(key string) (*QueryResponse, bool) {
+	if s.cache == nil {
+		return nil, false
+	}
+	start := time.Now()
+	v, ok := s.cache.Get(key)
+	if !ok {
+		return nil, false
+	}
+	cached, ok := v.(*QueryResponse)
+	if !ok {
+		return nil, false
+	}
+	// Cópia rasa: o valor em cache é compartilhado e não deve ser alterado.
+	hit := *cached
+	hit.Cache = CacheHit
+	hit.Metadata.TookMs = time.Since(start).Milliseconds()
+	return &hit, true
+}
+
+func (s *QueryService) storeResponse(key, group string, resp *QueryResponse) {
+	if s.cache == nil {
+		return
+	}
+	b, err := json.Marshal(resp)
+	if err != nil {
+		return
+	}
+	s.cache.Set(key, group, resp, int64(len(b)))
+}
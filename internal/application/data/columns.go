@@ -0,0 +1,239 @@
+package data
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"api-database/internal/domain"
+	"api-database/internal/domain/apikey"
+	"api-database/internal/domain/datasource"
+	"api-database/internal/domain/query"
+	"api-database/internal/domain/schema"
+)
+
+// validateColumns confere as colunas referenciadas pela consulta (e pelos includes) contra
+// o catálogo e o tipo dos valores de filtro contra o tipo de cada coluna, antes de gerar SQL.
+// Datasources sem Inspector (MongoDB) não têm schema fixo e não são validados.
+func (s *QueryService) validateColumns(ctx context.Context, ak *apikey.APIKey, ds *datasource.DataSource, table string, req QueryRequest, ks *keyset) error {
+	if _, ok := s.connectors.Inspector(ds.Type); !ok {
+		return nil
+	}
+	cols := referencedColumns(req)
+	if ks != nil {
+		for _, o := range ks.order {
+			cols = append(cols, o.Column)
+		}
+	}
+	desc, err := s.describeColumns(ctx, ak, ds, req.Schema, table, cols)
+	if err != nil {
+		return err
+	}
+	if ks != nil {
+		ks.markNullable(desc)
+	}
+
+	aliases := make(map[string]bool)
+	for _, alias := range req.aliases() {
+		aliases[alias] = true
+	}
+	if err := checkFilterTypes(desc, req.Filter, nil); err != nil {
+		return err
+	}
+	if err := checkFilterTypes(desc, req.Having, aliases); err != nil {
+		return err
+	}
+	return s.validateIncludeColumns(ctx, ak, ds, req.Schema, req.Include)
+}
+
+func (s *QueryService) validateIncludeColumns(ctx context.Context, ak *apikey.APIKey, ds *datasource.DataSource, schemaName string, includes []IncludeField) error {
+	for _, inc := range includes {
+		if _, err := s.describeColumns(ctx, ak, ds, schemaName, inc.Table, inc.Fields); err != nil {
+			return err
+		}
+		if err := s.validateIncludeColumns(ctx, ak, ds, schemaName, inc.Include); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// schemaRefreshInterval é o intervalo mínimo entre releituras do catálogo de uma tabela
+// provocadas por colunas desconhecidas.
+const schemaRefreshInterval = 10 * time.Second
+
+// describeColumns descreve a tabela e confere cols. Uma coluna ausente da descrição em cache
+// (ex.: adicionada por migração sem mudar DataSource.Version) provoca uma nova leitura do
+// catálogo antes de UNKNOWN_COLUMN.
+func (s *QueryService) describeColumns(ctx context.Context, ak *apikey.APIKey, ds *datasource.DataSource, schemaName, table string, cols []string) (*schema.Table, error) {
+	desc, err := s.describeExisting(ctx, ds, schemaName, table)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkColumns(ak, ds, desc, cols); err == nil || !s.schemas.expireTable(ds, schemaName, table, schemaRefreshInterval) {
+		return desc, err
+	}
+	if desc, err = s.describeExisting(ctx, ds, schemaName, table); err != nil {
+		return nil, err
+	}
+	return desc, s.checkColumns(ak, ds, desc, cols)
+}
+
+// describeExisting é describe com erro NOT_FOUND para tabelas ausentes do catálogo.
+func (s *QueryService) describeExisting(ctx context.Context, ds *datasource.DataSource, schemaName, table string) (*schema.Table, error) {
+	desc, err := s.describe(ctx, ds, schemaName, table)
+	if err != nil {
+		return nil, err
+	}
+	if desc == nil {
+		return nil, domain.NewAppError(domain.ErrNotFound, fmt.Sprintf("table not found: %s", table), http.StatusNotFound)
+	}
+	return desc, nil
+}
+
+// checkColumns retorna UNKNOWN_COLUMN para a primeira coluna inexistente, listando apenas
+// as colunas que a chave pode ver.
+func (s *QueryService) checkColumns(ak *apikey.APIKey, ds *datasource.DataSource, desc *schema.Table, cols []string) error {
+	for _, col := range cols {
+		if _, ok := desc.Column(col); ok {
+			continue
+		}
+		visible := s.visibleTable(ak, ds, desc).Columns
+		valid := make([]string, len(visible))
+		for i, c := range visible {
+			valid[i] = c.Name
+		}
+		return domain.NewAppError(domain.ErrUnknownColumn, fmt.Sprintf("unknown column: %s.%s", desc.Name, col), http.StatusBadRequest).
+			WithDetails(map[string]interface{}{"table": desc.Name, "column": col, "valid": valid})
+	}
+	return nil
+}
+
+// checkFilterTypes percorre a árvore de filtros; colunas em skip (aliases) são ignoradas.
+func checkFilterTypes(desc *schema.Table, f Filter, skip map[string]bool) error {
+	for col, field := range f.Columns {
+		column, ok := desc.Column(col)
+		if !ok || skip[col] {
+			continue
+		}
+		kind := columnKindOf(column.Type)
+		for _, c := range field.conditions() {
+			if err := checkConditionType(column, kind, c); err != nil {
+				return err
+			}
+		}
+	}
+	for _, g := range f.groups() {
+		if err := checkFilterTypes(desc, g, skip); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// columnKind agrupa os tipos nativos pelo tipo de valor JSON aceito nos filtros.
+type columnKind int
+
+const (
+	kindUnknown columnKind = iota // json, arrays, geometria...: sem verificação
+	kindInteger
+	kindDecimal
+	kindBoolean
+	kindText
+	kindTemporal
+)
+
+var columnKinds = map[string]columnKind{
+	"smallint": kindInteger, "integer": kindInteger, "int": kindInteger, "bigint": kindInteger,
+	"mediumint": kindInteger, "tinyint": kindInteger, "int2": kindInteger, "int4": kindInteger, "int8": kindInteger,
+	"serial": kindInteger, "smallserial": kindInteger, "bigserial": kindInteger,
+	"numeric": kindDecimal, "decimal": kindDecimal, "real": kindDecimal, "double": kindDecimal,
+	"double precision": kindDecimal, "float": kindDecimal, "float4": kindDecimal, "float8": kindDecimal,
+	"boolean": kindBoolean, "bool": kindBoolean,
+	"text": kindText, "varchar": kindText, "character varying": kindText, "character": kindText,
+	"char": kindText, "nchar": kindText, "nvarchar": kindText, "citext": kindText, "uuid": kindText,
+	"tinytext": kindText, "mediumtext": kindText, "longtext": kindText, "enum": kindText,
+	"date": kindTemporal, "time": kindTemporal, "datetime": kindTemporal, "timestamp": kindTemporal,
+	"timestamptz": kindTemporal, "timetz": kindTemporal,
+	"timestamp with time zone": kindTemporal, "timestamp without time zone": kindTemporal,
+	"time with time zone": kindTemporal, "time without time zone": kindTemporal,
+}
+
+// columnKindOf normaliza o tipo do catálogo (ex.: "varchar(255)", "int unsigned").
+// tinyint(1) é a convenção de booleano do MySQL.
+func columnKindOf(dbType string) columnKind {
+	t := strings.ToLower(strings.TrimSpace(dbType))
+	if t == "tinyint(1)" {
+		return kindBoolean
+	}
+	if i := strings.Index(t, "("); i >= 0 {
+		t = strings.TrimSpace(t[:i])
+	}
+	t = strings.TrimSpace(strings.TrimSuffix(strings.TrimSuffix(t, " unsigned"), " zerofill"))
+	return columnKinds[t]
+}
+
+func checkConditionType(column schema.Column, kind columnKind, c filterCondition) error {
+	if kind == kindUnknown || c.op == query.OpIsNull {
+		return nil
+	}
+	mismatch := func(value any) error {
+		return domain.NewAppError(domain.ErrInvalidInput,
+			fmt.Sprintf("invalid value for %s on %s: column type is %s", c.name, column.Name, column.Type), http.StatusBadRequest).
+			WithDetails(map[string]interface{}{"column": column.Name, "type": column.Type, "operator": c.name, "value": value})
+	}
+	if (c.op == query.OpLike || c.op == query.OpILike) && kind != kindText {
+		return mismatch(c.value)
+	}
+	values := []any{c.value}
+	if list, ok := c.value.([]any); ok {
+		values = list
+	}
+	for _, v := range values {
+		if !valueMatchesKind(v, kind) {
+			return mismatch(v)
+		}
+	}
+	return nil
+}
+
+func valueMatchesKind(v any, kind columnKind) bool {
+	if v == nil {
+		return true
+	}
+	switch val := v.(type) {
+	case float64, float32, int, int32, int64, json.Number:
+		// Booleanos em SQLite/MySQL costumam ser gravados como 0/1.
+		return kind == kindInteger || kind == kindDecimal || kind == kindBoolean
+	case bool:
+		return kind == kindBoolean
+	case string:
+		// Decimais e inteiros grandes são devolvidos como string (ex.: NUMERIC no Postgres);
+		// o cliente precisa poder reenviá-los nos filtros.
+		switch kind {
+		case kindText, kindTemporal:
+			return true
+		case kindInteger:
+			return isIntegerString(val)
+		case kindDecimal:
+			_, err := strconv.ParseFloat(val, 64)
+			return err == nil
+		}
+		return false
+	default:
+		return false
+	}
+}
+
+func isIntegerString(v string) bool {
+	if _, err := strconv.ParseInt(v, 10, 64); err == nil {
+		return true
+	}
+	// bigint unsigned (MySQL) passa do limite de int64.
+	_, err := strconv.ParseUint(v, 10, 64)
+	return err == nil
+}
@@ -0,0 +1,126 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"api-database/internal/domain"
+)
+
+func TestQueryTable_SQLiteUnknownColumn(t *testing.T) {
+	svc := newSQLiteService(t, sqliteUsers...)
+
+	_, err := svc.QueryTable(context.Background(), adminKey, "main", "User", QueryRequest{
+		Fields: []string{"id", "mail"},
+	})
+	appErr, ok := err.(*domain.AppError)
+	require.True(t, ok, "got %v", err)
+	assert.Equal(t, domain.ErrUnknownColumn, appErr.Code)
+	assert.Equal(t, "mail", appErr.Details["column"])
+	// Colunas bloqueadas não aparecem entre as válidas
+	assert.Equal(t, []string{"id", "email", "role", "active"}, appErr.Details["valid"])
+
+	_, err = svc.QueryTable(context.Background(), adminKey, "main", "User", QueryRequest{
+		Filter: Filter{Or: []Filter{{Columns: map[string]FilterField{"nickname": {Eq: "x"}}}}},
+	})
+	appErr, ok = err.(*domain.AppError)
+	require.True(t, ok, "got %v", err)
+	assert.Equal(t, domain.ErrUnknownColumn, appErr.Code)
+}
+
+func TestQueryTable_SQLiteFilterTypes(t *testing.T) {
+	svc := newSQLiteService(t, sqliteUsers...)
+	cases := map[string]struct {
+		filter FilterField
+		column string
+		valid  bool
+	}{
+		"string on integer":    {FilterField{Eq: "abc"}, "id", false},
+		"numeric string":       {FilterField{Eq: "1"}, "id", true},
+		"decimal string":       {FilterField{Eq: "1.5"}, "id", false},
+		"number on integer":    {FilterField{Eq: float64(1)}, "id", true},
+		"number list":          {FilterField{In: []any{float64(1), "2"}}, "id", true},
+		"mixed list":           {FilterField{In: []any{float64(1), "x"}}, "id", false},
+		"number on text":       {FilterField{Eq: float64(1)}, "email", false},
+		"like on integer":      {FilterField{Like: "1%"}, "id", false},
+		"like on text":         {FilterField{Like: "a%"}, "email", true},
+		"bool on boolean":      {FilterField{Eq: true}, "active", true},
+		"number on boolean":    {FilterField{Eq: float64(1)}, "active", true},
+		"isNull on any column": {FilterField{IsNull: true}, "id", true},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := svc.QueryTable(context.Background(), adminKey, "main", "User", QueryRequest{
+				Filter: Filter{Columns: map[string]FilterField{tc.column: tc.filter}},
+			})
+			if tc.valid {
+				assert.NoError(t, err)
+				return
+			}
+			assertInvalidInput(t, err)
+		})
+	}
+}
+
+func TestQueryTable_SQLiteColumnAddedAfterCache(t *testing.T) {
+	svc := newSQLiteService(t, sqliteUsers...)
+	ctx := context.Background()
+
+	_, err := svc.QueryTable(ctx, adminKey, "main", "User", QueryRequest{Fields: []string{"id"}})
+	require.NoError(t, err)
+
+	ds := svc.repo.(*fakeRepo).sources["main"]
+	db, err := sql.Open("sqlite", ds.Connection.Database)
+	require.NoError(t, err)
+	_, err = db.Exec(`ALTER TABLE "User" ADD COLUMN nickname TEXT`)
+	require.NoError(t, err)
+	require.NoError(t, db.Close())
+
+	// Dentro do intervalo mínimo a descrição em cache é mantida
+	_, err = svc.QueryTable(ctx, adminKey, "main", "User", QueryRequest{Fields: []string{"id", "nickname"}})
+	appErr, ok := err.(*domain.AppError)
+	require.True(t, ok, "got %v", err)
+	assert.Equal(t, domain.ErrUnknownColumn, appErr.Code)
+
+	svc.schemas.mu.Lock()
+	e := svc.schemas.entry(ds)
+	for key, cached := range e.defs {
+		cached.loadedAt = cached.loadedAt.Add(-schemaRefreshInterval)
+		e.defs[key] = cached
+	}
+	svc.schemas.mu.Unlock()
+
+	_, err = svc.QueryTable(ctx, adminKey, "main", "User", QueryRequest{Fields: []string{"id", "nickname"}})
+	assert.NoError(t, err)
+}
+
+func TestColumnKindOf(t *testing.T) {
+	cases := map[string]columnKind{
+		"integer":                  kindInteger,
+		"INTEGER":                  kindInteger,
+		"int unsigned":             kindInteger,
+		"bigint(20) unsigned":      kindInteger,
+		"numeric":                  kindDecimal,
+		"decimal(10,2)":            kindDecimal,
+		"double precision":         kindDecimal,
+		"tinyint(1)":               kindBoolean,
+		"boolean":                  kindBoolean,
+		"character varying":        kindText,
+		"varchar(255)":             kindText,
+		"enum('a','b')":            kindText,
+		"uuid":                     kindText,
+		"timestamp with time zone": kindTemporal,
+		"datetime":                 kindTemporal,
+		"jsonb":                    kindUnknown,
+		"_int4":                    kindUnknown,
+		"interval":                 kindUnknown,
+		"":                         kindUnknown,
+	}
+	for dbType, want := range cases {
+		assert.Equal(t, want, columnKindOf(dbType), dbType)
+	}
+}
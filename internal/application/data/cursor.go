@@ -0,0 +1,211 @@
+package data
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"api-database/internal/domain"
+	"api-database/internal/domain/apikey"
+	"api-database/internal/domain/datasource"
+	"api-database/internal/domain/query"
+	"api-database/internal/domain/schema"
+)
+
+// WithCursorSecret define a chave HMAC usada para assinar cursores. Sem ela, uma chave
+// aleatória é gerada no start e cursores não sobrevivem a restarts nem valem entre réplicas
+// (por isso CURSOR_SECRET é exigido fora de desenvolvimento).
+func (s *QueryService) WithCursorSecret(secret []byte) *QueryService {
+	s.cursorSecret = secret
+	return s
+}
+
+func randomSecret() []byte {
+	secret := make([]byte, 32)
+	_, _ = rand.Read(secret)
+	return secret
+}
+
+// cursorPayload é o conteúdo assinado do cursor.
+type cursorPayload struct {
+	Query  string `json:"q"` // vincula o cursor à consulta (source, table, filtro, ordenação)
+	Values []any  `json:"v"` // valores da última linha, alinhados com a ordenação
+}
+
+// keyset descreve a paginação por cursor de uma consulta.
+type keyset struct {
+	order   []query.Order // OrderBy da requisição + chave de desempate
+	after   []any         // posição da página anterior (nil na primeira página)
+	extra   []string      // colunas adicionadas ao SELECT só para montar o próximo cursor
+	binding string
+}
+
+// keyColumn retorna a coluna de desempate da tabela (DataSource.PrimaryKeys, senão id/_id).
+func keyColumn(ds *datasource.DataSource, table string) string {
+	for t, col := range ds.PrimaryKeys {
+		if strings.EqualFold(t, table) {
+			return col
+		}
+	}
+	if ds.Type == "mongodb" {
+		return "_id"
+	}
+	return "id"
+}
+
+// buildKeyset valida o cursor recebido e monta a ordenação estável da paginação.
+func (s *QueryService) buildKeyset(ak *apikey.APIKey, ds *datasource.DataSource, table string, req QueryRequest, order []query.Order) (*keyset, error) {
+	pk := keyColumn(ds, table)
+	if !columnRegex.MatchString(pk) || isColumnBlocked(table, pk, ds.BlockedColumns) {
+		return nil, domain.NewAppError(domain.ErrInvalidInput, fmt.Sprintf("cursor pagination unavailable: key column %s cannot be used", pk), http.StatusBadRequest)
+	}
+	// O cursor carrega o valor da chave; exigir a mesma permissão de uma coluna referenciada.
+	if !s.skipAuthorization && ak != nil && !ak.HasPermission(ds.Name+"."+table+"."+pk) {
+		return nil, forbidden(fmt.Sprintf("access denied to column: %s", pk), map[string]interface{}{
+			"resource": ds.Name + "." + table + "." + pk,
+		})
+	}
+
+	k := &keyset{order: order}
+	hasKey := false
+	for _, o := range order {
+		hasKey = hasKey || o.Column == pk
+	}
+	if !hasKey {
+		desc := len(order) > 0 && order[len(order)-1].Desc
+		k.order = append(append([]query.Order{}, order...), query.Order{Column: pk, Desc: desc})
+	}
+	if len(req.Fields) > 0 {
+		for _, o := range k.order {
+			if !containsColumn(req.Fields, o.Column) {
+				k.extra = append(k.extra, o.Column)
+			}
+		}
+	}
+
+	binding, err := cursorBinding(ds.Name, table, pk, req)
+	if err != nil {
+		return nil, err
+	}
+	k.binding = binding
+
+	if req.Cursor != nil && *req.Cursor != "" {
+		payload, err := s.decodeCursor(*req.Cursor)
+		if err != nil || payload.Query != binding || len(payload.Values) != len(k.order) {
+			return nil, domain.NewAppError(domain.ErrInvalidInput, "invalid cursor", http.StatusBadRequest)
+		}
+		k.after = payload.Values
+	}
+	return k, nil
+}
+
+// markNullable marca as colunas anuláveis da ordenação: o tradutor fixa a posição dos nulos
+// no ORDER BY e no predicado keyset, que de outra forma ((a, b) > ($1, $2) com NULL) nunca é
+// verdadeiro e encerraria a paginação em silêncio na primeira linha nula.
+func (k *keyset) markNullable(desc *schema.Table) {
+	for i, o := range k.order {
+		if col, ok := desc.Column(o.Column); ok && col.Nullable {
+			k.order[i].Nullable = true
+		}
+	}
+}
+
+// next devolve o cursor da página seguinte, ou "" quando a página não veio cheia. Sem catálogo
+// (MongoDB) as colunas não são marcadas como anuláveis: um valor nulo na última linha é um
+// erro, não o fim da paginação.
+func (s *QueryService) next(k *keyset, rows []map[string]any, limit int) (string, error) {
+	if len(rows) == 0 || len(rows) < limit {
+		return "", nil
+	}
+	last := rows[len(rows)-1]
+	values := make([]any, len(k.order))
+	for i, o := range k.order {
+		if last[o.Column] == nil && !o.Nullable {
+			return "", domain.NewAppError(domain.ErrInvalidInput,
+				fmt.Sprintf("cursor pagination unavailable: orderBy column %s has null values", o.Column), http.StatusBadRequest).
+				WithDetails(map[string]interface{}{"column": o.Column})
+		}
+		values[i] = last[o.Column]
+	}
+	return s.encodeCursor(cursorPayload{Query: k.binding, Values: values}), nil
+}
+
+// cursorBinding resume tudo que define o conjunto ordenado de linhas, exceto a posição.
+func cursorBinding(source, table, pk string, req QueryRequest) (string, error) {
+	req.Cursor = nil
+	req.Limit = 0
+	req.Offset = 0
+	req.CountTotal = false
+	hash, err := HashQueryRequest(req)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256([]byte(source + "/" + table + "/" + pk + "/" + hash))
+	return hex.EncodeToString(sum[:8]), nil
+}
+
+func (s *QueryService) encodeCursor(p cursorPayload) string {
+	b, _ := json.Marshal(p)
+	body := base64.RawURLEncoding.EncodeToString(b)
+	return body + "." + base64.RawURLEncoding.EncodeToString(s.sign(body))
+}
+
+func (s *QueryService) decodeCursor(token string) (*cursorPayload, error) {
+	body, sig, ok := strings.Cut(token, ".")
+	if !ok {
+		return nil, fmt.Errorf("malformed cursor")
+	}
+	got, err := base64.RawURLEncoding.DecodeString(sig)
+	if err != nil || !hmac.Equal(got, s.sign(body)) {
+		return nil, fmt.Errorf("invalid cursor signature")
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(body)
+	if err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	var p cursorPayload
+	if err := dec.Decode(&p); err != nil {
+		return nil, err
+	}
+	for i, v := range p.Values {
+		p.Values[i] = cursorValue(v)
+	}
+	return &p, nil
+}
+
+func (s *QueryService) sign(body string) []byte {
+	mac := hmac.New(sha256.New, s.cursorSecret)
+	mac.Write([]byte(body))
+	return mac.Sum(nil)
+}
+
+// cursorValue preserva inteiros grandes (ex.: bigint) que float64 arredondaria.
+func cursorValue(v any) any {
+	n, ok := v.(json.Number)
+	if !ok {
+		return v
+	}
+	if i, err := n.Int64(); err == nil {
+		return i
+	}
+	f, _ := n.Float64()
+	return f
+}
+
+func containsColumn(cols []string, col string) bool {
+	for _, c := range cols {
+		if c == col {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,92 @@
+package data
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"api-database/internal/domain"
+	"api-database/internal/domain/apikey"
+	"api-database/internal/domain/datasource"
+)
+
+func TestQueryTable_CursorSeekPredicate(t *testing.T) {
+	ds := &datasource.DataSource{Name: "main", Type: "postgres", PrimaryKeys: map[string]string{"User": "uid"}}
+	svc, conn := newTestService(ds, []map[string]any{
+		{"uid": int64(9007199254740993), "createdAt": "2025-01-02T00:00:00Z", "email": "a"},
+	})
+	first := ""
+	req := QueryRequest{
+		Fields:  []string{"email"},
+		OrderBy: []OrderField{{Field: "createdAt", Direction: "desc"}},
+		Limit:   1,
+		Cursor:  &first,
+	}
+
+	page1, err := svc.QueryTable(context.Background(), adminKey, "main", "User", req)
+	require.NoError(t, err)
+	assert.Equal(t, `SELECT "email", "createdAt", "uid" FROM "User" ORDER BY "createdAt" DESC, "uid" DESC LIMIT $1 OFFSET $2`, conn.statements[0].Text)
+	assert.Equal(t, []map[string]any{{"email": "a"}}, page1.Data)
+	require.NotEmpty(t, page1.Metadata.NextCursor)
+
+	req.Cursor = &page1.Metadata.NextCursor
+	_, err = svc.QueryTable(context.Background(), adminKey, "main", "User", req)
+	require.NoError(t, err)
+	assert.Equal(t, `SELECT "email", "createdAt", "uid" FROM "User" WHERE ("createdAt", "uid") < ($1, $2) ORDER BY "createdAt" DESC, "uid" DESC LIMIT $3 OFFSET $4`, conn.statements[1].Text)
+	assert.Equal(t, []any{"2025-01-02T00:00:00Z", int64(9007199254740993), 1, 0}, conn.statements[1].Args)
+}
+
+func TestQueryTable_CursorRejectsTampering(t *testing.T) {
+	ds := &datasource.DataSource{Name: "main", Type: "postgres"}
+	svc, _ := newTestService(ds, []map[string]any{{"id": int64(1)}})
+	first := ""
+	req := QueryRequest{Limit: 1, Cursor: &first}
+
+	page1, err := svc.QueryTable(context.Background(), adminKey, "main", "User", req)
+	require.NoError(t, err)
+	cursor := page1.Metadata.NextCursor
+	require.NotEmpty(t, cursor)
+
+	body, sig, _ := strings.Cut(cursor, ".")
+	cases := map[string]string{
+		"garbage":       "not-a-cursor",
+		"tampered body": body + "x." + sig,
+		"foreign key":   NewQueryService(nil, nil).encodeCursor(cursorPayload{Values: []any{1}}),
+	}
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			c := c
+			_, err := svc.QueryTable(context.Background(), adminKey, "main", "User", QueryRequest{Limit: 1, Cursor: &c})
+			assertInvalidInput(t, err)
+		})
+	}
+
+	// O cursor vale apenas para a consulta que o gerou.
+	_, err = svc.QueryTable(context.Background(), adminKey, "main", "User", QueryRequest{
+		Limit:  1,
+		Cursor: &cursor,
+		Filter: Filter{Columns: map[string]FilterField{"role": {Eq: "PILOT"}}},
+	})
+	assertInvalidInput(t, err)
+}
+
+func TestQueryTable_CursorRequiresKeyPermission(t *testing.T) {
+	ds := &datasource.DataSource{Name: "main", Type: "postgres"}
+	svc, _ := newTestService(ds, nil)
+	ak := &apikey.APIKey{Permissions: []apikey.Permission{{Resource: "main.User.email", Level: apikey.LevelColumn}}}
+	first := ""
+
+	_, err := svc.QueryTable(context.Background(), ak, "main", "User", QueryRequest{Fields: []string{"email"}, Cursor: &first})
+	assertForbidden(t, err)
+}
+
+func assertInvalidInput(t *testing.T, err error) {
+	t.Helper()
+	appErr, ok := err.(*domain.AppError)
+	if assert.True(t, ok) {
+		assert.Equal(t, domain.ErrInvalidInput, appErr.Code)
+	}
+}
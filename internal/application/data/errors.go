@@ -0,0 +1,25 @@
+package data
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/rs/zerolog"
+
+	"api-database/internal/domain"
+)
+
+// ClientError converte err no AppError devolvido ao cliente. Erros sem classificação viram
+// QUERY_FAILED com mensagem fixa (o texto do driver pode conter host, usuário ou valores); a
+// causa das falhas 5xx é registrada no logger do contexto.
+func ClientError(ctx context.Context, err error) *domain.AppError {
+	var appErr *domain.AppError
+	if !errors.As(err, &appErr) {
+		appErr = domain.QueryFailed(err)
+	}
+	if cause := appErr.Unwrap(); cause != nil && appErr.Status() >= http.StatusInternalServerError {
+		zerolog.Ctx(ctx).Error().Err(cause).Str("code", string(appErr.Code)).Msg("request failed")
+	}
+	return appErr
+}
@@ -0,0 +1,184 @@
+package data
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+
+	"api-database/internal/domain"
+	"api-database/internal/domain/apikey"
+	"api-database/internal/domain/datasource"
+	"api-database/internal/domain/query"
+)
+
+// defaultMaxExportRows limita exportações quando WithExportLimit não é configurado.
+const defaultMaxExportRows = 100000
+
+// RowWriter recebe as linhas de uma exportação. Begin é chamado uma única vez, antes da
+// primeira linha (ou ao final, sem linhas), com as colunas na ordem de saída.
+type RowWriter interface {
+	Begin(columns []string) error
+	Write(row map[string]any) error
+}
+
+// WithExportLimit define o teto de linhas das exportações (EXPORT_MAX_ROWS).
+func (s *QueryService) WithExportLimit(maxRows int) *QueryService {
+	if maxRows > 0 {
+		s.exportMaxRows = maxRows
+	}
+	return s
+}
+
+// ExportTable executa a consulta como QueryTable, mas entrega as linhas a w conforme são
+// lidas, com limite próprio (maior) e o orçamento de tempo das consultas assíncronas.
+// Aceita filter, orderBy, fields e limit; paginação, agregações e includes não se aplicam.
+// Erros retornados antes de Begin podem ser enviados ao cliente como resposta normal.
+func (s *QueryService) ExportTable(ctx context.Context, ak *apikey.APIKey, sourceName, table string, req QueryRequest, w RowWriter) (rows int, err error) {
+	if !tableNameRegex.MatchString(table) {
+		return 0, domain.NewAppError(domain.ErrInvalidTable, "invalid table name", http.StatusBadRequest)
+	}
+	if req.Schema != "" && !tableNameRegex.MatchString(req.Schema) {
+		return 0, domain.NewAppError(domain.ErrInvalidSchema, "invalid schema name", http.StatusBadRequest)
+	}
+	if req.Cursor != nil || req.aggregated() || len(req.Include) > 0 {
+		return 0, invalidInput("export supports only filter, orderBy, fields and limit")
+	}
+	if err := s.Authorize(ak, sourceName, table, req); err != nil {
+		return 0, err
+	}
+
+	ds, err := s.repo.GetByName(ctx, sourceName)
+	if err != nil {
+		return 0, domain.NewAppError(domain.ErrDataSourceNotFound, "datasource not found", http.StatusNotFound)
+	}
+	translator, ok := s.connectors.Translator(ds.Type)
+	if !ok {
+		return 0, domain.NewAppError(domain.ErrUnsupportedType, fmt.Sprintf("unsupported datasource type: %s", ds.Type), http.StatusBadRequest)
+	}
+
+	ctx = withAsyncBudget(ctx)
+	timeout := s.timeout(ctx, ds)
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	defer func() {
+		if err != nil {
+			err = timeoutError(ctx, err, timeout)
+		}
+	}()
+
+	if err := checkRequestColumns(table, ds, req); err != nil {
+		return 0, err
+	}
+	if err := s.validateColumns(ctx, ak, ds, table, req, nil); err != nil {
+		return 0, err
+	}
+	columns, err := s.exportColumns(ctx, ak, ds, table, req)
+	if err != nil {
+		return 0, err
+	}
+
+	where, err := buildFilter(req.Filter, ds.Capabilities.MaxDepthLimit)
+	if err != nil {
+		return 0, err
+	}
+	stmt, err := translator.Select(query.Select{
+		Schema:  req.Schema,
+		Table:   table,
+		Columns: req.Fields,
+		Where:   where,
+		OrderBy: buildOrder(req.OrderBy),
+		Limit:   s.exportLimit(ds, req.Limit),
+	})
+	if err != nil {
+		return 0, invalidInput(err.Error())
+	}
+
+	conn, release, err := s.connectors.Connector(ctx, ds)
+	if err != nil {
+		return 0, err
+	}
+	defer release()
+
+	begun := false
+	write := func(row map[string]any) error {
+		stripBlocked([]map[string]any{row}, table, ds.BlockedColumns)
+		if !begun {
+			if columns == nil {
+				columns = sortedKeys(row)
+			}
+			if err := w.Begin(columns); err != nil {
+				return err
+			}
+			begun = true
+		}
+		rows++
+		return w.Write(row)
+	}
+	if streamer, ok := conn.(datasource.Streamer); ok {
+		err = streamer.Stream(ctx, write, stmt.Text, stmt.Args...)
+	} else {
+		var result []map[string]any
+		if result, err = conn.Query(ctx, stmt.Text, stmt.Args...); err == nil {
+			for _, row := range result {
+				if err = write(row); err != nil {
+					break
+				}
+			}
+		}
+	}
+	if err != nil {
+		return rows, err
+	}
+	if !begun {
+		if columns == nil {
+			columns = []string{}
+		}
+		if err := w.Begin(columns); err != nil {
+			return rows, err
+		}
+	}
+	return rows, nil
+}
+
+// exportLimit aplica o teto de exportação (e Limits.MaxExportRows, se menor) ao limit pedido.
+func (s *QueryService) exportLimit(ds *datasource.DataSource, requested int) int {
+	maxRows := s.exportMaxRows
+	if ds.Limits.MaxExportRows > 0 && ds.Limits.MaxExportRows < maxRows {
+		maxRows = ds.Limits.MaxExportRows
+	}
+	if requested <= 0 || requested > maxRows {
+		return maxRows
+	}
+	return requested
+}
+
+// exportColumns define a ordem das colunas: fields, senão a ordem do catálogo (sem colunas
+// bloqueadas). Sem catálogo (MongoDB) retorna nil e a primeira linha define as colunas.
+func (s *QueryService) exportColumns(ctx context.Context, ak *apikey.APIKey, ds *datasource.DataSource, table string, req QueryRequest) ([]string, error) {
+	if len(req.Fields) > 0 {
+		return req.Fields, nil
+	}
+	if _, ok := s.connectors.Inspector(ds.Type); !ok {
+		return nil, nil
+	}
+	desc, err := s.describeExisting(ctx, ds, req.Schema, table)
+	if err != nil {
+		return nil, err
+	}
+	visible := s.visibleTable(ak, ds, desc).Columns
+	columns := make([]string, len(visible))
+	for i, c := range visible {
+		columns[i] = c.Name
+	}
+	return columns, nil
+}
+
+func sortedKeys(row map[string]any) []string {
+	keys := make([]string, 0, len(row))
+	for k := range row {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,107 @@
+package data
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"api-database/internal/domain"
+)
+
+// recordingWriter guarda as colunas e linhas recebidas pela exportação.
+type recordingWriter struct {
+	columns []string
+	begins  int
+	rows    []map[string]any
+}
+
+func (w *recordingWriter) Begin(columns []string) error {
+	w.columns = columns
+	w.begins++
+	return nil
+}
+
+func (w *recordingWriter) Write(row map[string]any) error {
+	w.rows = append(w.rows, row)
+	return nil
+}
+
+func TestExportTable_SQLite(t *testing.T) {
+	svc := newSQLiteService(t, sqliteUsers...)
+	w := &recordingWriter{}
+
+	rows, err := svc.ExportTable(context.Background(), adminKey, "main", "User", QueryRequest{
+		OrderBy: []OrderField{{Field: "id", Direction: "asc"}},
+	}, w)
+	require.NoError(t, err)
+	assert.Equal(t, 3, rows)
+	assert.Equal(t, 1, w.begins)
+	// Ordem do catálogo, sem a coluna bloqueada
+	assert.Equal(t, []string{"id", "email", "role", "active"}, w.columns)
+	require.Len(t, w.rows, 3)
+	for _, row := range w.rows {
+		assert.NotContains(t, row, "passwordHash")
+	}
+	assert.Equal(t, "a@example.com", w.rows[0]["email"])
+}
+
+func TestExportTable_FieldsAndLimit(t *testing.T) {
+	svc := newSQLiteService(t, sqliteUsers...)
+	ctx := context.Background()
+
+	w := &recordingWriter{}
+	_, err := svc.ExportTable(ctx, adminKey, "main", "User", QueryRequest{Fields: []string{"email", "id"}}, w)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"email", "id"}, w.columns)
+
+	svc.WithExportLimit(2)
+	w = &recordingWriter{}
+	rows, err := svc.ExportTable(ctx, adminKey, "main", "User", QueryRequest{Limit: 10}, w)
+	require.NoError(t, err)
+	assert.Equal(t, 2, rows)
+
+	svc.repo.(*fakeRepo).sources["main"].Limits.MaxExportRows = 1
+	w = &recordingWriter{}
+	rows, err = svc.ExportTable(ctx, adminKey, "main", "User", QueryRequest{}, w)
+	require.NoError(t, err)
+	assert.Equal(t, 1, rows)
+}
+
+func TestExportTable_NoRows(t *testing.T) {
+	svc := newSQLiteService(t, sqliteUsers...)
+	w := &recordingWriter{}
+
+	rows, err := svc.ExportTable(context.Background(), adminKey, "main", "User", QueryRequest{
+		Filter: Filter{Columns: map[string]FilterField{"role": {Eq: "NONE"}}},
+	}, w)
+	require.NoError(t, err)
+	assert.Zero(t, rows)
+	assert.Equal(t, 1, w.begins)
+	assert.Equal(t, []string{"id", "email", "role", "active"}, w.columns)
+}
+
+func TestExportTable_Rejected(t *testing.T) {
+	svc := newSQLiteService(t, sqliteUsers...)
+	ctx := context.Background()
+
+	cursor := ""
+	for name, req := range map[string]QueryRequest{
+		"cursor":  {Cursor: &cursor},
+		"groupBy": {GroupBy: []string{"role"}},
+		"blocked": {Fields: []string{"passwordHash"}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			w := &recordingWriter{}
+			_, err := svc.ExportTable(ctx, adminKey, "main", "User", req, w)
+			require.Error(t, err)
+			_, ok := err.(*domain.AppError)
+			assert.True(t, ok)
+			assert.Zero(t, w.begins)
+		})
+	}
+
+	_, err := svc.ExportTable(ctx, nil, "main", "User", QueryRequest{}, &recordingWriter{})
+	assertForbidden(t, err)
+}
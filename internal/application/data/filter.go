@@ -0,0 +1,378 @@
+package data
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"api-database/internal/domain"
+	"api-database/internal/domain/query"
+)
+
+// FilterField reúne os operadores aplicados a uma coluna; vários operadores são combinados com AND.
+type FilterField struct {
+	Eq      any `json:"$eq,omitempty"`
+	Ne      any `json:"$ne,omitempty"`
+	Gt      any `json:"$gt,omitempty"`
+	Gte     any `json:"$gte,omitempty"`
+	Lt      any `json:"$lt,omitempty"`
+	Lte     any `json:"$lte,omitempty"`
+	In      any `json:"$in,omitempty"`      // lista não vazia
+	Nin     any `json:"$nin,omitempty"`     // lista não vazia
+	Like    any `json:"$like,omitempty"`    // padrão SQL (% e _)
+	ILike   any `json:"$ilike,omitempty"`   // como $like, sem diferenciar maiúsculas
+	IsNull  any `json:"$isNull,omitempty"`  // true = IS NULL, false = IS NOT NULL
+	Between any `json:"$between,omitempty"` // [min, max], inclusivo
+}
+
+// maxFilterListValues limita $in/$nin: nos dialetos sem parâmetro de array cada valor é um
+// placeholder, e os bancos limitam o número de parâmetros por instrução.
+const maxFilterListValues = 1000
+
+// filterOperators lista os operadores aceitos no JSON, na ordem de geração dos parâmetros.
+var filterOperators = []string{"$eq", "$ne", "$gt", "$gte", "$lt", "$lte", "$in", "$nin", "$like", "$ilike", "$isNull", "$between"}
+
+// UnmarshalJSON rejeita operadores desconhecidos em vez de ignorá-los silenciosamente.
+func (f *FilterField) UnmarshalJSON(b []byte) error {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+	for op := range raw {
+		if !isFilterOperator(op) {
+			return domain.NewAppError(domain.ErrInvalidInput, fmt.Sprintf("unsupported filter operator: %s", op), http.StatusBadRequest).
+				WithDetails(map[string]interface{}{"operator": op, "supported": filterOperators})
+		}
+	}
+	type plain FilterField
+	if err := json.Unmarshal(b, (*plain)(f)); err != nil {
+		return err
+	}
+	return f.nullComparisons(raw)
+}
+
+// nullComparisons traduz {"$eq": null} e {"$ne": null} para $isNull: em SQL "= NULL" nunca é
+// verdadeiro e, decodificado como nil, o operador sumiria do filtro. Nos demais operadores
+// null é rejeitado.
+func (f *FilterField) nullComparisons(raw map[string]json.RawMessage) error {
+	for op, value := range raw {
+		if string(bytes.TrimSpace(value)) != "null" {
+			continue
+		}
+		var isNull bool
+		switch op {
+		case "$eq":
+			isNull = true
+		case "$ne":
+			isNull = false
+		default:
+			return domain.NewAppError(domain.ErrInvalidInput, fmt.Sprintf("invalid value for %s: null is only accepted by $eq and $ne", op), http.StatusBadRequest)
+		}
+		if current, ok := f.IsNull.(bool); ok && current != isNull {
+			return domain.NewAppError(domain.ErrInvalidInput, fmt.Sprintf("conflicting null checks: %s null and $isNull %t", op, current), http.StatusBadRequest)
+		}
+		f.IsNull = isNull
+	}
+	return nil
+}
+
+func isFilterOperator(op string) bool {
+	for _, known := range filterOperators {
+		if op == known {
+			return true
+		}
+	}
+	return false
+}
+
+// filterCondition associa um operador do JSON ($eq, ...) ao operador neutro e ao valor.
+type filterCondition struct {
+	name  string
+	op    query.Operator
+	value any
+}
+
+// conditions lista as comparações presentes no filtro da coluna.
+func (f FilterField) conditions() []filterCondition {
+	var result []filterCondition
+	for _, c := range []filterCondition{
+		{"$eq", query.OpEq, f.Eq},
+		{"$ne", query.OpNe, f.Ne},
+		{"$gt", query.OpGt, f.Gt},
+		{"$gte", query.OpGte, f.Gte},
+		{"$lt", query.OpLt, f.Lt},
+		{"$lte", query.OpLte, f.Lte},
+		{"$in", query.OpIn, f.In},
+		{"$nin", query.OpNin, f.Nin},
+		{"$like", query.OpLike, f.Like},
+		{"$ilike", query.OpILike, f.ILike},
+		{"$isNull", query.OpIsNull, f.IsNull},
+		{"$between", query.OpBetween, f.Between},
+	} {
+		if c.value != nil {
+			result = append(result, c)
+		}
+	}
+	return result
+}
+
+// conditionValue valida o formato do valor de cada operador.
+func conditionValue(col string, c filterCondition) (any, error) {
+	invalid := func(expected string) error {
+		return domain.NewAppError(domain.ErrInvalidInput, fmt.Sprintf("invalid value for %s on %s: expected %s", c.name, col, expected), http.StatusBadRequest)
+	}
+	switch c.op {
+	case query.OpIn, query.OpNin:
+		list, ok := c.value.([]any)
+		if !ok || len(list) == 0 {
+			return nil, invalid("non-empty array")
+		}
+		if len(list) > maxFilterListValues {
+			return nil, invalid(fmt.Sprintf("at most %d values", maxFilterListValues))
+		}
+		return list, nil
+	case query.OpBetween:
+		bounds, ok := c.value.([]any)
+		if !ok || len(bounds) != 2 || bounds[0] == nil || bounds[1] == nil {
+			return nil, invalid("[min, max]")
+		}
+		return bounds, nil
+	case query.OpLike, query.OpILike:
+		pattern, ok := c.value.(string)
+		if !ok {
+			return nil, invalid("string pattern")
+		}
+		return pattern, nil
+	case query.OpIsNull:
+		isNull, ok := c.value.(bool)
+		if !ok {
+			return nil, invalid("boolean")
+		}
+		return isNull, nil
+	default:
+		if _, ok := c.value.([]any); ok {
+			return nil, invalid("scalar value")
+		}
+		return c.value, nil
+	}
+}
+
+// Filter é a árvore de filtros da requisição. No JSON, colunas e grupos convivem no mesmo
+// objeto e são combinados com AND:
+//
+//	{"status": {"$eq": "A"}, "$or": [{"role": {"$eq": "X"}}, {"role": {"$eq": "Y"}}]}
+type Filter struct {
+	Columns map[string]FilterField
+	And     []Filter
+	Or      []Filter
+	Not     *Filter
+}
+
+// defaultMaxFilterDepth limita o aninhamento de grupos quando o datasource não define
+// Capabilities.MaxDepthLimit.
+const defaultMaxFilterDepth = 4
+
+// UnmarshalJSON separa os grupos ($and, $or, $not) das colunas.
+func (f *Filter) UnmarshalJSON(b []byte) error {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+	*f = Filter{}
+	for key, value := range raw {
+		var err error
+		switch key {
+		case "$and":
+			err = json.Unmarshal(value, &f.And)
+		case "$or":
+			err = json.Unmarshal(value, &f.Or)
+		case "$not":
+			f.Not = &Filter{}
+			err = json.Unmarshal(value, f.Not)
+		default:
+			if strings.HasPrefix(key, "$") {
+				return domain.NewAppError(domain.ErrInvalidInput, fmt.Sprintf("unsupported filter group: %s", key), http.StatusBadRequest).
+					WithDetails(map[string]interface{}{"operator": key, "supported": []string{"$and", "$or", "$not"}})
+			}
+			var field FilterField
+			err = json.Unmarshal(value, &field)
+			if f.Columns == nil {
+				f.Columns = make(map[string]FilterField)
+			}
+			f.Columns[key] = field
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// MarshalJSON produz o mesmo formato aceito por UnmarshalJSON (usado na fila de jobs).
+func (f Filter) MarshalJSON() ([]byte, error) {
+	if f.isEmpty() {
+		return []byte("null"), nil
+	}
+	out := make(map[string]any, len(f.Columns)+3)
+	for col, field := range f.Columns {
+		out[col] = field
+	}
+	if len(f.And) > 0 {
+		out["$and"] = f.And
+	}
+	if len(f.Or) > 0 {
+		out["$or"] = f.Or
+	}
+	if f.Not != nil {
+		out["$not"] = f.Not
+	}
+	return json.Marshal(out)
+}
+
+func (f Filter) isEmpty() bool {
+	return len(f.Columns) == 0 && len(f.And) == 0 && len(f.Or) == 0 && f.Not == nil
+}
+
+// columns lista as colunas referenciadas em todos os níveis da árvore.
+func (f Filter) columns() []string {
+	cols := make([]string, 0, len(f.Columns))
+	for col := range f.Columns {
+		cols = append(cols, col)
+	}
+	for _, g := range f.groups() {
+		cols = append(cols, g.columns()...)
+	}
+	return cols
+}
+
+// depth conta níveis de grupos aninhados; um filtro só com colunas tem profundidade 0.
+func (f Filter) depth() int {
+	max := 0
+	for _, g := range f.groups() {
+		if d := g.depth() + 1; d > max {
+			max = d
+		}
+	}
+	return max
+}
+
+func (f Filter) groups() []Filter {
+	groups := make([]Filter, 0, len(f.And)+len(f.Or)+1)
+	groups = append(groups, f.And...)
+	groups = append(groups, f.Or...)
+	if f.Not != nil {
+		groups = append(groups, *f.Not)
+	}
+	return groups
+}
+
+// shape descreve a estrutura (colunas, operadores e grupos) sem valores.
+func (f Filter) shape() []string {
+	var items []string
+	for col, field := range f.Columns {
+		for _, c := range field.conditions() {
+			items = append(items, col+":"+c.name)
+		}
+	}
+	for _, g := range f.And {
+		items = append(items, g.shape()...)
+	}
+	if len(f.Or) > 0 {
+		branches := make([]string, len(f.Or))
+		for i, g := range f.Or {
+			branches[i] = strings.Join(g.shape(), ",")
+		}
+		sort.Strings(branches)
+		items = append(items, "$or("+strings.Join(branches, "|")+")")
+	}
+	if f.Not != nil {
+		items = append(items, "$not("+strings.Join(f.Not.shape(), ",")+")")
+	}
+	sort.Strings(items)
+	return items
+}
+
+// buildFilter converte o filtro da requisição em uma árvore neutra, rejeitando
+// aninhamento acima de maxDepth e grupos vazios.
+func buildFilter(f Filter, maxDepth int) (*query.Filter, error) {
+	if maxDepth <= 0 {
+		maxDepth = defaultMaxFilterDepth
+	}
+	if depth := f.depth(); depth > maxDepth {
+		return nil, domain.NewAppError(domain.ErrInvalidInput, fmt.Sprintf("filter nesting depth %d exceeds limit %d", depth, maxDepth), http.StatusBadRequest).
+			WithDetails(map[string]interface{}{"depth": depth, "maxDepth": maxDepth})
+	}
+	clauses, err := f.clauses()
+	if err != nil {
+		return nil, err
+	}
+	return query.And(clauses...), nil
+}
+
+// clauses gera as condições do nível atual: colunas (ordem alfabética, para parâmetros
+// determinísticos), depois $and, $or e $not.
+func (f Filter) clauses() ([]query.Filter, error) {
+	cols := make([]string, 0, len(f.Columns))
+	for col := range f.Columns {
+		if !columnRegex.MatchString(col) {
+			return nil, domain.NewAppError(domain.ErrInvalidInput, fmt.Sprintf("invalid column name: %s", col), http.StatusBadRequest)
+		}
+		cols = append(cols, col)
+	}
+	sort.Strings(cols)
+
+	var result []query.Filter
+	for _, col := range cols {
+		for _, c := range f.Columns[col].conditions() {
+			value, err := conditionValue(col, c)
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, query.Filter{Op: c.op, Column: col, Value: value})
+		}
+	}
+
+	// $and apenas agrupa: as condições entram no mesmo AND do nível atual.
+	for _, g := range f.And {
+		children, err := g.groupClauses("$and")
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, children...)
+	}
+	if len(f.Or) > 0 {
+		or := query.Filter{Op: query.OpOr}
+		for _, g := range f.Or {
+			children, err := g.groupClauses("$or")
+			if err != nil {
+				return nil, err
+			}
+			or.Children = append(or.Children, query.Filter{Op: query.OpAnd, Children: children})
+		}
+		result = append(result, or)
+	}
+	if f.Not != nil {
+		children, err := f.Not.groupClauses("$not")
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, query.Filter{Op: query.OpNot, Children: children})
+	}
+	return result, nil
+}
+
+// groupClauses gera as condições de um grupo, que não pode ser vazio: um ramo vazio
+// em $or casaria com todas as linhas.
+func (f Filter) groupClauses(group string) ([]query.Filter, error) {
+	children, err := f.clauses()
+	if err != nil {
+		return nil, err
+	}
+	if len(children) == 0 {
+		return nil, domain.NewAppError(domain.ErrInvalidInput, fmt.Sprintf("empty condition in %s", group), http.StatusBadRequest)
+	}
+	return children, nil
+}
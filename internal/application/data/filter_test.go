@@ -0,0 +1,210 @@
+package data
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"api-database/internal/domain"
+	"api-database/internal/domain/datasource"
+)
+
+func TestFilterField_RejectsUnknownOperator(t *testing.T) {
+	var req QueryRequest
+	err := json.Unmarshal([]byte(`{"filter":{"role":{"$regex":"^A"}}}`), &req)
+
+	var appErr *domain.AppError
+	require.True(t, errors.As(err, &appErr))
+	assert.Equal(t, domain.ErrInvalidInput, appErr.Code)
+	assert.Equal(t, "$regex", appErr.Details["operator"])
+}
+
+func TestQueryTable_ExtendedOperators(t *testing.T) {
+	ds := &datasource.DataSource{Name: "main", Type: "postgres"}
+	svc, conn := newTestService(ds, nil)
+
+	var req QueryRequest
+	require.NoError(t, json.Unmarshal([]byte(`{"filter":{
+		"role": {"$in": ["PILOT", "ADMIN"]},
+		"deletedAt": {"$isNull": true},
+		"name": {"$ilike": "jo%"},
+		"age": {"$between": [18, 30]}
+	}}`), &req))
+
+	_, err := svc.QueryTable(context.Background(), adminKey, "main", "User", req)
+	require.NoError(t, err)
+	assert.Equal(t, `SELECT * FROM "User" WHERE "age" BETWEEN $1 AND $2 AND "deletedAt" IS NULL`+
+		` AND "name" ILIKE $3 AND "role" = ANY($4) LIMIT $5 OFFSET $6`, conn.statements[0].Text)
+	assert.Equal(t, []any{float64(18), float64(30), "jo%", `{"PILOT","ADMIN"}`, 100, 0}, conn.statements[0].Args)
+}
+
+func TestQueryTable_NullComparisons(t *testing.T) {
+	ds := &datasource.DataSource{Name: "main", Type: "postgres"}
+	svc, conn := newTestService(ds, nil)
+
+	var req QueryRequest
+	require.NoError(t, json.Unmarshal([]byte(`{"filter":{"deletedAt": {"$eq": null}, "verifiedAt": {"$ne": null}}}`), &req))
+	_, err := svc.QueryTable(context.Background(), adminKey, "main", "User", req)
+	require.NoError(t, err)
+	assert.Equal(t, `SELECT * FROM "User" WHERE "deletedAt" IS NULL AND "verifiedAt" IS NOT NULL LIMIT $1 OFFSET $2`, conn.statements[0].Text)
+
+	for name, body := range map[string]string{
+		"null $gt":      `{"filter":{"age": {"$gt": null}}}`,
+		"null $in":      `{"filter":{"age": {"$in": null}}}`,
+		"conflict":      `{"filter":{"age": {"$eq": null, "$isNull": false}}}`,
+		"null $isNull":  `{"filter":{"age": {"$isNull": null}}}`,
+		"null $between": `{"filter":{"age": {"$between": null}}}`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			var req QueryRequest
+			assertInvalidInput(t, json.Unmarshal([]byte(body), &req))
+		})
+	}
+}
+
+func TestQueryTable_InListLimit(t *testing.T) {
+	ds := &datasource.DataSource{Name: "main", Type: "postgres"}
+	svc, conn := newTestService(ds, nil)
+
+	values := make([]any, maxFilterListValues+1)
+	for i := range values {
+		values[i] = float64(i)
+	}
+	_, err := svc.QueryTable(context.Background(), adminKey, "main", "User", QueryRequest{
+		Filter: Filter{Columns: map[string]FilterField{"id": {In: values}}},
+	})
+	assertInvalidInput(t, err)
+
+	_, err = svc.QueryTable(context.Background(), adminKey, "main", "User", QueryRequest{
+		Filter: Filter{Columns: map[string]FilterField{"id": {Nin: values[:maxFilterListValues]}}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, `SELECT * FROM "User" WHERE "id" <> ALL($1) LIMIT $2 OFFSET $3`, conn.statements[0].Text)
+}
+
+func TestQueryTable_InvalidOperatorValue(t *testing.T) {
+	ds := &datasource.DataSource{Name: "main", Type: "postgres"}
+	svc, conn := newTestService(ds, nil)
+
+	cases := map[string]FilterField{
+		"empty $in":      {In: []any{}},
+		"scalar $nin":    {Nin: "A"},
+		"short $between": {Between: []any{1}},
+		"string $isNull": {IsNull: "yes"},
+		"numeric $like":  {Like: float64(1)},
+		"list for $eq":   {Eq: []any{"A"}},
+	}
+	for name, f := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := svc.QueryTable(context.Background(), adminKey, "main", "User", QueryRequest{Filter: Filter{Columns: map[string]FilterField{"role": f}}})
+			appErr, ok := err.(*domain.AppError)
+			require.True(t, ok)
+			assert.Equal(t, domain.ErrInvalidInput, appErr.Code)
+		})
+	}
+	assert.Empty(t, conn.statements)
+}
+
+func TestHashQueryRequest_IncludesExtendedOperators(t *testing.T) {
+	in, err := HashQueryRequest(QueryRequest{Filter: Filter{Columns: map[string]FilterField{"role": {In: []any{"A", "B"}}}}})
+	require.NoError(t, err)
+	nin, err := HashQueryRequest(QueryRequest{Filter: Filter{Columns: map[string]FilterField{"role": {Nin: []any{"A", "B"}}}}})
+	require.NoError(t, err)
+	notNull, err := HashQueryRequest(QueryRequest{Filter: Filter{Columns: map[string]FilterField{"role": {IsNull: false}}}})
+	require.NoError(t, err)
+	isNull, err := HashQueryRequest(QueryRequest{Filter: Filter{Columns: map[string]FilterField{"role": {IsNull: true}}}})
+	require.NoError(t, err)
+
+	assert.NotEqual(t, in, nin)
+	assert.NotEqual(t, notNull, isNull)
+}
+
+func TestQueryTable_BooleanGroups(t *testing.T) {
+	ds := &datasource.DataSource{Name: "main", Type: "postgres"}
+	svc, conn := newTestService(ds, nil)
+
+	var req QueryRequest
+	require.NoError(t, json.Unmarshal([]byte(`{"filter":{
+		"active": {"$eq": true},
+		"$or": [{"status": {"$eq": "A"}}, {"status": {"$eq": "B"}, "age": {"$gt": 18}}],
+		"$not": {"role": {"$eq": "ADMIN"}}
+	}}`), &req))
+
+	_, err := svc.QueryTable(context.Background(), adminKey, "main", "User", req)
+	require.NoError(t, err)
+	assert.Equal(t, `SELECT * FROM "User" WHERE "active" = $1 AND ("status" = $2 OR ("age" > $3 AND "status" = $4))`+
+		` AND NOT ("role" = $5) LIMIT $6 OFFSET $7`, conn.statements[0].Text)
+	assert.Equal(t, []any{true, "A", float64(18), "B", "ADMIN", 100, 0}, conn.statements[0].Args)
+}
+
+func TestQueryTable_NestedBlockedColumn(t *testing.T) {
+	ds := &datasource.DataSource{Name: "main", Type: "postgres", BlockedColumns: []string{"User.passwordHash"}}
+	svc, _ := newTestService(ds, nil)
+
+	var req QueryRequest
+	require.NoError(t, json.Unmarshal([]byte(`{"filter":{"$or":[{"$not":{"passwordHash":{"$eq":"x"}}}]}}`), &req))
+
+	_, err := svc.QueryTable(context.Background(), adminKey, "main", "User", req)
+	appErr, ok := err.(*domain.AppError)
+	require.True(t, ok)
+	assert.Equal(t, domain.ErrColumnBlocked, appErr.Code)
+}
+
+func TestQueryTable_FilterDepthLimit(t *testing.T) {
+	ds := &datasource.DataSource{Name: "main", Type: "postgres", Capabilities: datasource.Capabilities{MaxDepthLimit: 1}}
+	svc, _ := newTestService(ds, nil)
+
+	var ok QueryRequest
+	require.NoError(t, json.Unmarshal([]byte(`{"filter":{"$or":[{"a":{"$eq":1}},{"b":{"$eq":2}}]}}`), &ok))
+	_, err := svc.QueryTable(context.Background(), adminKey, "main", "User", ok)
+	require.NoError(t, err)
+
+	var tooDeep QueryRequest
+	require.NoError(t, json.Unmarshal([]byte(`{"filter":{"$or":[{"$not":{"a":{"$eq":1}}},{"b":{"$eq":2}}]}}`), &tooDeep))
+	_, err = svc.QueryTable(context.Background(), adminKey, "main", "User", tooDeep)
+	appErr, isAppErr := err.(*domain.AppError)
+	require.True(t, isAppErr)
+	assert.Equal(t, domain.ErrInvalidInput, appErr.Code)
+}
+
+func TestQueryTable_EmptyGroupRejected(t *testing.T) {
+	ds := &datasource.DataSource{Name: "main", Type: "postgres"}
+	svc, _ := newTestService(ds, nil)
+
+	var req QueryRequest
+	require.NoError(t, json.Unmarshal([]byte(`{"filter":{"$or":[{"a":{"$eq":1}},{}]}}`), &req))
+	_, err := svc.QueryTable(context.Background(), adminKey, "main", "User", req)
+	appErr, ok := err.(*domain.AppError)
+	require.True(t, ok)
+	assert.Equal(t, domain.ErrInvalidInput, appErr.Code)
+}
+
+func TestFilter_JSONRoundTrip(t *testing.T) {
+	var req QueryRequest
+	require.NoError(t, json.Unmarshal([]byte(`{"filter":{"a":{"$eq":1},"$or":[{"b":{"$in":[1,2]}},{"$not":{"c":{"$isNull":true}}}]}}`), &req))
+
+	b, err := json.Marshal(req)
+	require.NoError(t, err)
+	var decoded QueryRequest
+	require.NoError(t, json.Unmarshal(b, &decoded))
+	assert.Equal(t, req, decoded)
+
+	h1, err := HashQueryRequest(req)
+	require.NoError(t, err)
+	h2, err := HashQueryRequest(decoded)
+	require.NoError(t, err)
+	assert.Equal(t, h1, h2)
+	assert.ElementsMatch(t, []string{"a", "b", "c"}, req.Filter.columns())
+}
+
+func TestFilter_RejectsUnknownGroup(t *testing.T) {
+	var req QueryRequest
+	err := json.Unmarshal([]byte(`{"filter":{"$xor":[]}}`), &req)
+	var appErr *domain.AppError
+	require.True(t, errors.As(err, &appErr))
+	assert.Equal(t, domain.ErrInvalidInput, appErr.Code)
+}
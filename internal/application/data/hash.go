@@ -14,8 +14,8 @@ func HashQueryRequest(req QueryRequest) (string, error) {
 		Key   string      `json:"key"`
 		Value FilterField `json:"value"`
 	}
-	filters := make([]filterItem, 0, len(req.Filter))
-	for k, v := range req.Filter {
+	filters := make([]filterItem, 0, len(req.Filter.Columns))
+	for k, v := range req.Filter.Columns {
 		filters = append(filters, filterItem{Key: k, Value: v})
 	}
 	sort.Slice(filters, func(i, j int) bool { return filters[i].Key < filters[j].Key })
@@ -28,14 +28,35 @@ func HashQueryRequest(req QueryRequest) (string, error) {
 		OrderBy    []OrderField `json:"orderBy"`
 		Filter     []filterItem `json:"filter"`
 		Fields     []string     `json:"fields"`
+		// Grupos só entram quando presentes, mantendo o hash de filtros simples inalterado.
+		And    []Filter `json:"$and,omitempty"`
+		Or     []Filter `json:"$or,omitempty"`
+		Not    *Filter  `json:"$not,omitempty"`
+		Cursor *string  `json:"cursor,omitempty"`
+		// Agregação
+		GroupBy    []string         `json:"groupBy,omitempty"`
+		Aggregates []AggregateField `json:"aggregates,omitempty"`
+		Having     *Filter          `json:"having,omitempty"`
+		Include    []IncludeField   `json:"include,omitempty"`
+		// columnTypes muda a resposta (metadata.columns) e portanto a chave do cache.
+		ColumnTypes bool `json:"columnTypes,omitempty"`
 	}{
-		Schema:     req.Schema,
-		Limit:      req.Limit,
-		Offset:     req.Offset,
-		CountTotal: req.CountTotal,
-		OrderBy:    req.OrderBy,
-		Filter:     filters,
-		Fields:     req.Fields,
+		Schema:      req.Schema,
+		Limit:       req.Limit,
+		Offset:      req.Offset,
+		CountTotal:  req.CountTotal,
+		OrderBy:     req.OrderBy,
+		Filter:      filters,
+		Fields:      req.Fields,
+		And:         req.Filter.And,
+		Or:          req.Filter.Or,
+		Not:         req.Filter.Not,
+		Cursor:      req.Cursor,
+		GroupBy:     req.GroupBy,
+		Aggregates:  req.Aggregates,
+		Having:      optionalFilter(req.Having),
+		Include:     req.Include,
+		ColumnTypes: req.ColumnTypes,
 	}
 
 	b, err := json.Marshal(canonical)
@@ -45,3 +66,42 @@ func HashQueryRequest(req QueryRequest) (string, error) {
 	sum := sha256.Sum256(b)
 	return hex.EncodeToString(sum[:]), nil
 }
+
+// QueryShape cria um hash da estrutura da consulta (colunas, operadores, ordenação),
+// ignorando valores de filtro e paginação, para agrupar histórico de latência.
+func QueryShape(req QueryRequest) string {
+	filters := req.Filter.shape()
+
+	canonical := struct {
+		Schema     string           `json:"schema"`
+		CountTotal bool             `json:"countTotal"`
+		OrderBy    []OrderField     `json:"orderBy"`
+		Filter     []string         `json:"filter"`
+		Fields     []string         `json:"fields"`
+		GroupBy    []string         `json:"groupBy,omitempty"`
+		Aggregates []AggregateField `json:"aggregates,omitempty"`
+		Having     []string         `json:"having,omitempty"`
+		Include    []IncludeField   `json:"include,omitempty"`
+	}{
+		Schema:     req.Schema,
+		CountTotal: req.CountTotal,
+		OrderBy:    req.OrderBy,
+		Filter:     filters,
+		Fields:     req.Fields,
+		GroupBy:    req.GroupBy,
+		Aggregates: req.Aggregates,
+		Having:     req.Having.shape(),
+		Include:    req.Include,
+	}
+
+	b, _ := json.Marshal(canonical)
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:8])
+}
+
+func optionalFilter(f Filter) *Filter {
+	if f.isEmpty() {
+		return nil
+	}
+	return &f
+}
@@ -0,0 +1,266 @@
+package data
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"api-database/internal/domain"
+	"api-database/internal/domain/apikey"
+	"api-database/internal/domain/datasource"
+	"api-database/internal/domain/query"
+	"api-database/internal/domain/schema"
+)
+
+// IncludeField pede linhas relacionadas por chave estrangeira. Relações muitos-para-um
+// viram um objeto aninhado e um-para-muitos um array, na chave Table de cada linha.
+type IncludeField struct {
+	Table   string         `json:"table"`
+	Via     string         `json:"via,omitempty"` // coluna da FK, quando há mais de uma relação entre as tabelas
+	Fields  []string       `json:"fields,omitempty"`
+	Include []IncludeField `json:"include,omitempty"`
+}
+
+const (
+	// defaultMaxIncludeDepth vale quando o datasource não define Capabilities.MaxDepthLimit.
+	defaultMaxIncludeDepth = 2
+	// maxIncludeRows limita as linhas relacionadas carregadas por include; acima dele a
+	// consulta é recusada, para nenhuma linha pai receber uma lista incompleta.
+	maxIncludeRows = 5000
+)
+
+// includePlan é um include resolvido contra as chaves estrangeiras do catálogo.
+type includePlan struct {
+	name      string // chave na linha pai (IncludeField.Table como pedido)
+	table     string
+	columns   []string // colunas consultadas (vazio = todas)
+	hidden    []string // colunas adicionadas só para a junção, removidas da resposta
+	parentKey string
+	childKey  string
+	many      bool
+	children  []*includePlan
+}
+
+func includeDepth(includes []IncludeField) int {
+	max := 0
+	for _, inc := range includes {
+		if d := 1 + includeDepth(inc.Include); d > max {
+			max = d
+		}
+	}
+	return max
+}
+
+// validateIncludes aplica as regras que não dependem do catálogo: capacidade do datasource,
+// profundidade, nomes, colunas bloqueadas e permissões de cada tabela incluída.
+func (s *QueryService) validateIncludes(ak *apikey.APIKey, ds *datasource.DataSource, includes []IncludeField) error {
+	if !ds.Capabilities.SupportsJoins {
+		return invalidInput(fmt.Sprintf("datasource %s does not support joins", ds.Name))
+	}
+	maxDepth := ds.Capabilities.MaxDepthLimit
+	if maxDepth <= 0 {
+		maxDepth = defaultMaxIncludeDepth
+	}
+	if depth := includeDepth(includes); depth > maxDepth {
+		return invalidInput(fmt.Sprintf("include depth %d exceeds limit %d", depth, maxDepth)).
+			WithDetails(map[string]interface{}{"depth": depth, "maxDepth": maxDepth})
+	}
+	return s.validateIncludeLevel(ak, ds, includes)
+}
+
+func (s *QueryService) validateIncludeLevel(ak *apikey.APIKey, ds *datasource.DataSource, includes []IncludeField) error {
+	for _, inc := range includes {
+		if !tableNameRegex.MatchString(inc.Table) {
+			return domain.NewAppError(domain.ErrInvalidTable, fmt.Sprintf("invalid include table: %s", inc.Table), http.StatusBadRequest)
+		}
+		if inc.Via != "" && !columnRegex.MatchString(inc.Via) {
+			return invalidInput(fmt.Sprintf("invalid include via column: %s", inc.Via))
+		}
+		for _, field := range inc.Fields {
+			if err := checkAggregateColumn(inc.Table, ds, field); err != nil {
+				return err
+			}
+		}
+		if err := s.Authorize(ak, ds.Name, inc.Table, QueryRequest{Fields: inc.Fields}); err != nil {
+			return err
+		}
+		if err := s.validateIncludeLevel(ak, ds, inc.Include); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// planIncludes resolve as relações de cada nível com uma consulta de catálogo por tabela.
+func planIncludes(ctx context.Context, inspector schema.Inspector, conn datasource.Executor, ds *datasource.DataSource, schemaName, table string, includes []IncludeField) ([]*includePlan, error) {
+	if len(includes) == 0 {
+		return nil, nil
+	}
+	fks, err := inspector.ForeignKeys(ctx, conn, schemaName, table)
+	if err != nil {
+		return nil, err
+	}
+
+	plans := make([]*includePlan, 0, len(includes))
+	for _, inc := range includes {
+		plan, err := resolveRelation(table, inc, fks)
+		if err != nil {
+			return nil, err
+		}
+		plan.name = inc.Table
+		if isColumnBlocked(table, plan.parentKey, ds.BlockedColumns) {
+			return nil, domain.NewAppError(domain.ErrColumnBlocked, fmt.Sprintf("column blocked: %s", plan.parentKey), http.StatusForbidden)
+		}
+		if isColumnBlocked(plan.table, plan.childKey, ds.BlockedColumns) {
+			return nil, domain.NewAppError(domain.ErrColumnBlocked, fmt.Sprintf("column blocked: %s.%s", plan.table, plan.childKey), http.StatusForbidden)
+		}
+		if plan.children, err = planIncludes(ctx, inspector, conn, ds, schemaName, plan.table, inc.Include); err != nil {
+			return nil, err
+		}
+
+		if len(inc.Fields) > 0 {
+			plan.columns = append(plan.columns, inc.Fields...)
+			keys := []string{plan.childKey}
+			for _, child := range plan.children {
+				keys = append(keys, child.parentKey)
+			}
+			for _, key := range keys {
+				if !containsColumn(plan.columns, key) {
+					plan.columns = append(plan.columns, key)
+					plan.hidden = append(plan.hidden, key)
+				}
+			}
+		}
+		plans = append(plans, plan)
+	}
+	return plans, nil
+}
+
+// resolveRelation encontra a FK de coluna única entre table e inc.Table, em qualquer direção.
+func resolveRelation(table string, inc IncludeField, fks []schema.ForeignKey) (*includePlan, error) {
+	var candidates []*includePlan
+	var options []string
+	for _, fk := range fks {
+		if len(fk.Columns) != 1 || (inc.Via != "" && !strings.EqualFold(fk.Columns[0], inc.Via)) {
+			continue
+		}
+		// table.col → inc.Table.ref: cada linha tem no máximo um relacionado.
+		if strings.EqualFold(fk.Table, table) && strings.EqualFold(fk.RefTable, inc.Table) {
+			candidates = append(candidates, &includePlan{table: fk.RefTable, parentKey: fk.Columns[0], childKey: fk.RefColumns[0]})
+			options = append(options, fk.Columns[0])
+		}
+		// inc.Table.col → table.ref: cada linha pode ter vários relacionados.
+		if strings.EqualFold(fk.RefTable, table) && strings.EqualFold(fk.Table, inc.Table) {
+			candidates = append(candidates, &includePlan{table: fk.Table, parentKey: fk.RefColumns[0], childKey: fk.Columns[0], many: true})
+			options = append(options, fk.Columns[0])
+		}
+	}
+
+	switch len(candidates) {
+	case 0:
+		return nil, invalidInput(fmt.Sprintf("no single-column foreign key between %s and %s", table, inc.Table))
+	case 1:
+		return candidates[0], nil
+	default:
+		return nil, invalidInput(fmt.Sprintf("ambiguous include %s: set via", inc.Table)).
+			WithDetails(map[string]interface{}{"via": options})
+	}
+}
+
+// loadIncludes carrega cada include com uma única consulta (IN sobre as chaves das linhas
+// pai), evitando N+1, e anexa o resultado às linhas.
+func loadIncludes(ctx context.Context, conn datasource.Executor, translator query.Translator, ds *datasource.DataSource, schemaName string, rows []map[string]any, plans []*includePlan) error {
+	for _, plan := range plans {
+		// As linhas têm as mesmas colunas: basta olhar a primeira.
+		if len(rows) > 0 {
+			if _, exists := rows[0][plan.name]; exists {
+				return invalidInput(fmt.Sprintf("include %s conflicts with a column of the same name", plan.name)).
+					WithDetails(map[string]interface{}{"include": plan.name})
+			}
+		}
+		values := distinctValues(rows, plan.parentKey)
+		var children []map[string]any
+		if len(values) > 0 {
+			stmt, err := translator.Select(query.Select{
+				Schema:  schemaName,
+				Table:   plan.table,
+				Columns: plan.columns,
+				Where:   query.And(query.Filter{Op: query.OpIn, Column: plan.childKey, Value: values}),
+				Limit:   maxIncludeRows + 1,
+			})
+			if err != nil {
+				return domain.NewAppError(domain.ErrInvalidInput, err.Error(), http.StatusBadRequest)
+			}
+			if children, err = conn.Query(ctx, stmt.Text, stmt.Args...); err != nil {
+				return err
+			}
+			if len(children) > maxIncludeRows {
+				return domain.NewAppError(domain.ErrRowLimitExceeded,
+					fmt.Sprintf("include %s matches more than %d related rows; lower limit or narrow the filter", plan.name, maxIncludeRows), http.StatusBadRequest).
+					WithDetails(map[string]interface{}{"include": plan.name, "maxIncludeRows": maxIncludeRows})
+			}
+			stripBlocked(children, plan.table, ds.BlockedColumns)
+
+			if err := loadIncludes(ctx, conn, translator, ds, schemaName, children, plan.children); err != nil {
+				return err
+			}
+		}
+
+		byKey := make(map[string][]map[string]any, len(children))
+		for _, child := range children {
+			key := fmt.Sprint(child[plan.childKey])
+			byKey[key] = append(byKey[key], child)
+		}
+		for _, child := range children {
+			for _, col := range plan.hidden {
+				delete(child, col)
+			}
+		}
+
+		for _, row := range rows {
+			related := byKey[fmt.Sprint(row[plan.parentKey])]
+			switch {
+			case plan.many && related == nil:
+				row[plan.name] = []map[string]any{}
+			case plan.many:
+				row[plan.name] = related
+			case len(related) > 0:
+				row[plan.name] = related[0]
+			default:
+				row[plan.name] = nil
+			}
+		}
+	}
+	return nil
+}
+
+// distinctValues coleta os valores não nulos de col, sem repetição.
+func distinctValues(rows []map[string]any, col string) []any {
+	seen := make(map[string]bool, len(rows))
+	var values []any
+	for _, row := range rows {
+		v, ok := row[col]
+		if !ok || v == nil {
+			continue
+		}
+		key := fmt.Sprint(v)
+		if !seen[key] {
+			seen[key] = true
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
+// stripBlocked remove das linhas as colunas bloqueadas da tabela.
+func stripBlocked(rows []map[string]any, table string, blocked []string) {
+	for i := range rows {
+		for _, b := range blocked {
+			parts := strings.SplitN(b, ".", 2)
+			if len(parts) == 2 && strings.EqualFold(parts[0], table) {
+				delete(rows[i], parts[1])
+			}
+		}
+	}
+}
@@ -0,0 +1,165 @@
+package data
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"api-database/internal/domain"
+	"api-database/internal/domain/apikey"
+	"api-database/internal/domain/datasource"
+	"api-database/internal/domain/schema"
+	"api-database/internal/infrastructure/postgres"
+)
+
+// sqliteRelations é aplicado depois de sqliteUsers (User.teamId → Team.id, Post.authorId → User.id).
+var sqliteRelations = []string{
+	`CREATE TABLE "Team" (id INTEGER PRIMARY KEY, name TEXT, budget INTEGER)`,
+	`INSERT INTO "Team" VALUES (10, 'Red', 100), (20, 'Blue', 200)`,
+	`ALTER TABLE "User" ADD COLUMN teamId INTEGER REFERENCES "Team"(id)`,
+	`UPDATE "User" SET teamId = 10 WHERE id IN (1, 2)`,
+	`CREATE TABLE "Post" (id INTEGER PRIMARY KEY, authorId INTEGER REFERENCES "User"(id), title TEXT)`,
+	`INSERT INTO "Post" VALUES (100, 1, 'first'), (101, 1, 'second'), (102, 2, 'third')`,
+}
+
+func TestQueryTable_SQLiteIncludes(t *testing.T) {
+	svc := newSQLiteService(t, append(append([]string{}, sqliteUsers...), sqliteRelations...)...)
+
+	resp, err := svc.QueryTable(context.Background(), adminKey, "main", "User", QueryRequest{
+		Fields:  []string{"email"},
+		OrderBy: []OrderField{{Field: "id"}},
+		Include: []IncludeField{
+			{Table: "Team", Fields: []string{"name"}},
+			{Table: "Post", Fields: []string{"title"}},
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []map[string]any{
+		{"email": "a@example.com", "Team": map[string]any{"name": "Red"}, "Post": []map[string]any{{"title": "first"}, {"title": "second"}}},
+		{"email": "b@example.com", "Team": map[string]any{"name": "Red"}, "Post": []map[string]any{{"title": "third"}}},
+		{"email": "c@example.com", "Team": nil, "Post": []map[string]any{}},
+	}, resp.Data)
+}
+
+func TestQueryTable_IncludeValidation(t *testing.T) {
+	joins := datasource.Capabilities{SupportsJoins: true, MaxDepthLimit: 1}
+	cases := map[string]struct {
+		ds   *datasource.DataSource
+		ak   *apikey.APIKey
+		inc  []IncludeField
+		code domain.ErrorCode
+	}{
+		"joins disabled": {&datasource.DataSource{Name: "main", Type: "postgres"}, adminKey,
+			[]IncludeField{{Table: "Team"}}, domain.ErrInvalidInput},
+		"too deep": {&datasource.DataSource{Name: "main", Type: "postgres", Capabilities: joins}, adminKey,
+			[]IncludeField{{Table: "Team", Include: []IncludeField{{Table: "League"}}}}, domain.ErrInvalidInput},
+		"blocked field": {&datasource.DataSource{Name: "main", Type: "postgres", Capabilities: joins, BlockedColumns: []string{"Team.budget"}}, adminKey,
+			[]IncludeField{{Table: "Team", Fields: []string{"budget"}}}, domain.ErrColumnBlocked},
+		"table forbidden": {&datasource.DataSource{Name: "main", Type: "postgres", Capabilities: joins},
+			&apikey.APIKey{Permissions: []apikey.Permission{{Resource: "main.User", Level: apikey.LevelTable}}},
+			[]IncludeField{{Table: "Team"}}, domain.ErrForbidden},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			svc, conn := newTestService(tc.ds, nil)
+			svc.connectors.(*fakeFactory).inspector = postgres.NewInspector()
+
+			_, err := svc.QueryTable(context.Background(), tc.ak, "main", "User", QueryRequest{Include: tc.inc})
+			appErr, ok := err.(*domain.AppError)
+			require.True(t, ok, "got %v", err)
+			assert.Equal(t, tc.code, appErr.Code)
+			assert.Empty(t, conn.statements)
+		})
+	}
+}
+
+func TestResolveRelation(t *testing.T) {
+	fks := []schema.ForeignKey{
+		{Name: "user_team", Table: "User", Columns: []string{"teamId"}, RefTable: "Team", RefColumns: []string{"id"}},
+		{Name: "match_home", Table: "Match", Columns: []string{"homeTeamId"}, RefTable: "Team", RefColumns: []string{"id"}},
+		{Name: "match_away", Table: "Match", Columns: []string{"awayTeamId"}, RefTable: "Team", RefColumns: []string{"id"}},
+	}
+
+	plan, err := resolveRelation("User", IncludeField{Table: "Team"}, fks)
+	require.NoError(t, err)
+	assert.Equal(t, &includePlan{table: "Team", parentKey: "teamId", childKey: "id"}, plan)
+
+	_, err = resolveRelation("Team", IncludeField{Table: "Match"}, fks)
+	appErr, ok := err.(*domain.AppError)
+	require.True(t, ok)
+	assert.Equal(t, []string{"homeTeamId", "awayTeamId"}, appErr.Details["via"])
+
+	plan, err = resolveRelation("Team", IncludeField{Table: "Match", Via: "awayTeamId"}, fks)
+	require.NoError(t, err)
+	assert.Equal(t, &includePlan{table: "Match", parentKey: "id", childKey: "awayTeamId", many: true}, plan)
+
+	_, err = resolveRelation("User", IncludeField{Table: "Match"}, fks)
+	assert.Error(t, err)
+}
+
+// tableExecutor devolve linhas por tabela citada no FROM e registra as instruções.
+type tableExecutor struct {
+	rows       map[string][]map[string]any
+	statements []string
+}
+
+func (e *tableExecutor) Query(_ context.Context, stmt string, _ ...any) ([]map[string]any, error) {
+	e.statements = append(e.statements, stmt)
+	for table, rows := range e.rows {
+		if strings.Contains(stmt, `FROM "`+table+`"`) {
+			return rows, nil
+		}
+	}
+	return nil, nil
+}
+
+func (e *tableExecutor) Execute(context.Context, string, ...any) (int64, error) { return 0, nil }
+
+func TestLoadIncludes_SingleQueryPerLevel(t *testing.T) {
+	exec := &tableExecutor{rows: map[string][]map[string]any{
+		"Post": {
+			{"id": int64(100), "authorId": int64(1)},
+			{"id": int64(101), "authorId": int64(2)},
+			{"id": int64(102), "authorId": int64(1)},
+		},
+	}}
+	rows := []map[string]any{{"id": int64(1)}, {"id": int64(2)}, {"id": int64(3)}}
+	plans := []*includePlan{{name: "Post", table: "Post", parentKey: "id", childKey: "authorId", many: true}}
+
+	err := loadIncludes(context.Background(), exec, postgres.NewTranslator(), &datasource.DataSource{}, "", rows, plans)
+	require.NoError(t, err)
+	require.Len(t, exec.statements, 1)
+	assert.Equal(t, `SELECT * FROM "Post" WHERE "authorId" = ANY($1) LIMIT $2 OFFSET $3`, exec.statements[0])
+	assert.Len(t, rows[0]["Post"], 2)
+	assert.Len(t, rows[1]["Post"], 1)
+	assert.Equal(t, []map[string]any{}, rows[2]["Post"])
+}
+
+func TestLoadIncludes_NameConflict(t *testing.T) {
+	exec := &tableExecutor{rows: map[string][]map[string]any{"Team": {{"id": int64(10)}}}}
+	rows := []map[string]any{{"id": int64(1), "teamId": int64(10), "Team": "legacy"}}
+	plans := []*includePlan{{name: "Team", table: "Team", parentKey: "teamId", childKey: "id"}}
+
+	err := loadIncludes(context.Background(), exec, postgres.NewTranslator(), &datasource.DataSource{}, "", rows, plans)
+	assertInvalidInput(t, err)
+	assert.Equal(t, "legacy", rows[0]["Team"], "the parent column is not overwritten")
+	assert.Empty(t, exec.statements)
+}
+
+func TestLoadIncludes_RowLimit(t *testing.T) {
+	posts := make([]map[string]any, maxIncludeRows+1)
+	for i := range posts {
+		posts[i] = map[string]any{"id": int64(i), "authorId": int64(i % 2)}
+	}
+	exec := &tableExecutor{rows: map[string][]map[string]any{"Post": posts}}
+	rows := []map[string]any{{"id": int64(0)}, {"id": int64(1)}}
+	plans := []*includePlan{{name: "Post", table: "Post", parentKey: "id", childKey: "authorId", many: true}}
+
+	// Acima do limite a consulta é recusada em vez de devolver listas incompletas
+	err := loadIncludes(context.Background(), exec, postgres.NewTranslator(), &datasource.DataSource{}, "", rows, plans)
+	assertCode(t, err, domain.ErrRowLimitExceeded)
+	assert.NotContains(t, rows[0], "Post")
+}
@@ -3,10 +3,13 @@ package data
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"time"
 
 	"github.com/rs/zerolog"
 
+	"api-database/internal/domain"
+	"api-database/internal/domain/apikey"
 	"api-database/internal/domain/job"
 	"api-database/internal/telemetry"
 )
@@ -26,6 +29,9 @@ type QueryJobMessage struct {
 type JobProcessor struct {
 	service *QueryService
 	jobs    job.JobRepository
+	keys    apikey.APIKeyRepository
+	results job.ResultStore
+	ttl     time.Duration
 	metrics MetricsRecorder
 	logger  zerolog.Logger
 }
@@ -35,8 +41,15 @@ type MetricsRecorder interface {
 	RecordQuery(metric telemetry.QueryMetric)
 }
 
-func NewJobProcessor(service *QueryService, jobs job.JobRepository, metrics MetricsRecorder, logger zerolog.Logger) *JobProcessor {
-	return &JobProcessor{service: service, jobs: jobs, metrics: metrics, logger: logger}
+func NewJobProcessor(service *QueryService, jobs job.JobRepository, keys apikey.APIKeyRepository, metrics MetricsRecorder, logger zerolog.Logger) *JobProcessor {
+	return &JobProcessor{service: service, jobs: jobs, keys: keys, metrics: metrics, logger: logger}
+}
+
+// WithResultStore habilita a persistência dos resultados com a expiração informada.
+func (p *JobProcessor) WithResultStore(store job.ResultStore, ttl time.Duration) *JobProcessor {
+	p.results = store
+	p.ttl = ttl
+	return p
 }
 
 // Handle decodifica a mensagem, executa a query e atualiza o job no repositório.
@@ -55,12 +68,13 @@ func (p *JobProcessor) Handle(ctx context.Context, body []byte) error {
 		Str("api_key", msg.APIKey).
 		Msg("[WORKER] processing job from queue")
 
+	ctx = withAsyncBudget(ctx)
 	start := time.Now()
 	_ = p.jobs.UpdateStatus(ctx, msg.ID, job.StatusRunning, map[string]any{
 		"startedAt": time.Now(),
 	})
 
-	resp, err := p.service.QueryTable(ctx, msg.DataSource, msg.Table, msg.Request)
+	resp, err := p.service.QueryTable(ctx, p.lookupKey(ctx, msg.APIKey), msg.DataSource, msg.Table, msg.Request)
 	tookMs := time.Since(start).Milliseconds()
 
 	if err != nil {
@@ -70,11 +84,14 @@ func (p *JobProcessor) Handle(ctx context.Context, body []byte) error {
 			Int64("took_ms", tookMs).
 			Msg("[WORKER] job failed")
 		_ = p.jobs.UpdateStatus(ctx, msg.ID, job.StatusFailed, map[string]any{
-			"error":      err.Error(),
+			"error":      ClientError(ctx, err).Message,
 			"finishedAt": time.Now(),
 			"tookMs":     tookMs,
 		})
-		p.recordMetric(msg, "error", 0, tookMs)
+		p.recordMetric(msg, MetricStatus(err), 0, tookMs)
+		if isFinalJobError(err) {
+			return nil
+		}
 		return err
 	}
 
@@ -84,16 +101,88 @@ func (p *JobProcessor) Handle(ctx context.Context, body []byte) error {
 		Int64("took_ms", resp.Metadata.TookMs).
 		Msg("[WORKER] job completed successfully")
 
-	_ = p.jobs.UpdateStatus(ctx, msg.ID, job.StatusSucceeded, map[string]any{
+	fields := map[string]any{
 		"rows":       resp.Metadata.Rows,
 		"tookMs":     resp.Metadata.TookMs,
 		"finishedAt": time.Now(),
-	})
+	}
+	for k, v := range p.storeResult(ctx, msg.ID, resp.Data) {
+		fields[k] = v
+	}
+	_ = p.jobs.UpdateStatus(ctx, msg.ID, job.StatusSucceeded, fields)
 
 	p.recordMetric(msg, "success", resp.Metadata.Rows, resp.Metadata.TookMs)
 	return nil
 }
 
+// isFinalJobError indica erros que se repetiriam a cada nova entrega: os causados pela própria
+// requisição (4xx, como permissão, colunas e filtros inválidos) e o estouro do orçamento. Só
+// falhas de infraestrutura (5xx) devolvem a mensagem para a fila.
+func isFinalJobError(err error) bool {
+	var appErr *domain.AppError
+	if !errors.As(err, &appErr) {
+		return false
+	}
+	status := appErr.Status()
+	return appErr.Code == domain.ErrQueryTimeout || (status >= 400 && status < 500)
+}
+
+// storeResult grava as linhas no ResultStore e retorna os campos a atualizar no job.
+// Falhas não invalidam o job: a consulta foi concluída, apenas o resultado não fica disponível.
+func (p *JobProcessor) storeResult(ctx context.Context, jobID string, rows []map[string]any) map[string]any {
+	if p.results == nil {
+		return nil
+	}
+	expiresAt := time.Now().Add(p.ttl)
+	size, err := p.results.Save(ctx, jobID, rows, expiresAt)
+	if err != nil {
+		p.logger.Warn().Err(err).Str("job_id", jobID).Msg("[WORKER] failed to store job result")
+		msg := "failed to store result"
+		if errors.Is(err, job.ErrResultTooLarge) {
+			msg = err.Error()
+		}
+		return map[string]any{"resultStored": false, "resultError": msg}
+	}
+	return map[string]any{
+		"resultStored":    true,
+		"resultBytes":     size,
+		"resultExpiresAt": expiresAt,
+	}
+}
+
+// RunResultJanitor remove periodicamente resultados expirados até o contexto ser cancelado.
+func RunResultJanitor(ctx context.Context, store job.ResultStore, interval time.Duration, logger zerolog.Logger) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			removed, err := store.PurgeExpired(ctx, now)
+			if err != nil {
+				logger.Warn().Err(err).Msg("failed to purge expired job results")
+				continue
+			}
+			if removed > 0 {
+				logger.Info().Int("removed", removed).Msg("expired job results purged")
+			}
+		}
+	}
+}
+
+// lookupKey recarrega a chave do job; chaves removidas ou ausentes resultam em nil (acesso negado).
+func (p *JobProcessor) lookupKey(ctx context.Context, key string) *apikey.APIKey {
+	if key == "" || p.keys == nil {
+		return nil
+	}
+	ak, err := p.keys.GetByKey(ctx, key)
+	if err != nil {
+		return nil
+	}
+	return ak
+}
+
 func (p *JobProcessor) recordMetric(msg QueryJobMessage, status string, rows int, tookMs int64) {
 	if p.metrics == nil {
 		return
@@ -107,5 +196,6 @@ func (p *JobProcessor) recordMetric(msg QueryJobMessage, status string, rows int
 		JobID:       msg.ID,
 		PayloadHash: msg.PayloadHash,
 		APIKey:      msg.APIKey,
+		Shape:       QueryShape(msg.Request),
 	})
 }
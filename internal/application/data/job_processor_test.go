@@ -0,0 +1,56 @@
+package data
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"api-database/internal/domain"
+	"api-database/internal/domain/job"
+)
+
+// fakeJobs registra as transições de status de cada job.
+type fakeJobs struct {
+	statuses []string
+}
+
+func (f *fakeJobs) Insert(context.Context, *job.QueryJob) error { return nil }
+
+func (f *fakeJobs) UpdateStatus(_ context.Context, _ string, status string, _ map[string]any) error {
+	f.statuses = append(f.statuses, status)
+	return nil
+}
+
+func (f *fakeJobs) GetByID(context.Context, string) (*job.QueryJob, error) { return nil, nil }
+
+func (f *fakeJobs) GetByPayloadHash(context.Context, string) ([]*job.QueryJob, error) {
+	return nil, nil
+}
+
+func TestJobProcessor_RequestErrorsAreNotRequeued(t *testing.T) {
+	svc := newSQLiteService(t, sqliteUsers...)
+	svc.DisableAuthorization()
+	jobs := &fakeJobs{}
+	processor := NewJobProcessor(svc, jobs, nil, nil, zerolog.Nop())
+
+	body, err := json.Marshal(QueryJobMessage{ID: "job-1", DataSource: "main", Table: "User", Request: QueryRequest{Fields: []string{"missing"}}})
+	require.NoError(t, err)
+
+	// nil confirma a mensagem (Ack); um erro a devolveria para a fila
+	assert.NoError(t, processor.Handle(context.Background(), body))
+	assert.Equal(t, []string{job.StatusRunning, job.StatusFailed}, jobs.statuses)
+}
+
+func TestIsFinalJobError(t *testing.T) {
+	assert.True(t, isFinalJobError(domain.NewAppError(domain.ErrUnknownColumn, "unknown column", http.StatusBadRequest)))
+	assert.True(t, isFinalJobError(domain.NewAppError(domain.ErrDataSourceNotFound, "datasource not found", http.StatusNotFound)))
+	assert.True(t, isFinalJobError(domain.NewAppError(domain.ErrQueryTimeout, "query timed out", http.StatusGatewayTimeout)))
+	assert.False(t, isFinalJobError(domain.NewAppError(domain.ErrInternal, "connection refused", http.StatusServiceUnavailable)))
+	assert.False(t, isFinalJobError(errors.New("connection reset")))
+}
@@ -0,0 +1,97 @@
+package data
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"api-database/internal/domain"
+	"api-database/internal/domain/apikey"
+)
+
+// defaultBatchWorkers limita as consultas simultâneas de QueryBatch quando WithBatchWorkers
+// não é configurado.
+const defaultBatchWorkers = 4
+
+// maxBatchQueries limita o número de consultas de POST /data/_batch.
+const maxBatchQueries = 50
+
+// BatchQuery é uma consulta de POST /data/_batch; Request segue o formato de QueryRequest.
+type BatchQuery struct {
+	Source  string          `json:"source"`
+	Table   string          `json:"table"`
+	Request json.RawMessage `json:"request"`
+}
+
+// BatchQueryResult traz a resposta ou o erro de uma consulta, com o status HTTP equivalente.
+type BatchQueryResult struct {
+	Status   int              `json:"status"`
+	Response *QueryResponse   `json:"response,omitempty"`
+	Error    *domain.AppError `json:"error,omitempty"`
+	// Shape é o formato da consulta (QueryShape), usado nas métricas; vazio se o corpo é inválido.
+	Shape string `json:"-"`
+}
+
+// WithBatchWorkers define quantas consultas de um batch rodam ao mesmo tempo (BATCH_WORKERS).
+func (s *QueryService) WithBatchWorkers(workers int) *QueryService {
+	if workers > 0 {
+		s.batchWorkers = workers
+	}
+	return s
+}
+
+// QueryBatch executa consultas independentes (como QueryTable) em um pool limitado de workers.
+// Os resultados seguem a ordem do pedido e a falha de uma consulta não afeta as demais.
+func (s *QueryService) QueryBatch(ctx context.Context, ak *apikey.APIKey, queries []BatchQuery) ([]BatchQueryResult, error) {
+	if len(queries) == 0 {
+		return nil, invalidInput("batch requires at least one query")
+	}
+	if len(queries) > maxBatchQueries {
+		return nil, invalidInput(fmt.Sprintf("batch accepts at most %d queries", maxBatchQueries))
+	}
+
+	results := make([]BatchQueryResult, len(queries))
+	pending := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < min(s.batchWorkers, len(queries)); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range pending {
+				results[i] = s.batchQuery(ctx, ak, queries[i])
+			}
+		}()
+	}
+	for i := range queries {
+		pending <- i
+	}
+	close(pending)
+	wg.Wait()
+	return results, nil
+}
+
+func (s *QueryService) batchQuery(ctx context.Context, ak *apikey.APIKey, q BatchQuery) BatchQueryResult {
+	body := q.Request
+	if len(body) == 0 {
+		body = json.RawMessage("{}")
+	}
+	var req QueryRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		return failedQuery(ctx, decodeFailure(err), "")
+	}
+
+	shape := QueryShape(req)
+	resp, err := s.QueryTable(ctx, ak, q.Source, q.Table, req)
+	if err != nil {
+		return failedQuery(ctx, err, shape)
+	}
+	return BatchQueryResult{Status: http.StatusOK, Response: resp, Shape: shape}
+}
+
+// failedQuery converte o erro no AppError do item (ver ClientError).
+func failedQuery(ctx context.Context, err error, shape string) BatchQueryResult {
+	appErr := ClientError(ctx, err)
+	return BatchQueryResult{Status: appErr.Status(), Error: appErr, Shape: shape}
+}
@@ -0,0 +1,100 @@
+package data
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"api-database/internal/domain"
+	"api-database/internal/domain/apikey"
+	"api-database/internal/domain/datasource"
+)
+
+func TestQueryBatch_SQLite(t *testing.T) {
+	svc := newSQLiteService(t, sqliteUsers...)
+	userOnly := &apikey.APIKey{Permissions: []apikey.Permission{{Resource: "main.User", Level: apikey.LevelTable}}}
+
+	results, err := svc.QueryBatch(context.Background(), userOnly, []BatchQuery{
+		{Source: "main", Table: "User", Request: json.RawMessage(`{"filter": {"role": {"$eq": "PILOT"}}, "countTotal": true}`)},
+		{Source: "main", Table: "Other"},
+		{Source: "main", Table: "User", Request: json.RawMessage(`{"filter": {"role": {"$regex": "P"}}}`)},
+		{Source: "main", Table: "User", Request: json.RawMessage(`{"fields": ["id"], "limit": 1}`)},
+	})
+	require.NoError(t, err)
+	require.Len(t, results, 4)
+
+	assert.Equal(t, http.StatusOK, results[0].Status)
+	assert.Equal(t, int64(2), *results[0].Response.Metadata.Total)
+	assert.NotContains(t, results[0].Response.Data[0], "passwordHash")
+	assert.NotEmpty(t, results[0].Shape)
+
+	assert.Equal(t, http.StatusForbidden, results[1].Status)
+	assert.Equal(t, domain.ErrForbidden, results[1].Error.Code)
+	assert.Equal(t, http.StatusBadRequest, results[2].Status)
+	assert.Equal(t, domain.ErrInvalidInput, results[2].Error.Code)
+
+	assert.Equal(t, http.StatusOK, results[3].Status)
+	assert.Equal(t, []map[string]any{{"id": int64(1)}}, results[3].Response.Data)
+}
+
+func TestQueryBatch_Limits(t *testing.T) {
+	svc := newSQLiteService(t, sqliteUsers...)
+	ctx := context.Background()
+
+	_, err := svc.QueryBatch(ctx, adminKey, nil)
+	assertCode(t, err, domain.ErrInvalidInput)
+	_, err = svc.QueryBatch(ctx, adminKey, make([]BatchQuery, maxBatchQueries+1))
+	assertCode(t, err, domain.ErrInvalidInput)
+}
+
+// slowConnector mede quantas consultas estão em andamento ao mesmo tempo.
+type slowConnector struct {
+	*fakeConnector
+	active, peak atomic.Int32
+}
+
+func (c *slowConnector) Query(context.Context, string, ...any) ([]map[string]any, error) {
+	n := c.active.Add(1)
+	defer c.active.Add(-1)
+	for {
+		peak := c.peak.Load()
+		if n <= peak || c.peak.CompareAndSwap(peak, n) {
+			break
+		}
+	}
+	time.Sleep(10 * time.Millisecond)
+	return []map[string]any{{"id": 1}}, nil
+}
+
+type slowFactory struct {
+	fakeFactory
+	conn *slowConnector
+}
+
+func (f *slowFactory) Connector(context.Context, *datasource.DataSource) (datasource.DatabaseConnector, func(), error) {
+	return f.conn, func() {}, nil
+}
+
+func TestQueryBatch_BoundsConcurrency(t *testing.T) {
+	conn := &slowConnector{fakeConnector: &fakeConnector{}}
+	repo := &fakeRepo{sources: map[string]*datasource.DataSource{"main": {Name: "main", Type: "postgres"}}}
+	svc := NewQueryService(repo, &slowFactory{conn: conn}).WithBatchWorkers(3)
+
+	queries := make([]BatchQuery, 12)
+	for i := range queries {
+		queries[i] = BatchQuery{Source: "main", Table: "User"}
+	}
+	results, err := svc.QueryBatch(context.Background(), adminKey, queries)
+	require.NoError(t, err)
+	for _, result := range results {
+		assert.Equal(t, http.StatusOK, result.Status)
+	}
+	assert.LessOrEqual(t, conn.peak.Load(), int32(3))
+	assert.Greater(t, conn.peak.Load(), int32(1))
+}
@@ -9,34 +9,64 @@ import (
 	"time"
 
 	"api-database/internal/domain"
+	"api-database/internal/domain/apikey"
+	"api-database/internal/domain/cache"
 	"api-database/internal/domain/datasource"
-	"api-database/internal/infrastructure/postgres"
+	"api-database/internal/domain/query"
+	"api-database/internal/domain/schema"
 )
 
 // QueryService executa consultas simples em uma tabela de um datasource.
 type QueryService struct {
-	repo datasource.DataSourceRepository
+	repo              datasource.DataSourceRepository
+	connectors        datasource.ConnectorFactory
+	cache             cache.Cache
+	schemas           *schemaCache
+	cursorSecret      []byte
+	queryTimeout      time.Duration
+	asyncTimeout      time.Duration
+	exportMaxRows     int
+	batchWorkers      int
+	skipAuthorization bool
 }
 
-func NewQueryService(repo datasource.DataSourceRepository) *QueryService {
-	return &QueryService{repo: repo}
+func NewQueryService(repo datasource.DataSourceRepository, connectors datasource.ConnectorFactory) *QueryService {
+	return &QueryService{repo: repo, connectors: connectors, schemas: newSchemaCache(), cursorSecret: randomSecret(),
+		queryTimeout: defaultQueryTimeout, asyncTimeout: defaultAsyncQueryTimeout, exportMaxRows: defaultMaxExportRows,
+		batchWorkers: defaultBatchWorkers}
+}
+
+// DisableAuthorization desliga a checagem de permissões (usado com AUTH_MODE=disabled).
+func (s *QueryService) DisableAuthorization() {
+	s.skipAuthorization = true
 }
 
 // QueryRequest define entrada mínima para teste inicial.
 type QueryRequest struct {
-	Schema     string                 `json:"schema"`
-	Limit      int                    `json:"limit"`
-	Offset     int                    `json:"offset"`
-	CountTotal bool                   `json:"countTotal"`
-	OrderBy    []OrderField           `json:"orderBy"`
-	Filter     map[string]FilterField `json:"filter"`
-	Fields     []string               `json:"fields"` // Colunas a retornar; se vazio, SELECT *
+	Schema     string       `json:"schema"`
+	Limit      int          `json:"limit"`
+	Offset     int          `json:"offset"`
+	CountTotal bool         `json:"countTotal"`
+	OrderBy    []OrderField `json:"orderBy"`
+	Filter     Filter       `json:"filter"`
+	Fields     []string     `json:"fields"` // Colunas a retornar; se vazio, SELECT *
+	// Cursor ativa a paginação keyset: "" na primeira página, depois o nextCursor recebido.
+	Cursor *string `json:"cursor,omitempty"`
+	// GroupBy, Aggregates e Having transformam a consulta em agregação.
+	GroupBy    []string         `json:"groupBy,omitempty"`
+	Aggregates []AggregateField `json:"aggregates,omitempty"`
+	Having     Filter           `json:"having"`
+	// Include anexa linhas relacionadas por chave estrangeira.
+	Include []IncludeField `json:"include,omitempty"`
+	// ColumnTypes pede metadata.columns com o tipo de cada coluna (datasources que o suportam).
+	ColumnTypes bool `json:"columnTypes,omitempty"`
 }
 
 // QueryResponse retorna dados e metadados simples.
 type QueryResponse struct {
 	Data     []map[string]any `json:"data"`
 	Metadata Meta             `json:"metadata"`
+	Cache    string           `json:"-"` // HIT|MISS quando o cache está habilitado
 }
 
 // Meta inclui info básica.
@@ -45,6 +75,10 @@ type Meta struct {
 	Table  string `json:"table"`
 	TookMs int64  `json:"tookMs"`
 	Total  *int64 `json:"total,omitempty"`
+	// NextCursor é preenchido na paginação keyset quando a página veio cheia.
+	NextCursor string `json:"nextCursor,omitempty"`
+	// Columns descreve as colunas do resultado quando a requisição pede columnTypes.
+	Columns []datasource.ResultColumn `json:"columns,omitempty"`
 }
 
 // OrderField define campo e direção.
@@ -53,215 +87,297 @@ type OrderField struct {
 	Direction string `json:"direction"`
 }
 
-// FilterField suporta comparações simples.
-type FilterField struct {
-	Eq  any `json:"$eq,omitempty"`
-	Gt  any `json:"$gt,omitempty"`
-	Gte any `json:"$gte,omitempty"`
-	Lt  any `json:"$lt,omitempty"`
-	Lte any `json:"$lte,omitempty"`
-}
-
 var tableNameRegex = regexp.MustCompile(`^[A-Za-z0-9_]+$`)
-var directionRegex = regexp.MustCompile(`^(?i)(asc|desc)$`)
 var columnRegex = regexp.MustCompile(`^[A-Za-z0-9_]+$`)
 
 const defaultMaxLimit = 500
 
-func (s *QueryService) QueryTable(ctx context.Context, sourceName, table string, req QueryRequest) (*QueryResponse, error) {
+// QueryTable executa a consulta após validar as permissões da chave informada. A consulta
+// inteira (catálogo, includes e contagem) respeita o prazo resolvido por timeout.
+func (s *QueryService) QueryTable(ctx context.Context, ak *apikey.APIKey, sourceName, table string, req QueryRequest) (_ *QueryResponse, err error) {
 	if !tableNameRegex.MatchString(table) {
 		return nil, domain.NewAppError(domain.ErrInvalidTable, "invalid table name", http.StatusBadRequest)
 	}
 	if req.Schema != "" && !tableNameRegex.MatchString(req.Schema) {
 		return nil, domain.NewAppError(domain.ErrInvalidSchema, "invalid schema name", http.StatusBadRequest)
 	}
+	if err := s.Authorize(ak, sourceName, table, req); err != nil {
+		return nil, err
+	}
 
 	ds, err := s.repo.GetByName(ctx, sourceName)
 	if err != nil {
 		return nil, domain.NewAppError(domain.ErrDataSourceNotFound, "datasource not found", http.StatusNotFound)
 	}
-	if ds.Type != "postgres" {
-		return nil, domain.NewAppError(domain.ErrUnsupportedType, "only postgres is supported", http.StatusBadRequest)
+	translator, ok := s.connectors.Translator(ds.Type)
+	if !ok {
+		return nil, domain.NewAppError(domain.ErrUnsupportedType, fmt.Sprintf("unsupported datasource type: %s", ds.Type), http.StatusBadRequest)
 	}
 
-	// Validar colunas bloqueadas em filter
-	for col := range req.Filter {
-		if isColumnBlocked(table, col, ds.BlockedColumns) {
-			return nil, domain.NewAppError(domain.ErrColumnBlocked, fmt.Sprintf("column blocked: %s", col), http.StatusForbidden)
+	timeout := s.timeout(ctx, ds)
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	defer func() {
+		if err != nil {
+			err = timeoutError(ctx, err, timeout)
 		}
-	}
+	}()
 
-	// Validar colunas bloqueadas em orderBy
-	for _, o := range req.OrderBy {
-		if isColumnBlocked(table, o.Field, ds.BlockedColumns) {
-			return nil, domain.NewAppError(domain.ErrColumnBlocked, fmt.Sprintf("column blocked: %s", o.Field), http.StatusForbidden)
-		}
+	if err := checkRequestColumns(table, ds, req); err != nil {
+		return nil, err
 	}
 
-	limit := req.Limit
-	if limit <= 0 {
-		limit = 100
-	}
-	maxLimit := defaultMaxLimit
-	if ds.Limits.MaxRows > 0 && ds.Limits.MaxRows < maxLimit {
-		maxLimit = ds.Limits.MaxRows
-	}
-	if limit > maxLimit {
-		limit = maxLimit
-	}
+	limit := pageLimit(ds, req.Limit)
 	offset := req.Offset
 	if offset < 0 {
 		offset = 0
 	}
 
-	// Validar fields contra blockedColumns
-	for _, field := range req.Fields {
-		if !columnRegex.MatchString(field) {
-			return nil, domain.NewAppError(domain.ErrInvalidInput, fmt.Sprintf("invalid column name: %s", field), http.StatusBadRequest)
+	agg, err := buildAggregation(table, ds, req)
+	if err != nil {
+		return nil, err
+	}
+	var inspector schema.Inspector
+	if len(req.Include) > 0 {
+		if agg != nil {
+			return nil, invalidInput("include cannot be combined with aggregations")
 		}
-		if isColumnBlocked(table, field, ds.BlockedColumns) {
-			return nil, domain.NewAppError(domain.ErrColumnBlocked, fmt.Sprintf("column blocked: %s", field), http.StatusForbidden)
+		if inspector, ok = s.connectors.Inspector(ds.Type); !ok {
+			return nil, invalidInput(fmt.Sprintf("include is not supported for datasource type: %s", ds.Type))
+		}
+		if err := s.validateIncludes(ak, ds, req.Include); err != nil {
+			return nil, err
 		}
 	}
 
-	conn, err := postgres.NewConnector(ctx, ds.Connection.Host, ds.Connection.Port, ds.Connection.User, ds.Connection.Password, ds.Connection.Database, ds.Connection.SSLMode)
-	if err != nil {
-		return nil, err
+	order := buildOrder(req.OrderBy)
+	columns := req.Fields
+	var ks *keyset
+	if req.Cursor != nil {
+		if ks, err = s.buildKeyset(ak, ds, table, req, order); err != nil {
+			return nil, err
+		}
+		order = ks.order
+		offset = 0
+		if len(ks.extra) > 0 {
+			columns = append(append([]string{}, req.Fields...), ks.extra...)
+		}
 	}
-	defer conn.Close()
 
-	fullTable := quoteIdent(table)
-	if req.Schema != "" {
-		fullTable = fmt.Sprintf("%s.%s", quoteIdent(req.Schema), quoteIdent(table))
+	if err := s.validateColumns(ctx, ak, ds, table, req, ks); err != nil {
+		return nil, err
 	}
 
-	// Construir SELECT com fields ou *
-	var selectClause string
-	if len(req.Fields) > 0 {
-		cols := make([]string, len(req.Fields))
-		for i, f := range req.Fields {
-			cols[i] = quoteIdent(f)
+	key, group, cacheable := cacheKey(ds, table, req)
+	if cacheable {
+		if cached, ok := s.cachedResponse(key); ok {
+			return cached, nil
 		}
-		selectClause = strings.Join(cols, ", ")
-	} else {
-		selectClause = "*"
 	}
 
-	whereClause, params := buildWhere(req.Filter)
-	orderClause := buildOrder(req.OrderBy)
+	conn, release, err := s.connectors.Connector(ctx, ds)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
 
-	paramIdx := len(params) + 1
-	params = append(params, limit)
-	params = append(params, offset)
+	// Chaves de junção ausentes em fields são consultadas e removidas no fim.
+	var includes []*includePlan
+	var hidden []string
+	if inspector != nil {
+		if includes, err = planIncludes(ctx, inspector, conn, ds, req.Schema, table, req.Include); err != nil {
+			return nil, err
+		}
+		for _, plan := range includes {
+			if len(columns) > 0 && !containsColumn(columns, plan.parentKey) {
+				columns = append(append([]string{}, columns...), plan.parentKey)
+				hidden = append(hidden, plan.parentKey)
+			}
+		}
+	}
 
-	query := fmt.Sprintf("SELECT %s FROM %s %s %s LIMIT $%d OFFSET $%d", selectClause, fullTable, whereClause, orderClause, paramIdx, paramIdx+1)
+	where, err := buildFilter(req.Filter, ds.Capabilities.MaxDepthLimit)
+	if err != nil {
+		return nil, err
+	}
+	spec := query.Select{
+		Schema:  req.Schema,
+		Table:   table,
+		Columns: columns,
+		Where:   where,
+		OrderBy: order,
+		Limit:   limit,
+		Offset:  offset,
+	}
+	if ks != nil {
+		spec.After = ks.after
+	}
+	if agg != nil {
+		spec.GroupBy = agg.groupBy
+		spec.Aggregates = agg.aggregates
+		spec.Having = agg.having
+	}
+	stmt, err := translator.Select(spec)
+	if err != nil {
+		return nil, domain.NewAppError(domain.ErrInvalidInput, err.Error(), http.StatusBadRequest)
+	}
 
 	start := time.Now()
-	rows, err := conn.Query(ctx, query, params...)
+	rows, resultColumns, err := queryWithColumns(ctx, conn, req.ColumnTypes, stmt)
 	if err != nil {
 		return nil, err
 	}
 	took := time.Since(start).Milliseconds()
 
 	// Remover colunas bloqueadas da resposta
+	stripBlocked(rows, table, ds.BlockedColumns)
+
+	if err := loadIncludes(ctx, conn, translator, ds, req.Schema, rows, includes); err != nil {
+		return nil, err
+	}
+
+	var nextCursor string
+	if ks != nil {
+		if nextCursor, err = s.next(ks, rows, limit); err != nil {
+			return nil, err
+		}
+		hidden = append(hidden, ks.extra...)
+	}
 	for i := range rows {
-		for _, blocked := range ds.BlockedColumns {
-			parts := strings.SplitN(blocked, ".", 2)
-			if len(parts) == 2 && strings.EqualFold(parts[0], table) {
-				delete(rows[i], parts[1])
-			}
+		for _, col := range hidden {
+			delete(rows[i], col)
 		}
 	}
+	resultColumns = visibleResultColumns(resultColumns, table, ds, hidden)
 
+	// Nas páginas seguintes do cursor o total não muda; evitar o COUNT completo.
 	var totalPtr *int64
-	if req.CountTotal {
-		countQuery := fmt.Sprintf("SELECT COUNT(1) AS total FROM %s %s", fullTable, whereClause)
-		totalRows, err := conn.Query(ctx, countQuery, params[:len(params)-2]...)
-		if err == nil && len(totalRows) > 0 {
-			if v, ok := totalRows[0]["total"].(int64); ok {
-				totalPtr = &v
+	if req.CountTotal && (ks == nil || ks.after == nil) {
+		countStmt, err := translator.Count(spec)
+		if err == nil {
+			totalRows, err := conn.Query(ctx, countStmt.Text, countStmt.Args...)
+			if err == nil {
+				totalPtr = countFromRows(totalRows)
 			}
 		}
 	}
 
-	return &QueryResponse{
+	resp := &QueryResponse{
 		Data: rows,
 		Metadata: Meta{
-			Rows:   len(rows),
-			Table:  table,
-			TookMs: took,
-			Total:  totalPtr,
+			Rows:       len(rows),
+			Table:      table,
+			TookMs:     took,
+			Total:      totalPtr,
+			NextCursor: nextCursor,
+			Columns:    resultColumns,
 		},
-	}, nil
-}
-
-func quoteIdent(name string) string {
-	return "\"" + strings.ReplaceAll(name, "\"", "\"\"") + "\""
+	}
+	if s.cache != nil && cacheable {
+		s.storeResponse(key, group, resp)
+		miss := *resp
+		miss.Cache = CacheMiss
+		return &miss, nil
+	}
+	return resp, nil
 }
 
-func buildWhere(filters map[string]FilterField) (string, []any) {
-	if len(filters) == 0 {
-		return "", nil
-	}
-	var clauses []string
-	var params []any
-	idx := 1
-	for col, f := range filters {
-		if !columnRegex.MatchString(col) {
-			continue
-		}
-		colIdent := quoteIdent(col)
-		if f.Eq != nil {
-			clauses = append(clauses, fmt.Sprintf("%s = $%d", colIdent, idx))
-			params = append(params, f.Eq)
-			idx++
-		}
-		if f.Gt != nil {
-			clauses = append(clauses, fmt.Sprintf("%s > $%d", colIdent, idx))
-			params = append(params, f.Gt)
-			idx++
+// checkRequestColumns valida nomes e colunas bloqueadas em filter (todos os níveis de
+// $and/$or/$not), orderBy e fields.
+func checkRequestColumns(table string, ds *datasource.DataSource, req QueryRequest) error {
+	for _, col := range req.Filter.columns() {
+		if isColumnBlocked(table, col, ds.BlockedColumns) {
+			return domain.NewAppError(domain.ErrColumnBlocked, fmt.Sprintf("column blocked: %s", col), http.StatusForbidden)
 		}
-		if f.Gte != nil {
-			clauses = append(clauses, fmt.Sprintf("%s >= $%d", colIdent, idx))
-			params = append(params, f.Gte)
-			idx++
+	}
+	for _, o := range req.OrderBy {
+		if isColumnBlocked(table, o.Field, ds.BlockedColumns) {
+			return domain.NewAppError(domain.ErrColumnBlocked, fmt.Sprintf("column blocked: %s", o.Field), http.StatusForbidden)
 		}
-		if f.Lt != nil {
-			clauses = append(clauses, fmt.Sprintf("%s < $%d", colIdent, idx))
-			params = append(params, f.Lt)
-			idx++
+	}
+	for _, field := range req.Fields {
+		if !columnRegex.MatchString(field) {
+			return domain.NewAppError(domain.ErrInvalidInput, fmt.Sprintf("invalid column name: %s", field), http.StatusBadRequest)
 		}
-		if f.Lte != nil {
-			clauses = append(clauses, fmt.Sprintf("%s <= $%d", colIdent, idx))
-			params = append(params, f.Lte)
-			idx++
+		if isColumnBlocked(table, field, ds.BlockedColumns) {
+			return domain.NewAppError(domain.ErrColumnBlocked, fmt.Sprintf("column blocked: %s", field), http.StatusForbidden)
 		}
 	}
-	if len(clauses) == 0 {
-		return "", params
+	return nil
+}
+
+// pageLimit aplica o padrão (100) e o teto (500 ou Limits.MaxRows, se menor) ao limit pedido.
+func pageLimit(ds *datasource.DataSource, requested int) int {
+	limit := requested
+	if limit <= 0 {
+		limit = 100
+	}
+	maxLimit := defaultMaxLimit
+	if ds.Limits.MaxRows > 0 && ds.Limits.MaxRows < maxLimit {
+		maxLimit = ds.Limits.MaxRows
+	}
+	if limit > maxLimit {
+		limit = maxLimit
 	}
-	return "WHERE " + strings.Join(clauses, " AND "), params
+	return limit
 }
 
-func buildOrder(order []OrderField) string {
-	if len(order) == 0 {
-		return ""
+// queryWithColumns executa a consulta principal; com columnTypes, usa ColumnDescriber quando o
+// connector o implementa (os demais respondem sem metadata.columns).
+func queryWithColumns(ctx context.Context, conn datasource.DatabaseConnector, withColumns bool, stmt query.Statement) ([]map[string]any, []datasource.ResultColumn, error) {
+	if describer, ok := conn.(datasource.ColumnDescriber); ok && withColumns {
+		return describer.QueryColumns(ctx, stmt.Text, stmt.Args...)
 	}
-	var clauses []string
-	for _, o := range order {
-		if !columnRegex.MatchString(o.Field) {
+	rows, err := conn.Query(ctx, stmt.Text, stmt.Args...)
+	return rows, nil, err
+}
+
+// visibleResultColumns remove da descrição as colunas bloqueadas e as auxiliares (hidden)
+// que também são removidas das linhas.
+func visibleResultColumns(columns []datasource.ResultColumn, table string, ds *datasource.DataSource, hidden []string) []datasource.ResultColumn {
+	if columns == nil {
+		return nil
+	}
+	visible := make([]datasource.ResultColumn, 0, len(columns))
+	for _, c := range columns {
+		if isColumnBlocked(table, c.Name, ds.BlockedColumns) || containsColumn(hidden, c.Name) {
 			continue
 		}
-		dir := "ASC"
-		if directionRegex.MatchString(o.Direction) {
-			dir = strings.ToUpper(o.Direction)
+		visible = append(visible, c)
+	}
+	return visible
+}
+
+// countFromRows extrai "total" do resultado de Count. Alguns dialetos (ex.: aggregate com
+// $count no Mongo) não retornam linha quando nada casa com o filtro.
+func countFromRows(rows []map[string]any) *int64 {
+	var total int64
+	if len(rows) > 0 {
+		switch v := rows[0]["total"].(type) {
+		case int64:
+			total = v
+		case int32:
+			total = int64(v)
+		case int:
+			total = int64(v)
+		case float64:
+			total = int64(v)
+		default:
+			return nil
 		}
-		clauses = append(clauses, fmt.Sprintf("%s %s", quoteIdent(o.Field), dir))
 	}
-	if len(clauses) == 0 {
-		return ""
+	return &total
+}
+
+func buildOrder(order []OrderField) []query.Order {
+	var result []query.Order
+	for _, o := range order {
+		if !columnRegex.MatchString(o.Field) {
+			continue
+		}
+		result = append(result, query.Order{Column: o.Field, Desc: strings.EqualFold(o.Direction, "desc")})
 	}
-	return "ORDER BY " + strings.Join(clauses, ", ")
+	return result
 }
 
 // isColumnBlocked verifica se table.column está na lista de bloqueados
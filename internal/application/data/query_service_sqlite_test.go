@@ -0,0 +1,145 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"api-database/internal/domain/datasource"
+	"api-database/internal/infrastructure/connector"
+	"api-database/internal/infrastructure/sqlite"
+)
+
+// newSQLiteService cria um datasource SQLite em arquivo temporário e executa o setup informado.
+func newSQLiteService(t *testing.T, setup ...string) *QueryService {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "main.db")
+
+	db, err := sql.Open("sqlite", path)
+	require.NoError(t, err)
+	for _, stmt := range setup {
+		_, err := db.Exec(stmt)
+		require.NoError(t, err)
+	}
+	require.NoError(t, db.Close())
+
+	factory := connector.NewFactory()
+	factory.Register("sqlite", connector.Driver{Open: sqlite.Open, Translator: sqlite.NewTranslator(), Inspector: sqlite.NewInspector()})
+	t.Cleanup(factory.Close)
+
+	repo := &fakeRepo{sources: map[string]*datasource.DataSource{
+		"main": {
+			Name:           "main",
+			Type:           "sqlite",
+			Connection:     datasource.Connection{Database: path},
+			BlockedColumns: []string{"User.passwordHash"},
+			Capabilities:   datasource.Capabilities{SupportsJoins: true},
+		},
+	}}
+	return NewQueryService(repo, factory)
+}
+
+var sqliteUsers = []string{
+	`CREATE TABLE "User" (id INTEGER PRIMARY KEY, email TEXT, role TEXT, active BOOLEAN, passwordHash TEXT)`,
+	`INSERT INTO "User" VALUES (1, 'a@example.com', 'PILOT', 1, 'x'), (2, 'b@example.com', 'ADMIN', 0, 'y'), (3, 'c@example.com', 'PILOT', 1, 'z')`,
+}
+
+func TestQueryTable_SQLite(t *testing.T) {
+	svc := newSQLiteService(t, sqliteUsers...)
+
+	resp, err := svc.QueryTable(context.Background(), adminKey, "main", "User", QueryRequest{
+		Filter:     Filter{Columns: map[string]FilterField{"role": {Eq: "PILOT"}}},
+		OrderBy:    []OrderField{{Field: "id", Direction: "desc"}},
+		Limit:      1,
+		CountTotal: true,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []map[string]any{
+		{"id": int64(3), "email": "c@example.com", "role": "PILOT", "active": true},
+	}, resp.Data)
+	require.NotNil(t, resp.Metadata.Total)
+	assert.Equal(t, int64(2), *resp.Metadata.Total)
+}
+
+func TestQueryTable_SQLiteCursorPagination(t *testing.T) {
+	svc := newSQLiteService(t, sqliteUsers...)
+	first := ""
+	req := QueryRequest{
+		Fields:     []string{"email"},
+		OrderBy:    []OrderField{{Field: "role", Direction: "asc"}},
+		Limit:      2,
+		CountTotal: true,
+		Cursor:     &first,
+	}
+
+	page1, err := svc.QueryTable(context.Background(), adminKey, "main", "User", req)
+	require.NoError(t, err)
+	assert.Equal(t, []map[string]any{{"email": "b@example.com"}, {"email": "a@example.com"}}, page1.Data)
+	require.NotNil(t, page1.Metadata.Total)
+	require.NotEmpty(t, page1.Metadata.NextCursor)
+
+	req.Cursor = &page1.Metadata.NextCursor
+	page2, err := svc.QueryTable(context.Background(), adminKey, "main", "User", req)
+	require.NoError(t, err)
+	assert.Equal(t, []map[string]any{{"email": "c@example.com"}}, page2.Data)
+	assert.Nil(t, page2.Metadata.Total)
+	assert.Empty(t, page2.Metadata.NextCursor)
+}
+
+func TestQueryTable_SQLiteCursorPaginationWithNulls(t *testing.T) {
+	svc := newSQLiteService(t,
+		`CREATE TABLE "User" (id INTEGER PRIMARY KEY, email TEXT, role TEXT, active BOOLEAN, passwordHash TEXT)`,
+		`INSERT INTO "User" (id, role) VALUES (1, 'B'), (2, NULL), (3, 'A'), (4, NULL), (5, 'B'), (6, 'C')`,
+	)
+
+	pageAll := func(direction string) []any {
+		first := ""
+		req := QueryRequest{
+			Fields:  []string{"id"},
+			OrderBy: []OrderField{{Field: "role", Direction: direction}},
+			Limit:   2,
+			Cursor:  &first,
+		}
+		var ids []any
+		for pages := 0; pages < 10; pages++ {
+			resp, err := svc.QueryTable(context.Background(), adminKey, "main", "User", req)
+			require.NoError(t, err)
+			for _, row := range resp.Data {
+				ids = append(ids, row["id"])
+			}
+			if resp.Metadata.NextCursor == "" {
+				return ids
+			}
+			req.Cursor = &resp.Metadata.NextCursor
+		}
+		t.Fatal("pagination did not finish")
+		return nil
+	}
+
+	// NULL é o maior valor: último em ASC, primeiro em DESC; nenhuma linha se perde
+	assert.Equal(t, []any{int64(3), int64(1), int64(5), int64(6), int64(2), int64(4)}, pageAll("asc"))
+	assert.Equal(t, []any{int64(4), int64(2), int64(6), int64(5), int64(1), int64(3)}, pageAll("desc"))
+}
+
+func TestQueryTable_SQLiteAggregation(t *testing.T) {
+	svc := newSQLiteService(t, sqliteUsers...)
+
+	resp, err := svc.QueryTable(context.Background(), adminKey, "main", "User", QueryRequest{
+		GroupBy:    []string{"role"},
+		Aggregates: []AggregateField{{Function: "count", Alias: "users"}, {Function: "max", Column: "id"}},
+		Having:     Filter{Columns: map[string]FilterField{"users": {Gte: float64(1)}}},
+		OrderBy:    []OrderField{{Field: "role"}},
+		CountTotal: true,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []map[string]any{
+		{"role": "ADMIN", "users": int64(1), "max_id": int64(2)},
+		{"role": "PILOT", "users": int64(2), "max_id": int64(3)},
+	}, resp.Data)
+	require.NotNil(t, resp.Metadata.Total)
+	assert.Equal(t, int64(2), *resp.Metadata.Total)
+}
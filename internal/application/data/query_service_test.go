@@ -0,0 +1,193 @@
+package data
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"api-database/internal/domain"
+	"api-database/internal/domain/apikey"
+	"api-database/internal/domain/datasource"
+	"api-database/internal/domain/query"
+	"api-database/internal/domain/schema"
+	"api-database/internal/infrastructure/memcache"
+	"api-database/internal/infrastructure/postgres"
+)
+
+type fakeRepo struct {
+	sources map[string]*datasource.DataSource
+}
+
+func (f *fakeRepo) GetByName(_ context.Context, name string) (*datasource.DataSource, error) {
+	if ds, ok := f.sources[name]; ok {
+		return ds, nil
+	}
+	return nil, errors.New("not found")
+}
+
+func (f *fakeRepo) ListAll(context.Context) ([]*datasource.DataSource, error) { return nil, nil }
+
+// fakeConnector registra as instruções recebidas e devolve linhas fixas.
+type fakeConnector struct {
+	rows       []map[string]any
+	columns    []datasource.ResultColumn
+	statements []query.Statement
+}
+
+func (c *fakeConnector) Query(_ context.Context, stmt string, args ...any) ([]map[string]any, error) {
+	c.statements = append(c.statements, query.Statement{Text: stmt, Args: args})
+	if len(c.statements) > 1 && strings.HasPrefix(stmt, "SELECT COUNT(") {
+		return []map[string]any{{"total": int64(len(c.rows))}}, nil
+	}
+	// Cópias: o serviço remove colunas ocultas das linhas devolvidas.
+	rows := make([]map[string]any, len(c.rows))
+	for i, row := range c.rows {
+		rows[i] = make(map[string]any, len(row))
+		for k, v := range row {
+			rows[i][k] = v
+		}
+	}
+	return rows, nil
+}
+
+func (c *fakeConnector) QueryColumns(ctx context.Context, stmt string, args ...any) ([]map[string]any, []datasource.ResultColumn, error) {
+	rows, err := c.Query(ctx, stmt, args...)
+	return rows, c.columns, err
+}
+
+func (c *fakeConnector) Execute(context.Context, string, ...any) (int64, error) { return 0, nil }
+
+func (c *fakeConnector) Transaction(_ context.Context, _ datasource.TxOptions, fn func(tx datasource.Executor) error) error {
+	return fn(c)
+}
+
+func (c *fakeConnector) Close() {}
+
+type fakeFactory struct {
+	conn      *fakeConnector
+	inspector schema.Inspector
+}
+
+func (f *fakeFactory) Connector(context.Context, *datasource.DataSource) (datasource.DatabaseConnector, func(), error) {
+	return f.conn, func() {}, nil
+}
+
+func (f *fakeFactory) Translator(dsType string) (query.Translator, bool) {
+	if dsType != "postgres" {
+		return nil, false
+	}
+	return postgres.NewTranslator(), true
+}
+
+func (f *fakeFactory) Inspector(string) (schema.Inspector, bool) {
+	return f.inspector, f.inspector != nil
+}
+
+var adminKey = &apikey.APIKey{Permissions: []apikey.Permission{{Resource: "main", Level: apikey.LevelDatabase}}}
+
+func newTestService(ds *datasource.DataSource, rows []map[string]any) (*QueryService, *fakeConnector) {
+	conn := &fakeConnector{rows: rows}
+	repo := &fakeRepo{sources: map[string]*datasource.DataSource{ds.Name: ds}}
+	return NewQueryService(repo, &fakeFactory{conn: conn}), conn
+}
+
+func TestQueryTable_BuildsStatementAndStripsBlocked(t *testing.T) {
+	ds := &datasource.DataSource{Name: "main", Type: "postgres", BlockedColumns: []string{"User.passwordHash"}}
+	svc, conn := newTestService(ds, []map[string]any{{"id": 1, "passwordHash": "x"}})
+
+	resp, err := svc.QueryTable(context.Background(), adminKey, "main", "User", QueryRequest{
+		Filter:     Filter{Columns: map[string]FilterField{"role": {Eq: "PILOT"}}},
+		OrderBy:    []OrderField{{Field: "id", Direction: "desc"}},
+		CountTotal: true,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, `SELECT * FROM "User" WHERE "role" = $1 ORDER BY "id" DESC LIMIT $2 OFFSET $3`, conn.statements[0].Text)
+	assert.Equal(t, []any{"PILOT", 100, 0}, conn.statements[0].Args)
+	assert.Equal(t, []map[string]any{{"id": 1}}, resp.Data)
+	require.NotNil(t, resp.Metadata.Total)
+	assert.Equal(t, int64(1), *resp.Metadata.Total)
+}
+
+func TestQueryTable_ColumnTypes(t *testing.T) {
+	ds := &datasource.DataSource{Name: "main", Type: "postgres", BlockedColumns: []string{"User.passwordHash"}}
+	svc, conn := newTestService(ds, []map[string]any{{"id": "u1", "passwordHash": "x"}})
+	conn.columns = []datasource.ResultColumn{
+		{Name: "id", Type: "uuid", JSON: "string", Format: "uuid"},
+		{Name: "passwordHash", Type: "text", JSON: "string", Nullable: true},
+	}
+
+	resp, err := svc.QueryTable(context.Background(), adminKey, "main", "User", QueryRequest{})
+	require.NoError(t, err)
+	assert.Nil(t, resp.Metadata.Columns)
+
+	resp, err = svc.QueryTable(context.Background(), adminKey, "main", "User", QueryRequest{ColumnTypes: true})
+	require.NoError(t, err)
+	assert.Equal(t, []datasource.ResultColumn{{Name: "id", Type: "uuid", JSON: "string", Format: "uuid"}}, resp.Metadata.Columns)
+}
+
+func TestQueryTable_UnsupportedType(t *testing.T) {
+	ds := &datasource.DataSource{Name: "main", Type: "dynamodb"}
+	svc, _ := newTestService(ds, nil)
+
+	_, err := svc.QueryTable(context.Background(), adminKey, "main", "User", QueryRequest{})
+	appErr, ok := err.(*domain.AppError)
+	require.True(t, ok)
+	assert.Equal(t, domain.ErrUnsupportedType, appErr.Code)
+}
+
+func TestQueryTable_CacheByPayloadAndVersion(t *testing.T) {
+	ds := &datasource.DataSource{Name: "main", Type: "postgres", Version: 1}
+	svc, conn := newTestService(ds, []map[string]any{{"id": 1}})
+	svc.WithCache(memcache.NewLRU(10, 1<<20, time.Minute, 0))
+	req := QueryRequest{Filter: Filter{Columns: map[string]FilterField{"role": {Eq: "PILOT"}}}}
+
+	first, err := svc.QueryTable(context.Background(), adminKey, "main", "User", req)
+	require.NoError(t, err)
+	assert.Equal(t, CacheMiss, first.Cache)
+
+	second, err := svc.QueryTable(context.Background(), adminKey, "main", "User", req)
+	require.NoError(t, err)
+	assert.Equal(t, CacheHit, second.Cache)
+	assert.Equal(t, first.Data, second.Data)
+	assert.Len(t, conn.statements, 1)
+
+	ds.Version = 2
+	third, err := svc.QueryTable(context.Background(), adminKey, "main", "User", req)
+	require.NoError(t, err)
+	assert.Equal(t, CacheMiss, third.Cache)
+	assert.Len(t, conn.statements, 2)
+}
+
+func TestCachedResponse_ReportsHitLatency(t *testing.T) {
+	svc, _ := newTestService(&datasource.DataSource{Name: "main", Type: "postgres"}, nil)
+	svc.WithCache(memcache.NewLRU(10, 1<<20, time.Minute, 0))
+	stored := &QueryResponse{Data: []map[string]any{}, Metadata: Meta{Rows: 0, Table: "User", TookMs: 5000}}
+	svc.cache.Set("main/User/v0/hash", "main/User", stored, 1)
+
+	hit, ok := svc.cachedResponse("main/User/v0/hash")
+	require.True(t, ok)
+	assert.Equal(t, CacheHit, hit.Cache)
+	assert.Less(t, hit.Metadata.TookMs, int64(5000))
+	// A entrada compartilhada mantém o tempo original
+	assert.Equal(t, int64(5000), stored.Metadata.TookMs)
+}
+
+func TestQueryTable_CacheChecksPermissionsFirst(t *testing.T) {
+	ds := &datasource.DataSource{Name: "main", Type: "postgres"}
+	svc, _ := newTestService(ds, []map[string]any{{"id": 1}})
+	svc.WithCache(memcache.NewLRU(10, 1<<20, time.Minute, 0))
+
+	_, err := svc.QueryTable(context.Background(), adminKey, "main", "User", QueryRequest{})
+	require.NoError(t, err)
+
+	other := &apikey.APIKey{Permissions: []apikey.Permission{{Resource: "other", Level: apikey.LevelDatabase}}}
+	_, err = svc.QueryTable(context.Background(), other, "main", "User", QueryRequest{})
+	appErr, ok := err.(*domain.AppError)
+	require.True(t, ok)
+	assert.Equal(t, domain.ErrForbidden, appErr.Code)
+}
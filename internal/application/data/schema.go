@@ -0,0 +1,272 @@
+package data
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"api-database/internal/domain"
+	"api-database/internal/domain/apikey"
+	"api-database/internal/domain/datasource"
+	"api-database/internal/domain/schema"
+)
+
+// TablesResponse lista as tabelas visíveis de um datasource.
+type TablesResponse struct {
+	Source string   `json:"source"`
+	Schema string   `json:"schema,omitempty"`
+	Tables []string `json:"tables"`
+}
+
+// ListTables lista as tabelas do schema que a chave pode consultar.
+func (s *QueryService) ListTables(ctx context.Context, ak *apikey.APIKey, sourceName, schemaName string) (*TablesResponse, error) {
+	if schemaName != "" && !tableNameRegex.MatchString(schemaName) {
+		return nil, domain.NewAppError(domain.ErrInvalidSchema, "invalid schema name", http.StatusBadRequest)
+	}
+	if err := s.authorizeSource(ak, sourceName); err != nil {
+		return nil, err
+	}
+	ds, err := s.repo.GetByName(ctx, sourceName)
+	if err != nil {
+		return nil, domain.NewAppError(domain.ErrDataSourceNotFound, "datasource not found", http.StatusNotFound)
+	}
+
+	tables, err := s.tables(ctx, ds, schemaName)
+	if err != nil {
+		return nil, err
+	}
+	visible := make([]string, 0, len(tables))
+	for _, t := range tables {
+		if s.skipAuthorization || ak.HasPermissionWithin(sourceName+"."+t) {
+			visible = append(visible, t)
+		}
+	}
+	return &TablesResponse{Source: sourceName, Schema: schemaName, Tables: visible}, nil
+}
+
+// DescribeTable retorna colunas, chaves e índices da tabela, sem colunas bloqueadas ou
+// fora do alcance da chave. Chaves e índices que envolvem colunas ocultas são omitidos.
+func (s *QueryService) DescribeTable(ctx context.Context, ak *apikey.APIKey, sourceName, schemaName, table string) (*schema.Table, error) {
+	if !tableNameRegex.MatchString(table) {
+		return nil, domain.NewAppError(domain.ErrInvalidTable, "invalid table name", http.StatusBadRequest)
+	}
+	if schemaName != "" && !tableNameRegex.MatchString(schemaName) {
+		return nil, domain.NewAppError(domain.ErrInvalidSchema, "invalid schema name", http.StatusBadRequest)
+	}
+	if err := s.authorizeSource(ak, sourceName); err != nil {
+		return nil, err
+	}
+	if !s.skipAuthorization && !ak.HasPermissionWithin(sourceName+"."+table) {
+		return nil, forbidden(fmt.Sprintf("access denied to table: %s", table), map[string]interface{}{
+			"resource": sourceName + "." + table,
+		})
+	}
+	ds, err := s.repo.GetByName(ctx, sourceName)
+	if err != nil {
+		return nil, domain.NewAppError(domain.ErrDataSourceNotFound, "datasource not found", http.StatusNotFound)
+	}
+
+	desc, err := s.describe(ctx, ds, schemaName, table)
+	if err != nil {
+		return nil, err
+	}
+	if desc == nil {
+		return nil, domain.NewAppError(domain.ErrNotFound, fmt.Sprintf("table not found: %s", table), http.StatusNotFound)
+	}
+	return s.visibleTable(ak, ds, desc), nil
+}
+
+func (s *QueryService) authorizeSource(ak *apikey.APIKey, sourceName string) error {
+	if s.skipAuthorization {
+		return nil
+	}
+	if ak == nil {
+		return forbidden("api key required", nil)
+	}
+	if !ak.HasPermissionWithin(sourceName) {
+		return forbidden(fmt.Sprintf("access denied to datasource: %s", sourceName), map[string]interface{}{
+			"resource": sourceName,
+		})
+	}
+	return nil
+}
+
+// visibleTable copia desc mantendo só o que a chave pode ver.
+func (s *QueryService) visibleTable(ak *apikey.APIKey, ds *datasource.DataSource, desc *schema.Table) *schema.Table {
+	visible := func(table, column string) bool {
+		if isColumnBlocked(table, column, ds.BlockedColumns) {
+			return false
+		}
+		return s.skipAuthorization || ak.HasPermission(ds.Name+"."+table+"."+column)
+	}
+	allVisible := func(table string, columns []string) bool {
+		for _, c := range columns {
+			if !visible(table, c) {
+				return false
+			}
+		}
+		return true
+	}
+
+	out := &schema.Table{
+		Name:         desc.Name,
+		Columns:      []schema.Column{},
+		PrimaryKey:   []string{},
+		ForeignKeys:  []schema.ForeignKey{},
+		ReferencedBy: []schema.ForeignKey{},
+		Indexes:      []schema.Index{},
+	}
+	for _, c := range desc.Columns {
+		if visible(desc.Name, c.Name) {
+			out.Columns = append(out.Columns, c)
+		}
+	}
+	// Chave primária parcial induziria a erro: com alguma coluna oculta, fica vazia.
+	if allVisible(desc.Name, desc.PrimaryKey) {
+		out.PrimaryKey = append(out.PrimaryKey, desc.PrimaryKey...)
+	}
+	for _, fk := range desc.ForeignKeys {
+		if allVisible(fk.Table, fk.Columns) && allVisible(fk.RefTable, fk.RefColumns) {
+			out.ForeignKeys = append(out.ForeignKeys, fk)
+		}
+	}
+	for _, fk := range desc.ReferencedBy {
+		if allVisible(fk.Table, fk.Columns) && allVisible(fk.RefTable, fk.RefColumns) {
+			out.ReferencedBy = append(out.ReferencedBy, fk)
+		}
+	}
+	for _, idx := range desc.Indexes {
+		if allVisible(desc.Name, idx.Columns) {
+			out.Indexes = append(out.Indexes, idx)
+		}
+	}
+	return out
+}
+
+// tables lê (ou recupera do cache) a lista de tabelas do schema.
+func (s *QueryService) tables(ctx context.Context, ds *datasource.DataSource, schemaName string) ([]string, error) {
+	if tables, ok := s.schemas.tables(ds, schemaName); ok {
+		return tables, nil
+	}
+	inspector, conn, release, err := s.catalog(ctx, ds)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	tables, err := inspector.Tables(ctx, conn, schemaName)
+	if err != nil {
+		return nil, err
+	}
+	s.schemas.setTables(ds, schemaName, tables)
+	return tables, nil
+}
+
+// describe lê (ou recupera do cache) a descrição completa da tabela; nil se não existir.
+func (s *QueryService) describe(ctx context.Context, ds *datasource.DataSource, schemaName, table string) (*schema.Table, error) {
+	if desc, ok := s.schemas.table(ds, schemaName, table); ok {
+		return desc, nil
+	}
+	inspector, conn, release, err := s.catalog(ctx, ds)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	desc, err := inspector.Describe(ctx, conn, schemaName, table)
+	if err != nil {
+		return nil, err
+	}
+	// Tabelas inexistentes não são guardadas: nomes arbitrários fariam o cache crescer sem limite.
+	if desc != nil {
+		s.schemas.setTable(ds, schemaName, table, desc)
+	}
+	return desc, nil
+}
+
+// catalog resolve o leitor de catálogo e o connector; o chamador invoca release ao terminar.
+func (s *QueryService) catalog(ctx context.Context, ds *datasource.DataSource) (schema.Inspector, datasource.Executor, func(), error) {
+	inspector, ok := s.connectors.Inspector(ds.Type)
+	if !ok {
+		return nil, nil, nil, domain.NewAppError(domain.ErrUnsupportedType, fmt.Sprintf("schema introspection is not supported for datasource type: %s", ds.Type), http.StatusBadRequest)
+	}
+	conn, release, err := s.connectors.Connector(ctx, ds)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return inspector, conn, release, nil
+}
+
+// schemaCache guarda metadados de catálogo por datasource. Uma nova DataSource.Version
+// descarta tudo o que foi lido na versão anterior; migrações sem mudança de versão são
+// percebidas por describeColumns quando uma coluna referenciada não está na descrição.
+type schemaCache struct {
+	mu      sync.Mutex
+	entries map[string]*schemaEntry
+}
+
+type schemaEntry struct {
+	version int
+	tables  map[string][]string    // schema → tabelas
+	defs    map[string]cachedTable // schema/tabela → descrição
+}
+
+type cachedTable struct {
+	desc     *schema.Table
+	loadedAt time.Time
+}
+
+func newSchemaCache() *schemaCache {
+	return &schemaCache{entries: make(map[string]*schemaEntry)}
+}
+
+// entry retorna a entrada da versão atual do datasource; chamar com mu travado.
+func (c *schemaCache) entry(ds *datasource.DataSource) *schemaEntry {
+	e, ok := c.entries[ds.Name]
+	if !ok || e.version != ds.Version {
+		e = &schemaEntry{version: ds.Version, tables: make(map[string][]string), defs: make(map[string]cachedTable)}
+		c.entries[ds.Name] = e
+	}
+	return e
+}
+
+func (c *schemaCache) tables(ds *datasource.DataSource, schemaName string) ([]string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	tables, ok := c.entry(ds).tables[schemaName]
+	return tables, ok
+}
+
+func (c *schemaCache) setTables(ds *datasource.DataSource, schemaName string, tables []string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entry(ds).tables[schemaName] = tables
+}
+
+func (c *schemaCache) table(ds *datasource.DataSource, schemaName, table string) (*schema.Table, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	cached, ok := c.entry(ds).defs[schemaName+"/"+table]
+	return cached.desc, ok
+}
+
+func (c *schemaCache) setTable(ds *datasource.DataSource, schemaName, table string, desc *schema.Table) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entry(ds).defs[schemaName+"/"+table] = cachedTable{desc: desc, loadedAt: time.Now()}
+}
+
+// expireTable descarta a descrição da tabela se ela foi lida há mais de minAge; o intervalo
+// evita que requisições com colunas inválidas consultem o catálogo a cada chamada.
+func (c *schemaCache) expireTable(ds *datasource.DataSource, schemaName, table string, minAge time.Duration) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e := c.entry(ds)
+	key := schemaName + "/" + table
+	cached, ok := e.defs[key]
+	if !ok || time.Since(cached.loadedAt) < minAge {
+		return false
+	}
+	delete(e.defs, key)
+	return true
+}
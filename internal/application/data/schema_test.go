@@ -0,0 +1,98 @@
+package data
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"api-database/internal/domain"
+	"api-database/internal/domain/apikey"
+	"api-database/internal/domain/schema"
+)
+
+var sqliteCatalog = []string{
+	`CREATE TABLE "Team" (id INTEGER PRIMARY KEY, name TEXT NOT NULL)`,
+	`CREATE TABLE "User" (id INTEGER PRIMARY KEY, email TEXT NOT NULL, teamId INTEGER REFERENCES "Team"(id), passwordHash TEXT)`,
+	`CREATE UNIQUE INDEX user_email ON "User"(email)`,
+	`CREATE INDEX user_team_hash ON "User"(teamId, passwordHash)`,
+}
+
+func TestDescribeTable_SQLite(t *testing.T) {
+	svc := newSQLiteService(t, sqliteCatalog...)
+
+	desc, err := svc.DescribeTable(context.Background(), adminKey, "main", "", "User")
+	require.NoError(t, err)
+	assert.Equal(t, []schema.Column{
+		{Name: "id", Type: "INTEGER", Nullable: false},
+		{Name: "email", Type: "TEXT", Nullable: false},
+		{Name: "teamId", Type: "INTEGER", Nullable: true},
+	}, desc.Columns)
+	assert.Equal(t, []string{"id"}, desc.PrimaryKey)
+	require.Len(t, desc.ForeignKeys, 1)
+	assert.Equal(t, "Team", desc.ForeignKeys[0].RefTable)
+	assert.Equal(t, []string{"teamId"}, desc.ForeignKeys[0].Columns)
+	// Índice com coluna bloqueada é omitido
+	assert.Equal(t, []schema.Index{{Name: "user_email", Columns: []string{"email"}, Unique: true}}, desc.Indexes)
+
+	team, err := svc.DescribeTable(context.Background(), adminKey, "main", "", "Team")
+	require.NoError(t, err)
+	require.Len(t, team.ReferencedBy, 1)
+	assert.Equal(t, "User", team.ReferencedBy[0].Table)
+
+	_, err = svc.DescribeTable(context.Background(), adminKey, "main", "", "Missing")
+	appErr, ok := err.(*domain.AppError)
+	require.True(t, ok)
+	assert.Equal(t, domain.ErrNotFound, appErr.Code)
+}
+
+func TestSchema_SQLiteRestrictedKey(t *testing.T) {
+	svc := newSQLiteService(t, sqliteCatalog...)
+	ak := &apikey.APIKey{Permissions: []apikey.Permission{
+		{Resource: "main.User.id", Level: "column"},
+		{Resource: "main.User.teamId", Level: "column"},
+	}}
+
+	list, err := svc.ListTables(context.Background(), ak, "main", "")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"User"}, list.Tables)
+
+	desc, err := svc.DescribeTable(context.Background(), ak, "main", "", "User")
+	require.NoError(t, err)
+	assert.Len(t, desc.Columns, 2)
+	assert.Equal(t, []string{"id"}, desc.PrimaryKey)
+	// A FK aponta para colunas de Team, fora do alcance da chave
+	assert.Empty(t, desc.ForeignKeys)
+	assert.Empty(t, desc.Indexes)
+
+	_, err = svc.DescribeTable(context.Background(), ak, "main", "", "Team")
+	assertForbidden(t, err)
+	_, err = svc.ListTables(context.Background(), nil, "main", "")
+	assertForbidden(t, err)
+}
+
+func TestSchema_CachedPerVersion(t *testing.T) {
+	svc := newSQLiteService(t, sqliteCatalog...)
+	ctx := context.Background()
+
+	list, err := svc.ListTables(ctx, adminKey, "main", "")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Team", "User"}, list.Tables)
+
+	ds := svc.repo.(*fakeRepo).sources["main"]
+	conn, release, err := svc.connectors.Connector(ctx, ds)
+	require.NoError(t, err)
+	_, err = conn.Execute(ctx, `CREATE TABLE "Race" (id INTEGER PRIMARY KEY)`)
+	require.NoError(t, err)
+	release()
+
+	list, err = svc.ListTables(ctx, adminKey, "main", "")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Team", "User"}, list.Tables)
+
+	ds.Version++
+	list, err = svc.ListTables(ctx, adminKey, "main", "")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Race", "Team", "User"}, list.Tables)
+}
@@ -0,0 +1,80 @@
+package data
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"api-database/internal/domain"
+	"api-database/internal/domain/datasource"
+)
+
+const (
+	// defaultQueryTimeout vale quando nem o datasource (Limits.QueryTimeoutMs) nem
+	// WithTimeouts definem o prazo.
+	defaultQueryTimeout = 4 * time.Second
+	// defaultAsyncQueryTimeout é o orçamento padrão das consultas executadas pela fila.
+	defaultAsyncQueryTimeout = 5 * time.Minute
+)
+
+// WithTimeouts define o prazo padrão das consultas síncronas (usado quando o datasource
+// não define Limits.QueryTimeoutMs) e o orçamento das consultas assíncronas.
+func (s *QueryService) WithTimeouts(query, async time.Duration) *QueryService {
+	if query > 0 {
+		s.queryTimeout = query
+	}
+	if async > 0 {
+		s.asyncTimeout = async
+	}
+	return s
+}
+
+type asyncBudgetKey struct{}
+
+// withAsyncBudget marca o contexto de uma consulta vinda da fila, que usa o orçamento assíncrono.
+func withAsyncBudget(ctx context.Context) context.Context {
+	return context.WithValue(ctx, asyncBudgetKey{}, true)
+}
+
+// timeout resolve o prazo da consulta: Limits.QueryTimeoutMs do datasource ou o padrão;
+// na fila, o orçamento assíncrono quando for maior.
+func (s *QueryService) timeout(ctx context.Context, ds *datasource.DataSource) time.Duration {
+	timeout := s.queryTimeout
+	if ds.Limits.QueryTimeoutMs > 0 {
+		timeout = time.Duration(ds.Limits.QueryTimeoutMs) * time.Millisecond
+	}
+	if async, _ := ctx.Value(asyncBudgetKey{}).(bool); async && s.asyncTimeout > timeout {
+		timeout = s.asyncTimeout
+	}
+	return timeout
+}
+
+// timeoutError converte o estouro do prazo (em qualquer driver) em QUERY_TIMEOUT.
+func timeoutError(ctx context.Context, err error, timeout time.Duration) error {
+	var appErr *domain.AppError
+	if errors.As(err, &appErr) {
+		if appErr.Code == domain.ErrQueryTimeout {
+			details := map[string]interface{}{"timeoutMs": timeout.Milliseconds()}
+			for k, v := range appErr.Details {
+				details[k] = v
+			}
+			appErr.Details = details
+		}
+		return appErr
+	}
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return domain.NewAppError(domain.ErrQueryTimeout, "query timed out", http.StatusGatewayTimeout).
+			WithDetails(map[string]interface{}{"timeoutMs": timeout.Milliseconds()})
+	}
+	return err
+}
+
+// MetricStatus classifica uma falha para telemetria: "timeout" para QUERY_TIMEOUT, senão "error".
+func MetricStatus(err error) string {
+	var appErr *domain.AppError
+	if errors.As(err, &appErr) && appErr.Code == domain.ErrQueryTimeout {
+		return "timeout"
+	}
+	return "error"
+}
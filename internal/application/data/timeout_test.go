@@ -0,0 +1,54 @@
+package data
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"api-database/internal/domain"
+	"api-database/internal/domain/datasource"
+)
+
+func TestQueryService_Timeout(t *testing.T) {
+	svc := NewQueryService(&fakeRepo{}, &fakeFactory{}).WithTimeouts(2*time.Second, time.Minute)
+	ctx := context.Background()
+
+	assert.Equal(t, 2*time.Second, svc.timeout(ctx, &datasource.DataSource{}))
+	limited := &datasource.DataSource{Limits: datasource.Limits{QueryTimeoutMs: 500}}
+	assert.Equal(t, 500*time.Millisecond, svc.timeout(ctx, limited))
+	assert.Equal(t, time.Minute, svc.timeout(withAsyncBudget(ctx), limited))
+
+	slow := &datasource.DataSource{Limits: datasource.Limits{QueryTimeoutMs: 120000}}
+	assert.Equal(t, 2*time.Minute, svc.timeout(withAsyncBudget(ctx), slow))
+}
+
+// blockingConnector espera o cancelamento do contexto, como um driver em consulta lenta.
+type blockingConnector struct{ fakeConnector }
+
+func (c *blockingConnector) Query(ctx context.Context, _ string, _ ...any) ([]map[string]any, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+type blockingFactory struct{ fakeFactory }
+
+func (f *blockingFactory) Connector(context.Context, *datasource.DataSource) (datasource.DatabaseConnector, func(), error) {
+	return &blockingConnector{}, func() {}, nil
+}
+
+func TestQueryTable_Timeout(t *testing.T) {
+	ds := &datasource.DataSource{Name: "main", Type: "postgres", Limits: datasource.Limits{QueryTimeoutMs: 20}}
+	svc := NewQueryService(&fakeRepo{sources: map[string]*datasource.DataSource{"main": ds}}, &blockingFactory{})
+
+	_, err := svc.QueryTable(context.Background(), adminKey, "main", "User", QueryRequest{})
+	appErr, ok := err.(*domain.AppError)
+	require.True(t, ok, "got %v", err)
+	assert.Equal(t, domain.ErrQueryTimeout, appErr.Code)
+	assert.Equal(t, 504, appErr.Status())
+	assert.Equal(t, int64(20), appErr.Details["timeoutMs"])
+	assert.Equal(t, "timeout", MetricStatus(err))
+	assert.Equal(t, "error", MetricStatus(assert.AnError))
+}
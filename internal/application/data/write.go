@@ -0,0 +1,341 @@
+package data
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	"api-database/internal/domain"
+	"api-database/internal/domain/apikey"
+	"api-database/internal/domain/datasource"
+	"api-database/internal/domain/query"
+)
+
+// defaultMaxAffectedRows limita as escritas quando o datasource não define maxAffectedRows.
+const defaultMaxAffectedRows = 1000
+
+// WriteOp identifica o tipo de escrita.
+type WriteOp string
+
+const (
+	WriteInsert WriteOp = "insert"
+	WriteUpdate WriteOp = "update"
+	WriteDelete WriteOp = "delete"
+)
+
+// WriteRequest descreve uma escrita. Insert usa Values (um objeto ou uma lista); Update usa
+// Values (um objeto) e Filter; Delete usa apenas Filter.
+type WriteRequest struct {
+	Schema string      `json:"schema"`
+	Values WriteValues `json:"values"`
+	Filter Filter      `json:"filter"`
+	// Fields limita as colunas devolvidas das linhas afetadas; vazio = todas.
+	Fields []string `json:"fields"`
+}
+
+// WriteValues aceita um objeto ou uma lista de objetos coluna → valor.
+type WriteValues []map[string]any
+
+func (v *WriteValues) UnmarshalJSON(b []byte) error {
+	if trimmed := bytes.TrimSpace(b); len(trimmed) > 0 && trimmed[0] == '{' {
+		var row map[string]any
+		if err := json.Unmarshal(trimmed, &row); err != nil {
+			return err
+		}
+		*v = WriteValues{row}
+		return nil
+	}
+	var rows []map[string]any
+	if err := json.Unmarshal(b, &rows); err != nil {
+		return invalidInput("values must be an object or a list of objects")
+	}
+	*v = rows
+	return nil
+}
+
+// WriteResponse devolve as linhas afetadas (RETURNING), sem colunas bloqueadas.
+type WriteResponse struct {
+	Data     []map[string]any `json:"data"`
+	Metadata WriteMeta        `json:"metadata"`
+}
+
+// WriteMeta resume a escrita.
+type WriteMeta struct {
+	Operation WriteOp `json:"operation"`
+	Table     string  `json:"table"`
+	Affected  int     `json:"affected"`
+	TookMs    int64   `json:"tookMs"`
+}
+
+// Write executa insert, update ou delete em uma transação. Exige permissão de escrita
+// (Permission.Write) nas colunas gravadas, ou na tabela para delete, recusa update/delete
+// sem filtro e desfaz a escrita que ultrapassar Limits.MaxAffectedRows.
+func (s *QueryService) Write(ctx context.Context, ak *apikey.APIKey, op WriteOp, sourceName, table string, req WriteRequest) (resp *WriteResponse, err error) {
+	if !tableNameRegex.MatchString(table) {
+		return nil, domain.NewAppError(domain.ErrInvalidTable, "invalid table name", http.StatusBadRequest)
+	}
+	if req.Schema != "" && !tableNameRegex.MatchString(req.Schema) {
+		return nil, domain.NewAppError(domain.ErrInvalidSchema, "invalid schema name", http.StatusBadRequest)
+	}
+	if err := s.authorizeWrite(ak, sourceName, table, op, req); err != nil {
+		return nil, err
+	}
+
+	ds, err := s.repo.GetByName(ctx, sourceName)
+	if err != nil {
+		return nil, domain.NewAppError(domain.ErrDataSourceNotFound, "datasource not found", http.StatusNotFound)
+	}
+	translator, ok := s.connectors.Translator(ds.Type)
+	if !ok {
+		return nil, domain.NewAppError(domain.ErrUnsupportedType, fmt.Sprintf("unsupported datasource type: %s", ds.Type), http.StatusBadRequest)
+	}
+
+	timeout := s.timeout(ctx, ds)
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	defer func() {
+		if err != nil {
+			err = timeoutError(ctx, err, timeout)
+		}
+	}()
+
+	plan, err := s.planWrite(ctx, ak, ds, translator, op, table, req)
+	if err != nil {
+		return nil, err
+	}
+	conn, release, err := s.connectors.Connector(ctx, ds)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	start := time.Now()
+	var rows []map[string]any
+	err = conn.Transaction(ctx, datasource.TxOptions{}, func(tx datasource.Executor) error {
+		var err error
+		rows, err = plan.run(ctx, tx)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.invalidate(ds)
+
+	return &WriteResponse{
+		Data:     plan.result(rows),
+		Metadata: WriteMeta{Operation: op, Table: table, Affected: len(rows), TookMs: time.Since(start).Milliseconds()},
+	}, nil
+}
+
+// authorizeWrite exige leitura das colunas do filtro e das devolvidas, e escrita nas
+// colunas gravadas (insert/update) ou na tabela (delete).
+func (s *QueryService) authorizeWrite(ak *apikey.APIKey, sourceName, table string, op WriteOp, req WriteRequest) error {
+	if s.skipAuthorization {
+		return nil
+	}
+	if err := authorizeQuery(ak, sourceName, table, QueryRequest{Fields: req.Fields, Filter: req.Filter}); err != nil {
+		return err
+	}
+	tableResource := sourceName + "." + table
+	if op == WriteDelete {
+		if !ak.HasWritePermission(tableResource) {
+			return forbidden(fmt.Sprintf("write access denied to table: %s", table), map[string]interface{}{
+				"resource": tableResource,
+			})
+		}
+		return nil
+	}
+	for _, col := range req.Values.columns() {
+		columnResource := tableResource + "." + col
+		if !ak.HasWritePermission(columnResource) {
+			return forbidden(fmt.Sprintf("write access denied to column: %s", col), map[string]interface{}{
+				"resource": columnResource,
+			})
+		}
+	}
+	return nil
+}
+
+// columns lista as colunas de todas as linhas, em ordem alfabética.
+func (v WriteValues) columns() []string {
+	seen := make(map[string]bool)
+	cols := make([]string, 0)
+	for _, row := range v {
+		for col := range row {
+			if !seen[col] {
+				seen[col] = true
+				cols = append(cols, col)
+			}
+		}
+	}
+	sort.Strings(cols)
+	return cols
+}
+
+// writePlan é uma escrita validada e traduzida, pronta para executar em um Executor.
+type writePlan struct {
+	op      WriteOp
+	table   string
+	ds      *datasource.DataSource
+	stmt    query.Statement
+	count   *query.Statement // update/delete: conta as linhas atingidas antes de escrever
+	maxRows int
+}
+
+func (s *QueryService) planWrite(ctx context.Context, ak *apikey.APIKey, ds *datasource.DataSource, translator query.Translator, op WriteOp, table string, req WriteRequest) (*writePlan, error) {
+	writer, ok := translator.(query.WriteTranslator)
+	if !ok {
+		return nil, domain.NewAppError(domain.ErrUnsupportedType, fmt.Sprintf("writes are not supported for datasource type: %s", ds.Type), http.StatusBadRequest)
+	}
+
+	cols := req.Values.columns()
+	switch op {
+	case WriteInsert:
+		if len(req.Values) == 0 || len(cols) == 0 {
+			return nil, invalidInput("insert requires values")
+		}
+		if len(req.Filter.columns()) > 0 {
+			return nil, invalidInput("insert does not accept a filter")
+		}
+		for i, row := range req.Values {
+			if len(row) != len(cols) {
+				return nil, invalidInput(fmt.Sprintf("values[%d] must set the same columns as the other rows", i))
+			}
+		}
+	case WriteUpdate:
+		if len(req.Values) != 1 || len(cols) == 0 {
+			return nil, invalidInput("update requires a single values object")
+		}
+	case WriteDelete:
+		if len(req.Values) > 0 {
+			return nil, invalidInput("delete does not accept values")
+		}
+	default:
+		return nil, invalidInput(fmt.Sprintf("unsupported write operation: %s", op))
+	}
+
+	for _, col := range cols {
+		if !columnRegex.MatchString(col) {
+			return nil, invalidInput(fmt.Sprintf("invalid column name: %s", col))
+		}
+		if isColumnBlocked(table, col, ds.BlockedColumns) {
+			return nil, domain.NewAppError(domain.ErrColumnBlocked, fmt.Sprintf("column blocked: %s", col), http.StatusForbidden)
+		}
+	}
+	read := QueryRequest{Schema: req.Schema, Filter: req.Filter, Fields: req.Fields}
+	if err := checkRequestColumns(table, ds, read); err != nil {
+		return nil, err
+	}
+	read.Fields = append(append([]string{}, req.Fields...), cols...)
+	if err := s.validateColumns(ctx, ak, ds, table, read, nil); err != nil {
+		return nil, err
+	}
+
+	where, err := buildFilter(req.Filter, ds.Capabilities.MaxDepthLimit)
+	if err != nil {
+		return nil, err
+	}
+	if op != WriteInsert && where == nil {
+		return nil, invalidInput(fmt.Sprintf("%s requires a filter", op))
+	}
+
+	plan := &writePlan{op: op, table: table, ds: ds, maxRows: maxAffectedRows(ds)}
+	switch op {
+	case WriteInsert:
+		if len(req.Values) > plan.maxRows {
+			return nil, rowLimitExceeded(len(req.Values), plan.maxRows)
+		}
+		rows := make([][]any, len(req.Values))
+		for i, row := range req.Values {
+			rows[i] = make([]any, len(cols))
+			for j, col := range cols {
+				rows[i][j] = row[col]
+			}
+		}
+		plan.stmt, err = writer.Insert(query.Insert{Schema: req.Schema, Table: table, Columns: cols, Rows: rows, Returning: req.Fields})
+	case WriteUpdate:
+		set := make([]query.Assignment, len(cols))
+		for i, col := range cols {
+			set[i] = query.Assignment{Column: col, Value: req.Values[0][col]}
+		}
+		plan.stmt, err = writer.Update(query.Update{Schema: req.Schema, Table: table, Set: set, Where: where, Returning: req.Fields})
+	case WriteDelete:
+		plan.stmt, err = writer.Delete(query.Delete{Schema: req.Schema, Table: table, Where: where, Returning: req.Fields})
+	}
+	if err != nil {
+		return nil, translateError(ds, err)
+	}
+	if op != WriteInsert {
+		count, err := translator.Count(query.Select{Schema: req.Schema, Table: table, Where: where})
+		if err != nil {
+			return nil, invalidInput(err.Error())
+		}
+		plan.count = &count
+	}
+	return plan, nil
+}
+
+// run executa a escrita; ultrapassar o limite retorna erro, desfazendo a transação.
+func (p *writePlan) run(ctx context.Context, ex datasource.Executor) ([]map[string]any, error) {
+	if p.count != nil {
+		rows, err := ex.Query(ctx, p.count.Text, p.count.Args...)
+		if err != nil {
+			return nil, err
+		}
+		if total := countFromRows(rows); total != nil && *total > int64(p.maxRows) {
+			return nil, rowLimitExceeded(int(*total), p.maxRows)
+		}
+	}
+	rows, err := ex.Query(ctx, p.stmt.Text, p.stmt.Args...)
+	if err != nil {
+		return nil, err
+	}
+	// Linhas inseridas por outras transações entre a contagem e a escrita.
+	if len(rows) > p.maxRows {
+		return nil, rowLimitExceeded(len(rows), p.maxRows)
+	}
+	return rows, nil
+}
+
+// result remove as colunas bloqueadas das linhas devolvidas.
+func (p *writePlan) result(rows []map[string]any) []map[string]any {
+	if rows == nil {
+		rows = []map[string]any{}
+	}
+	stripBlocked(rows, p.table, p.ds.BlockedColumns)
+	return rows
+}
+
+// invalidate descarta as respostas em cache de todo o datasource: consultas de outras
+// tabelas podem ter trazido a tabela escrita via include.
+func (s *QueryService) invalidate(ds *datasource.DataSource) {
+	if s.cache != nil {
+		s.cache.InvalidatePrefix(ds.Name + "/")
+	}
+}
+
+func maxAffectedRows(ds *datasource.DataSource) int {
+	if ds.Limits.MaxAffectedRows > 0 {
+		return ds.Limits.MaxAffectedRows
+	}
+	return defaultMaxAffectedRows
+}
+
+func rowLimitExceeded(affected, maxRows int) *domain.AppError {
+	return domain.NewAppError(domain.ErrRowLimitExceeded,
+		fmt.Sprintf("write would affect %d rows, above the limit of %d", affected, maxRows), http.StatusBadRequest).
+		WithDetails(map[string]interface{}{"affected": affected, "maxAffectedRows": maxRows})
+}
+
+// translateError separa dialetos sem suporte à escrita de erros de entrada.
+func translateError(ds *datasource.DataSource, err error) error {
+	if errors.Is(err, query.ErrUnsupported) {
+		return domain.NewAppError(domain.ErrUnsupportedType, fmt.Sprintf("writes are not supported for datasource type: %s", ds.Type), http.StatusBadRequest)
+	}
+	return invalidInput(err.Error())
+}
@@ -0,0 +1,192 @@
+package data
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"api-database/internal/domain"
+	"api-database/internal/domain/apikey"
+	"api-database/internal/domain/datasource"
+	"api-database/internal/domain/query"
+	"api-database/internal/infrastructure/memcache"
+	"api-database/internal/infrastructure/mysql"
+)
+
+var writeKey = &apikey.APIKey{Permissions: []apikey.Permission{{Resource: "main", Level: apikey.LevelDatabase, Write: true}}}
+
+func assertCode(t *testing.T, err error, code domain.ErrorCode) {
+	t.Helper()
+	appErr, ok := err.(*domain.AppError)
+	if assert.True(t, ok, "expected AppError, got %v", err) {
+		assert.Equal(t, code, appErr.Code)
+	}
+}
+
+func TestWrite_SQLite(t *testing.T) {
+	svc := newSQLiteService(t, sqliteUsers...)
+	ctx := context.Background()
+
+	inserted, err := svc.Write(ctx, writeKey, WriteInsert, "main", "User", WriteRequest{
+		Values: WriteValues{
+			{"id": 4, "email": "d@example.com", "role": "PILOT", "passwordHash": "h"},
+			{"id": 5, "email": "e@example.com", "role": "ADMIN", "passwordHash": "h"},
+		},
+	})
+	assertCode(t, err, domain.ErrColumnBlocked)
+	assert.Nil(t, inserted)
+
+	inserted, err = svc.Write(ctx, writeKey, WriteInsert, "main", "User", WriteRequest{
+		Values: WriteValues{{"id": 4, "email": "d@example.com", "role": "PILOT"}, {"id": 5, "email": "e@example.com", "role": "ADMIN"}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 2, inserted.Metadata.Affected)
+	require.Len(t, inserted.Data, 2)
+	assert.NotContains(t, inserted.Data[0], "passwordHash")
+	assert.Equal(t, "d@example.com", inserted.Data[0]["email"])
+
+	updated, err := svc.Write(ctx, writeKey, WriteUpdate, "main", "User", WriteRequest{
+		Values: WriteValues{{"role": "CREW"}},
+		Filter: Filter{Columns: map[string]FilterField{"id": {In: []any{4, 5}}}},
+		Fields: []string{"id", "role"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 2, updated.Metadata.Affected)
+	assert.Equal(t, map[string]any{"id": int64(4), "role": "CREW"}, updated.Data[0])
+
+	deleted, err := svc.Write(ctx, writeKey, WriteDelete, "main", "User", WriteRequest{
+		Filter: Filter{Columns: map[string]FilterField{"role": {Eq: "CREW"}}},
+		Fields: []string{"id"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 2, deleted.Metadata.Affected)
+
+	resp, err := svc.QueryTable(ctx, adminKey, "main", "User", QueryRequest{CountTotal: true})
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), *resp.Metadata.Total)
+}
+
+func TestWrite_RequiresFilter(t *testing.T) {
+	svc := newSQLiteService(t, sqliteUsers...)
+	ctx := context.Background()
+
+	_, err := svc.Write(ctx, writeKey, WriteUpdate, "main", "User", WriteRequest{Values: WriteValues{{"role": "CREW"}}})
+	assertCode(t, err, domain.ErrInvalidInput)
+	_, err = svc.Write(ctx, writeKey, WriteDelete, "main", "User", WriteRequest{Filter: Filter{And: []Filter{}}})
+	assertCode(t, err, domain.ErrInvalidInput)
+	_, err = svc.Write(ctx, writeKey, WriteInsert, "main", "User", WriteRequest{})
+	assertCode(t, err, domain.ErrInvalidInput)
+}
+
+func TestWrite_RowLimitRollsBack(t *testing.T) {
+	svc := newSQLiteService(t, sqliteUsers...)
+	svc.repo.(*fakeRepo).sources["main"].Limits.MaxAffectedRows = 1
+	ctx := context.Background()
+
+	_, err := svc.Write(ctx, writeKey, WriteUpdate, "main", "User", WriteRequest{
+		Values: WriteValues{{"role": "CREW"}},
+		Filter: Filter{Columns: map[string]FilterField{"role": {Eq: "PILOT"}}},
+	})
+	assertCode(t, err, domain.ErrRowLimitExceeded)
+
+	resp, err := svc.QueryTable(ctx, adminKey, "main", "User", QueryRequest{
+		Filter:     Filter{Columns: map[string]FilterField{"role": {Eq: "CREW"}}},
+		CountTotal: true,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), *resp.Metadata.Total)
+
+	_, err = svc.Write(ctx, writeKey, WriteInsert, "main", "User", WriteRequest{
+		Values: WriteValues{{"id": 4}, {"id": 5}},
+	})
+	assertCode(t, err, domain.ErrRowLimitExceeded)
+}
+
+func TestWrite_Permissions(t *testing.T) {
+	svc := newSQLiteService(t, sqliteUsers...)
+	ctx := context.Background()
+	byID := Filter{Columns: map[string]FilterField{"id": {Eq: 1}}}
+
+	// Leitura não concede escrita
+	_, err := svc.Write(ctx, adminKey, WriteDelete, "main", "User", WriteRequest{Filter: byID})
+	assertForbidden(t, err)
+
+	roleOnly := &apikey.APIKey{Permissions: []apikey.Permission{
+		{Resource: "main.User", Level: apikey.LevelTable},
+		{Resource: "main.User.role", Level: apikey.LevelColumn, Write: true},
+	}}
+	_, err = svc.Write(ctx, roleOnly, WriteUpdate, "main", "User", WriteRequest{Values: WriteValues{{"role": "CREW"}}, Filter: byID})
+	require.NoError(t, err)
+	_, err = svc.Write(ctx, roleOnly, WriteUpdate, "main", "User", WriteRequest{Values: WriteValues{{"email": "x@example.com"}}, Filter: byID})
+	assertForbidden(t, err)
+	// Remover linhas exige escrita na tabela
+	_, err = svc.Write(ctx, roleOnly, WriteDelete, "main", "User", WriteRequest{Filter: byID})
+	assertForbidden(t, err)
+}
+
+func TestWrite_InvalidatesCache(t *testing.T) {
+	svc := newSQLiteService(t, sqliteUsers...)
+	svc.WithCache(memcache.NewLRU(10, 1<<20, time.Minute, 0))
+	ctx := context.Background()
+
+	_, err := svc.QueryTable(ctx, adminKey, "main", "User", QueryRequest{})
+	require.NoError(t, err)
+	_, err = svc.Write(ctx, writeKey, WriteDelete, "main", "User", WriteRequest{
+		Filter: Filter{Columns: map[string]FilterField{"id": {Eq: 1}}},
+	})
+	require.NoError(t, err)
+
+	resp, err := svc.QueryTable(ctx, adminKey, "main", "User", QueryRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, CacheMiss, resp.Cache)
+	assert.Len(t, resp.Data, 2)
+}
+
+func TestWrite_InvalidatesIncludingTables(t *testing.T) {
+	svc := newSQLiteService(t, append(append([]string{}, sqliteUsers...), sqliteRelations...)...)
+	svc.WithCache(memcache.NewLRU(10, 1<<20, time.Minute, 0))
+	ctx := context.Background()
+	req := QueryRequest{Fields: []string{"name"}, OrderBy: []OrderField{{Field: "id"}}, Include: []IncludeField{{Table: "User", Fields: []string{"email"}}}}
+
+	_, err := svc.QueryTable(ctx, adminKey, "main", "Team", req)
+	require.NoError(t, err)
+	_, err = svc.Write(ctx, writeKey, WriteUpdate, "main", "User", WriteRequest{
+		Values: WriteValues{{"teamId": 20}},
+		Filter: Filter{Columns: map[string]FilterField{"id": {Eq: 1}}},
+	})
+	require.NoError(t, err)
+
+	// A consulta de Team trouxe User via include: a escrita em User também a invalida
+	resp, err := svc.QueryTable(ctx, adminKey, "main", "Team", req)
+	require.NoError(t, err)
+	assert.Equal(t, CacheMiss, resp.Cache)
+	assert.Equal(t, []map[string]any{{"email": "b@example.com"}}, resp.Data[0]["User"])
+}
+
+// mysqlFactory usa o tradutor MySQL, que não tem RETURNING.
+type mysqlFactory struct{ fakeFactory }
+
+func (f *mysqlFactory) Translator(string) (query.Translator, bool) {
+	return mysql.NewTranslator(), true
+}
+
+func TestWrite_MySQLUnsupported(t *testing.T) {
+	conn := &fakeConnector{}
+	ds := &datasource.DataSource{Name: "main", Type: "mysql"}
+	svc := NewQueryService(&fakeRepo{sources: map[string]*datasource.DataSource{"main": ds}}, &mysqlFactory{fakeFactory{conn: conn}})
+	ctx := context.Background()
+	byID := Filter{Columns: map[string]FilterField{"id": {Eq: 1}}}
+
+	for op, req := range map[WriteOp]WriteRequest{
+		WriteInsert: {Values: WriteValues{{"id": 4}}},
+		WriteUpdate: {Values: WriteValues{{"role": "CREW"}}, Filter: byID},
+		WriteDelete: {Filter: byID},
+	} {
+		_, err := svc.Write(ctx, writeKey, op, "main", "User", req)
+		assertCode(t, err, domain.ErrUnsupportedType)
+	}
+	assert.Empty(t, conn.statements)
+}
@@ -3,6 +3,7 @@ package config
 import (
 	"os"
 	"strconv"
+	"strings"
 
 	"github.com/joho/godotenv"
 )
@@ -16,6 +17,9 @@ type Config struct {
 	Cache      CacheConfig
 	Thresholds ThresholdsConfig
 	RabbitMQ   RabbitMQConfig
+	Auth       AuthConfig
+	Results    ResultsConfig
+	Pagination PaginationConfig
 }
 
 // MongoConfig define onde ficam os metadados de fontes de dados.
@@ -25,9 +29,17 @@ type MongoConfig struct {
 }
 
 // CacheConfig controla TTL e limites de cache.
+// TTLSeconds ou MaxItems <= 0 desabilita o cache de resultados.
 type CacheConfig struct {
 	TTLSeconds int
 	MaxItems   int
+	MaxBytes   int // orçamento total de memória das respostas em cache
+	TableQuota int // máximo de entradas por source/table (0 = sem limite)
+}
+
+// Enabled indica se o cache de resultados deve ser criado.
+func (c CacheConfig) Enabled() bool {
+	return c.TTLSeconds > 0 && c.MaxItems > 0
 }
 
 // RabbitMQConfig define parâmetros de fila.
@@ -36,10 +48,33 @@ type RabbitMQConfig struct {
 	QueryQueue string
 }
 
+// AuthConfig define o modo de autenticação (required|optional|disabled) e rotas públicas.
+type AuthConfig struct {
+	Mode        string
+	PublicPaths []string
+}
+
+// ResultsConfig define onde e por quanto tempo resultados de jobs assíncronos são guardados.
+type ResultsConfig struct {
+	Store      string // gridfs | disk | none
+	Dir        string
+	MaxBytes   int
+	TTLSeconds int
+}
+
+// PaginationConfig define a chave que assina cursores de paginação keyset.
+type PaginationConfig struct {
+	CursorSecret string
+}
+
 // ThresholdsConfig determina limites de tempo e custo para consultas.
 type ThresholdsConfig struct {
-	QueryTimeoutMs   int
-	AsyncSwitchP95Ms int
+	QueryTimeoutMs      int // prazo padrão de consultas síncronas (datasource pode sobrescrever)
+	AsyncQueryTimeoutMs int // orçamento das consultas executadas pela fila
+	RequestTimeoutMs    int // teto de uma requisição HTTP
+	ExportMaxRows       int // teto de linhas de POST /export
+	BatchWorkers        int // consultas simultâneas de POST /data/_batch
+	AsyncSwitchP95Ms    int
 }
 
 func Load() Config {
@@ -53,6 +88,9 @@ func Load() Config {
 		Cache:      loadCache(),
 		Thresholds: loadThresholds(),
 		RabbitMQ:   loadRabbitMQ(),
+		Auth:       loadAuth(),
+		Results:    loadResults(),
+		Pagination: PaginationConfig{CursorSecret: os.Getenv("CURSOR_SECRET")},
 	}
 }
 
@@ -67,13 +105,19 @@ func loadCache() CacheConfig {
 	return CacheConfig{
 		TTLSeconds: intFromEnv("CACHE_TTL_SECONDS", 300),
 		MaxItems:   intFromEnv("CACHE_MAX_ITEMS", 1000),
+		MaxBytes:   intFromEnv("CACHE_MAX_BYTES", 64*1024*1024),
+		TableQuota: intFromEnv("CACHE_TABLE_QUOTA", 100),
 	}
 }
 
 func loadThresholds() ThresholdsConfig {
 	return ThresholdsConfig{
-		QueryTimeoutMs:   intFromEnv("QUERY_TIMEOUT_MS", 4000),
-		AsyncSwitchP95Ms: intFromEnv("ASYNC_SWITCH_P95_MS", 3500),
+		QueryTimeoutMs:      intFromEnv("QUERY_TIMEOUT_MS", 4000),
+		AsyncQueryTimeoutMs: intFromEnv("ASYNC_QUERY_TIMEOUT_MS", 300000),
+		RequestTimeoutMs:    intFromEnv("REQUEST_TIMEOUT_MS", 30000),
+		ExportMaxRows:       intFromEnv("EXPORT_MAX_ROWS", 100000),
+		BatchWorkers:        intFromEnv("BATCH_WORKERS", 4),
+		AsyncSwitchP95Ms:    intFromEnv("ASYNC_SWITCH_P95_MS", 3500),
 	}
 }
 
@@ -84,6 +128,22 @@ func loadRabbitMQ() RabbitMQConfig {
 	}
 }
 
+func loadAuth() AuthConfig {
+	return AuthConfig{
+		Mode:        strings.ToLower(getEnv("AUTH_MODE", "required")),
+		PublicPaths: listFromEnv("AUTH_PUBLIC_PATHS", []string{"/health", "/", "/index.html", "/keys.html"}),
+	}
+}
+
+func loadResults() ResultsConfig {
+	return ResultsConfig{
+		Store:      strings.ToLower(getEnv("RESULT_STORE", "gridfs")),
+		Dir:        getEnv("RESULT_STORE_DIR", "data/results"),
+		MaxBytes:   intFromEnv("RESULT_MAX_BYTES", 10*1024*1024),
+		TTLSeconds: intFromEnv("RESULT_TTL_SECONDS", 86400),
+	}
+}
+
 func getEnv(key, fallback string) string {
 	if value := os.Getenv(key); value != "" {
 		return value
@@ -99,3 +159,17 @@ func intFromEnv(key string, fallback int) int {
 	}
 	return parsed
 }
+
+func listFromEnv(key string, fallback []string) []string {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -2,16 +2,28 @@ package apikey
 
 import (
 	"context"
+	"strings"
 
 	"github.com/google/uuid"
 )
 
+// Níveis de permissão aceitos.
+const (
+	LevelDatabase = "database"
+	LevelTable    = "table"
+	LevelColumn   = "column"
+	// LevelAdmin concede acesso às rotas de administração de chaves.
+	LevelAdmin = "admin"
+)
+
 // Permission define acesso a um recurso em um nível específico.
 type Permission struct {
 	// Resource examples: "racehub", "racehub.User", "racehub.User.passwordHash"
 	Resource string `bson:"resource" json:"resource"`
 	// Level: "database", "table", "column"
 	Level string `bson:"level" json:"level"`
+	// Write concede também escrita (insert/update/delete) no recurso e abaixo dele.
+	Write bool `bson:"write,omitempty" json:"write,omitempty"`
 }
 
 // APIKey representa uma chave de acesso.
@@ -32,14 +44,63 @@ func GenerateKey() string {
 // HasPermission verifica se a chave tem acesso a um recurso específico.
 // Verifica em order: resource específico → table → database
 func (ak *APIKey) HasPermission(resource string) bool {
+	for _, candidate := range resourceHierarchy(resource) {
+		for _, p := range ak.Permissions {
+			if strings.EqualFold(p.Resource, candidate) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// HasWritePermission é HasPermission considerando apenas permissões com Write.
+func (ak *APIKey) HasWritePermission(resource string) bool {
+	for _, candidate := range resourceHierarchy(resource) {
+		for _, p := range ak.Permissions {
+			if p.Write && strings.EqualFold(p.Resource, candidate) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// IsAdmin indica se a chave possui escopo de administração.
+func (ak *APIKey) IsAdmin() bool {
 	for _, p := range ak.Permissions {
-		if p.Resource == resource {
+		if strings.EqualFold(p.Level, LevelAdmin) {
 			return true
 		}
 	}
 	return false
 }
 
+// HasPermissionWithin verifica se a chave tem acesso ao recurso ou a algum recurso abaixo dele.
+// Ex.: "racehub.User.email" concede acesso parcial a "racehub.User" e "racehub".
+func (ak *APIKey) HasPermissionWithin(resource string) bool {
+	if ak.HasPermission(resource) {
+		return true
+	}
+	prefix := strings.ToLower(resource) + "."
+	for _, p := range ak.Permissions {
+		if strings.HasPrefix(strings.ToLower(p.Resource), prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// resourceHierarchy retorna o recurso e seus ancestrais, do mais específico ao mais amplo.
+func resourceHierarchy(resource string) []string {
+	parts := strings.Split(resource, ".")
+	result := make([]string, 0, len(parts))
+	for i := len(parts); i > 0; i-- {
+		result = append(result, strings.Join(parts[:i], "."))
+	}
+	return result
+}
+
 // APIKeyRepository interface para gerenciar chaves.
 type APIKeyRepository interface {
 	GetByKey(ctx context.Context, key string) (*APIKey, error)
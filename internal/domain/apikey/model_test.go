@@ -0,0 +1,51 @@
+package apikey
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHasPermissionHierarchy(t *testing.T) {
+	ak := &APIKey{Permissions: []Permission{
+		{Resource: "racehub", Level: "database"},
+		{Resource: "billing.Invoice", Level: "table"},
+		{Resource: "crm.Contact.email", Level: "column"},
+	}}
+
+	assert.True(t, ak.HasPermission("racehub"))
+	assert.True(t, ak.HasPermission("racehub.User"))
+	assert.True(t, ak.HasPermission("racehub.User.passwordHash"))
+	assert.True(t, ak.HasPermission("billing.Invoice.total"))
+	assert.False(t, ak.HasPermission("billing"))
+	assert.False(t, ak.HasPermission("billing.Customer"))
+	assert.True(t, ak.HasPermission("crm.Contact.email"))
+	assert.False(t, ak.HasPermission("crm.Contact"))
+	assert.False(t, ak.HasPermission("crm.Contact.phone"))
+}
+
+func TestHasPermissionWithin(t *testing.T) {
+	ak := &APIKey{Permissions: []Permission{
+		{Resource: "crm.Contact.email", Level: "column"},
+	}}
+
+	assert.True(t, ak.HasPermissionWithin("crm"))
+	assert.True(t, ak.HasPermissionWithin("crm.Contact"))
+	assert.False(t, ak.HasPermissionWithin("crm.Company"))
+	assert.False(t, ak.HasPermissionWithin("cr"))
+}
+
+func TestHasWritePermission(t *testing.T) {
+	ak := &APIKey{Permissions: []Permission{
+		{Resource: "racehub", Level: "database"},
+		{Resource: "racehub.User", Level: "table", Write: true},
+		{Resource: "crm.Contact.email", Level: "column", Write: true},
+	}}
+
+	assert.False(t, ak.HasWritePermission("racehub"))
+	assert.False(t, ak.HasWritePermission("racehub.Race"))
+	assert.True(t, ak.HasWritePermission("racehub.User"))
+	assert.True(t, ak.HasWritePermission("racehub.User.email"))
+	assert.True(t, ak.HasWritePermission("crm.Contact.email"))
+	assert.False(t, ak.HasWritePermission("crm.Contact"))
+}
@@ -0,0 +1,12 @@
+package cache
+
+// Cache é a porta de cache de resultados. Valores são tratados como imutáveis após Set.
+type Cache interface {
+	Get(key string) (any, bool)
+	// Set grava value no grupo informado (ex.: "source/table", usado para quotas); size em bytes.
+	Set(key, group string, value any, size int64)
+	// InvalidateGroup remove todos os itens do grupo (ex.: após escrever na tabela).
+	InvalidateGroup(group string)
+	// InvalidatePrefix remove os itens de todos os grupos que começam com prefix (ex.: "source/").
+	InvalidatePrefix(prefix string)
+}
@@ -0,0 +1,59 @@
+package datasource
+
+import (
+	"context"
+
+	"api-database/internal/domain/query"
+	"api-database/internal/domain/schema"
+)
+
+// Executor executa instruções já traduzidas para o dialeto do datasource.
+type Executor interface {
+	Query(ctx context.Context, stmt string, args ...any) ([]map[string]any, error)
+	Execute(ctx context.Context, stmt string, args ...any) (int64, error)
+}
+
+// Streamer é implementado por connectors que entregam as linhas uma a uma, sem materializar
+// o resultado inteiro (usado nas exportações). Um erro retornado por fn interrompe a leitura.
+type Streamer interface {
+	Stream(ctx context.Context, fn func(row map[string]any) error, stmt string, args ...any) error
+}
+
+// ResultColumn descreve uma coluna do resultado de uma consulta.
+type ResultColumn struct {
+	Name string `json:"name"`
+	Type string `json:"type"` // tipo no banco, ex.: "numeric(10,2)"
+	// JSON é a representação do valor na resposta (string, number, boolean, object, array
+	// ou json); Format refina strings e números (uuid, date-time, decimal...).
+	JSON     string `json:"json"`
+	Format   string `json:"format,omitempty"`
+	Nullable bool   `json:"nullable"` // true também quando o banco não informa (expressões)
+}
+
+// ColumnDescriber é implementado por connectors que informam os tipos das colunas do
+// resultado junto com as linhas.
+type ColumnDescriber interface {
+	QueryColumns(ctx context.Context, stmt string, args ...any) ([]map[string]any, []ResultColumn, error)
+}
+
+// TxOptions configura uma transação.
+type TxOptions struct {
+	Isolation string // "", "read committed", "repeatable read", "serializable"
+	ReadOnly  bool
+}
+
+// DatabaseConnector é a porta de acesso a um banco (Strategy).
+type DatabaseConnector interface {
+	Executor
+	Transaction(ctx context.Context, opts TxOptions, fn func(tx Executor) error) error
+	Close()
+}
+
+// ConnectorFactory resolve connectors, tradutores e leitores de catálogo a partir de DataSource.Type.
+type ConnectorFactory interface {
+	// Connector devolve o connector e um release que o chamador invoca ao terminar de usá-lo:
+	// um connector substituído por nova versão só é fechado depois do último release.
+	Connector(ctx context.Context, ds *DataSource) (conn DatabaseConnector, release func(), err error)
+	Translator(dsType string) (query.Translator, bool)
+	Inspector(dsType string) (schema.Inspector, bool)
+}
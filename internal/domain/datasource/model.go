@@ -4,18 +4,21 @@ import "context"
 
 // DataSource representa a configuração de uma fonte de dados.
 type DataSource struct {
-	Name           string         `bson:"name" json:"name"`
-	Type           string         `bson:"type" json:"type"`
-	Description    string         `bson:"description" json:"description"`
-	Connection     Connection     `bson:"connection" json:"connection"`
-	Capabilities   Capabilities   `bson:"capabilities" json:"capabilities"`
-	Limits         Limits         `bson:"limits" json:"limits"`
-	BlockedColumns []string       `bson:"blockedColumns" json:"blockedColumns"`
-	Version        int            `bson:"version" json:"version"`
-	CreatedAt      interface{}    `bson:"createdAt" json:"createdAt"`
-	UpdatedAt      interface{}    `bson:"updatedAt" json:"updatedAt"`
-	Raw            map[string]any `bson:"-" json:"-"`
-	Extra          map[string]any `bson:",inline" json:"-"`
+	Name           string            `bson:"name" json:"name"`
+	Type           string            `bson:"type" json:"type"`
+	Description    string            `bson:"description" json:"description"`
+	Connection     Connection        `bson:"connection" json:"connection"`
+	Capabilities   Capabilities      `bson:"capabilities" json:"capabilities"`
+	Limits         Limits            `bson:"limits" json:"limits"`
+	Pool           Pool              `bson:"pool" json:"pool"`
+	BlockedColumns []string          `bson:"blockedColumns" json:"blockedColumns"`
+	PrimaryKeys    map[string]string `bson:"primaryKeys" json:"primaryKeys"` // tabela → desempate do cursor (padrão id/_id)
+	UUIDColumns    []string          `bson:"uuidColumns" json:"uuidColumns"` // colunas binárias (MySQL/SQLite) que guardam UUID
+	Version        int               `bson:"version" json:"version"`
+	CreatedAt      interface{}       `bson:"createdAt" json:"createdAt"`
+	UpdatedAt      interface{}       `bson:"updatedAt" json:"updatedAt"`
+	Raw            map[string]any    `bson:"-" json:"-"`
+	Extra          map[string]any    `bson:",inline" json:"-"`
 }
 
 // Connection contém parâmetros de conexão.
@@ -38,7 +41,18 @@ type Capabilities struct {
 // Limits define restrições de execução.
 type Limits struct {
 	MaxRows        int `bson:"maxRows" json:"maxRows"`
+	MaxExportRows  int `bson:"maxExportRows" json:"maxExportRows"` // teto das exportações (menor que EXPORT_MAX_ROWS)
 	QueryTimeoutMs int `bson:"queryTimeoutMs" json:"queryTimeoutMs"`
+	// MaxAffectedRows limita as linhas alteradas por escrita (padrão 1000).
+	MaxAffectedRows int `bson:"maxAffectedRows" json:"maxAffectedRows"`
+}
+
+// Pool define o dimensionamento do pool de conexões; zero usa o padrão do driver.
+type Pool struct {
+	MaxConns          int `bson:"maxConns" json:"maxConns"`
+	MinConns          int `bson:"minConns" json:"minConns"`
+	MaxConnIdleTimeMs int `bson:"maxConnIdleTimeMs" json:"maxConnIdleTimeMs"`
+	MaxConnLifetimeMs int `bson:"maxConnLifetimeMs" json:"maxConnLifetimeMs"`
 }
 
 // DataSourceRepository interface para buscar configurações.
@@ -10,11 +10,23 @@ const (
 	ErrInvalidTable       ErrorCode = "INVALID_TABLE"
 	ErrInvalidSchema      ErrorCode = "INVALID_SCHEMA"
 	ErrColumnBlocked      ErrorCode = "COLUMN_BLOCKED"
+	ErrUnknownColumn      ErrorCode = "UNKNOWN_COLUMN"
 	ErrDataSourceNotFound ErrorCode = "DATASOURCE_NOT_FOUND"
 	ErrUnsupportedType    ErrorCode = "UNSUPPORTED_TYPE"
 	ErrQueryFailed        ErrorCode = "QUERY_FAILED"
+	ErrUndefinedTable     ErrorCode = "UNDEFINED_TABLE"
+	ErrUndefinedColumn    ErrorCode = "UNDEFINED_COLUMN"
+	ErrQueryTimeout       ErrorCode = "QUERY_TIMEOUT"
+	ErrUnavailable        ErrorCode = "DATASOURCE_UNAVAILABLE"
+	ErrPermissionDenied   ErrorCode = "PERMISSION_DENIED"
+	ErrRowLimitExceeded   ErrorCode = "ROW_LIMIT_EXCEEDED"
 	ErrInternal           ErrorCode = "INTERNAL_ERROR"
 	ErrNotFound           ErrorCode = "NOT_FOUND"
+	ErrForbidden          ErrorCode = "FORBIDDEN"
+	ErrMissingAPIKey      ErrorCode = "NO_API_KEY"
+	ErrInvalidAPIKey      ErrorCode = "INVALID_API_KEY"
+	ErrJobNotReady        ErrorCode = "JOB_NOT_READY"
+	ErrResultUnavailable  ErrorCode = "RESULT_NOT_AVAILABLE"
 )
 
 // AppError representa um erro estruturado da aplicação.
@@ -23,6 +35,8 @@ type AppError struct {
 	Message string                 `json:"message"`
 	Details map[string]interface{} `json:"details,omitempty"`
 	status  int
+	// cause é o erro original, apenas para logs: nunca é serializado para o cliente.
+	cause error
 }
 
 // NewAppError cria um novo erro estruturado.
@@ -41,6 +55,23 @@ func (e *AppError) WithDetails(details map[string]interface{}) *AppError {
 	return e
 }
 
+// WithCause guarda o erro original para os logs do servidor.
+func (e *AppError) WithCause(err error) *AppError {
+	e.cause = err
+	return e
+}
+
+// Unwrap expõe o erro original (errors.Is/As e logs).
+func (e *AppError) Unwrap() error {
+	return e.cause
+}
+
+// QueryFailed embrulha um erro não classificado do datasource com mensagem fixa: o texto do
+// driver pode conter host, usuário, valores ou trechos do SQL.
+func QueryFailed(err error) *AppError {
+	return NewAppError(ErrQueryFailed, "query failed", http.StatusInternalServerError).WithCause(err)
+}
+
 // Status retorna o código HTTP apropriado.
 func (e *AppError) Status() int {
 	return e.status
@@ -2,6 +2,7 @@ package job
 
 import (
 	"context"
+	"errors"
 	"time"
 )
 
@@ -17,7 +18,7 @@ const (
 type QueryJob struct {
 	ID          string     `bson:"_id" json:"id"`
 	PayloadHash string     `bson:"payloadHash" json:"payloadHash"`
-	APIKey      string     `bson:"apiKey" json:"apiKey"`
+	APIKey      string     `bson:"apiKey" json:"-"` // dono do job; nunca exposto nas respostas
 	DataSource  string     `bson:"dataSource" json:"dataSource"`
 	Table       string     `bson:"table" json:"table"`
 	Status      string     `bson:"status" json:"status"`
@@ -27,6 +28,33 @@ type QueryJob struct {
 	CreatedAt   time.Time  `bson:"createdAt" json:"createdAt"`
 	StartedAt   *time.Time `bson:"startedAt" json:"startedAt,omitempty"`
 	FinishedAt  *time.Time `bson:"finishedAt" json:"finishedAt,omitempty"`
+	// Resultado persistido no ResultStore (quando habilitado)
+	ResultStored    bool       `bson:"resultStored" json:"resultStored"`
+	ResultBytes     int64      `bson:"resultBytes" json:"resultBytes,omitempty"`
+	ResultExpiresAt *time.Time `bson:"resultExpiresAt" json:"resultExpiresAt,omitempty"`
+	ResultError     string     `bson:"resultError" json:"resultError,omitempty"`
+}
+
+// VisibleTo indica se a chave pode consultar o job: apenas a que o criou ou uma chave admin.
+// Jobs sem dono (criados sem autenticação) são visíveis a todos.
+func (j *QueryJob) VisibleTo(key string, admin bool) bool {
+	return j.APIKey == "" || admin || (key != "" && key == j.APIKey)
+}
+
+// ErrResultTooLarge indica que o resultado excede o limite de armazenamento.
+var ErrResultTooLarge = errors.New("job result exceeds storage size cap")
+
+// ErrResultNotFound indica resultado inexistente ou expirado.
+var ErrResultNotFound = errors.New("job result not found or expired")
+
+// ResultStore persiste linhas de jobs concluídos com expiração.
+type ResultStore interface {
+	// Save grava as linhas e retorna o tamanho serializado; ErrResultTooLarge acima do limite.
+	Save(ctx context.Context, jobID string, rows []map[string]any, expiresAt time.Time) (int64, error)
+	// Load retorna as linhas; ErrResultNotFound se ausente ou expirado.
+	Load(ctx context.Context, jobID string) ([]map[string]any, error)
+	// PurgeExpired remove resultados expirados e retorna quantos foram removidos.
+	PurgeExpired(ctx context.Context, now time.Time) (int, error)
 }
 
 // JobRepository define operações para persistir jobs.
@@ -0,0 +1,25 @@
+package job
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueryJob_HidesAPIKey(t *testing.T) {
+	b, err := json.Marshal(QueryJob{ID: "job-1", APIKey: "secret-key"})
+	require.NoError(t, err)
+	assert.NotContains(t, string(b), "secret-key")
+	assert.NotContains(t, string(b), "apiKey")
+}
+
+func TestQueryJob_VisibleTo(t *testing.T) {
+	owned := &QueryJob{APIKey: "owner"}
+	assert.True(t, owned.VisibleTo("owner", false))
+	assert.True(t, owned.VisibleTo("other", true))
+	assert.False(t, owned.VisibleTo("other", false))
+	assert.False(t, owned.VisibleTo("", false))
+	assert.True(t, (&QueryJob{}).VisibleTo("", false))
+}
@@ -0,0 +1,147 @@
+package query
+
+import "errors"
+
+// Operator identifica uma comparação ou composição de filtros.
+type Operator string
+
+const (
+	OpAnd     Operator = "and"
+	OpOr      Operator = "or"
+	OpNot     Operator = "not" // nega o AND de Children
+	OpEq      Operator = "eq"
+	OpNe      Operator = "ne"
+	OpGt      Operator = "gt"
+	OpGte     Operator = "gte"
+	OpLt      Operator = "lt"
+	OpLte     Operator = "lte"
+	OpIn      Operator = "in"      // Value: []any não vazio
+	OpNin     Operator = "nin"     // Value: []any não vazio
+	OpLike    Operator = "like"    // Value: padrão SQL (% e _)
+	OpILike   Operator = "ilike"   // Value: padrão SQL, sem diferenciar maiúsculas
+	OpIsNull  Operator = "isnull"  // Value: bool (false = IS NOT NULL)
+	OpBetween Operator = "between" // Value: []any{min, max}
+)
+
+// Filter é um nó da árvore de filtros: comparação (Column/Value) ou grupo (Children).
+type Filter struct {
+	Op       Operator
+	Column   string
+	Value    any
+	Children []Filter
+}
+
+// AggregateFunc identifica uma função de agregação.
+type AggregateFunc string
+
+const (
+	AggCount AggregateFunc = "count"
+	AggSum   AggregateFunc = "sum"
+	AggAvg   AggregateFunc = "avg"
+	AggMin   AggregateFunc = "min"
+	AggMax   AggregateFunc = "max"
+)
+
+// Aggregate é uma coluna calculada Func(Column) AS Alias; Column vazio só vale para count.
+type Aggregate struct {
+	Func   AggregateFunc
+	Column string
+	Alias  string
+}
+
+// Order define coluna e direção de ordenação. Em colunas Nullable, NULL é ordenado como o
+// maior valor em qualquer banco (último em ASC, primeiro em DESC), e a paginação keyset
+// passa a tratá-lo explicitamente.
+type Order struct {
+	Column   string
+	Desc     bool
+	Nullable bool
+}
+
+// Select descreve uma consulta independente de dialeto; identificadores já validados.
+type Select struct {
+	Schema  string
+	Table   string
+	Columns []string // vazio = todas as colunas
+	Where   *Filter
+	OrderBy []Order
+	Limit   int
+	Offset  int
+	// GroupBy e Aggregates transformam a consulta em agregação: o resultado traz as colunas
+	// de GroupBy seguidas dos aliases. Having e OrderBy referenciam essas colunas/aliases.
+	GroupBy    []string
+	Aggregates []Aggregate
+	Having     *Filter
+	// After posiciona a consulta logo após a linha com estes valores de OrderBy
+	// (paginação keyset); vazio = desde o início.
+	After []any
+}
+
+// Statement é uma instrução traduzida para o dialeto do datasource.
+type Statement struct {
+	Text string
+	Args []any
+}
+
+// Translator converte consultas neutras no dialeto de um tipo de datasource.
+type Translator interface {
+	Select(spec Select) (Statement, error)
+	Count(spec Select) (Statement, error)
+}
+
+// Insert grava Rows; cada linha traz os valores na ordem de Columns.
+type Insert struct {
+	Schema  string
+	Table   string
+	Columns []string
+	Rows    [][]any
+	// Returning lista as colunas devolvidas das linhas gravadas; vazio = todas.
+	Returning []string
+}
+
+// Assignment é uma atribuição coluna = valor de um Update.
+type Assignment struct {
+	Column string
+	Value  any
+}
+
+// Update altera as linhas que satisfazem Where (obrigatório).
+type Update struct {
+	Schema    string
+	Table     string
+	Set       []Assignment
+	Where     *Filter
+	Returning []string
+}
+
+// Delete remove as linhas que satisfazem Where (obrigatório).
+type Delete struct {
+	Schema    string
+	Table     string
+	Where     *Filter
+	Returning []string
+}
+
+// WriteTranslator é implementado pelos tradutores que geram escritas devolvendo as linhas
+// afetadas (RETURNING).
+type WriteTranslator interface {
+	Insert(spec Insert) (Statement, error)
+	Update(spec Update) (Statement, error)
+	Delete(spec Delete) (Statement, error)
+}
+
+// ErrUnsupported indica uma instrução que o dialeto não suporta (ex.: RETURNING no MySQL).
+var ErrUnsupported = errors.New("not supported by this dialect")
+
+// Aggregated indica se a consulta agrupa linhas.
+func (s Select) Aggregated() bool {
+	return len(s.GroupBy) > 0 || len(s.Aggregates) > 0
+}
+
+// And agrupa filtros com AND, descartando grupos vazios.
+func And(filters ...Filter) *Filter {
+	if len(filters) == 0 {
+		return nil
+	}
+	return &Filter{Op: OpAnd, Children: filters}
+}
@@ -0,0 +1,62 @@
+package schema
+
+import "context"
+
+// Table descreve uma tabela lida do catálogo.
+type Table struct {
+	Name        string       `json:"name"`
+	Columns     []Column     `json:"columns"`
+	PrimaryKey  []string     `json:"primaryKey"`
+	ForeignKeys []ForeignKey `json:"foreignKeys"`
+	// ReferencedBy lista as FKs de outras tabelas que apontam para esta.
+	ReferencedBy []ForeignKey `json:"referencedBy"`
+	Indexes      []Index      `json:"indexes"`
+}
+
+// Column descreve uma coluna com o tipo nativo do banco.
+type Column struct {
+	Name     string `json:"name"`
+	Type     string `json:"type"`
+	Nullable bool   `json:"nullable"`
+}
+
+// Index descreve um índice (colunas na ordem da definição).
+type Index struct {
+	Name    string   `json:"name"`
+	Columns []string `json:"columns"`
+	Unique  bool     `json:"unique"`
+}
+
+// Column retorna a coluna pelo nome, se existir.
+func (t *Table) Column(name string) (Column, bool) {
+	for _, c := range t.Columns {
+		if c.Name == name {
+			return c, true
+		}
+	}
+	return Column{}, false
+}
+
+// ForeignKey liga Columns de Table a RefColumns de RefTable (mesma ordem).
+type ForeignKey struct {
+	Name       string   `json:"name"`
+	Table      string   `json:"table"`
+	Columns    []string `json:"columns"`
+	RefTable   string   `json:"refTable"`
+	RefColumns []string `json:"refColumns"`
+}
+
+// Querier executa consultas de catálogo (satisfeito por datasource.Executor).
+type Querier interface {
+	Query(ctx context.Context, stmt string, args ...any) ([]map[string]any, error)
+}
+
+// Inspector lê metadados do catálogo de um tipo de banco.
+type Inspector interface {
+	// Tables lista as tabelas e views do schema, em ordem alfabética.
+	Tables(ctx context.Context, q Querier, schema string) ([]string, error)
+	// Describe lê colunas, chaves e índices da tabela; retorna nil quando ela não existe.
+	Describe(ctx context.Context, q Querier, schema, table string) (*Table, error)
+	// ForeignKeys lista as chaves estrangeiras em que table é origem ou destino.
+	ForeignKeys(ctx context.Context, q Querier, schema, table string) ([]ForeignKey, error)
+}
@@ -0,0 +1,188 @@
+package connector
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"api-database/internal/domain/datasource"
+	"api-database/internal/domain/query"
+	"api-database/internal/domain/schema"
+)
+
+// OpenFunc cria um connector para um datasource.
+type OpenFunc func(ctx context.Context, ds *datasource.DataSource) (datasource.DatabaseConnector, error)
+
+// Driver agrupa a estratégia de conexão, o tradutor e o leitor de catálogo (opcional)
+// de um tipo de datasource.
+type Driver struct {
+	Open       OpenFunc
+	Translator query.Translator
+	Inspector  schema.Inspector
+}
+
+// Factory implementa datasource.ConnectorFactory: resolve drivers por DataSource.Type e
+// mantém um connector por datasource, reaproveitado entre requisições. Quando
+// DataSource.Version muda, o connector antigo é aposentado e fechado só depois que a
+// última requisição que o usava o devolve (release).
+type Factory struct {
+	mu      sync.Mutex
+	drivers map[string]Driver
+	entries map[string]*entry
+	// opening guarda as aberturas em andamento por nome/versão: requisições simultâneas
+	// aguardam o mesmo Open em vez de criar pools paralelos.
+	opening map[string]*opening
+}
+
+type entry struct {
+	version int
+	conn    datasource.DatabaseConnector
+	// refs conta as requisições usando o connector; retired marca que foi substituído.
+	refs    int
+	retired bool
+}
+
+type opening struct {
+	done chan struct{}
+	err  error
+}
+
+func NewFactory() *Factory {
+	return &Factory{
+		drivers: make(map[string]Driver),
+		entries: make(map[string]*entry),
+		opening: make(map[string]*opening),
+	}
+}
+
+// Register associa um driver a um tipo de datasource (ex.: "postgres").
+func (f *Factory) Register(dsType string, driver Driver) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.drivers[dsType] = driver
+}
+
+// Translator retorna o tradutor do tipo informado.
+func (f *Factory) Translator(dsType string) (query.Translator, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	driver, ok := f.drivers[dsType]
+	if !ok {
+		return nil, false
+	}
+	return driver.Translator, true
+}
+
+// Inspector retorna o leitor de catálogo do tipo informado, se houver.
+func (f *Factory) Inspector(dsType string) (schema.Inspector, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	driver, ok := f.drivers[dsType]
+	if !ok || driver.Inspector == nil {
+		return nil, false
+	}
+	return driver.Inspector, true
+}
+
+// Connector retorna o connector do datasource, criando ou recriando conforme a versão.
+// O chamador deve chamar release ao terminar de usá-lo.
+func (f *Factory) Connector(ctx context.Context, ds *datasource.DataSource) (datasource.DatabaseConnector, func(), error) {
+	for {
+		f.mu.Lock()
+		driver, ok := f.drivers[ds.Type]
+		if !ok {
+			f.mu.Unlock()
+			return nil, nil, fmt.Errorf("unsupported datasource type: %s", ds.Type)
+		}
+		if current, cached := f.entries[ds.Name]; cached && current.version == ds.Version {
+			conn, release := f.acquire(current)
+			f.mu.Unlock()
+			return conn, release, nil
+		}
+		key := fmt.Sprintf("%s@%d", ds.Name, ds.Version)
+		if call, inFlight := f.opening[key]; inFlight {
+			f.mu.Unlock()
+			select {
+			case <-call.done:
+				if call.err != nil {
+					return nil, nil, call.err
+				}
+				// Aberto: pegar a referência pelo caminho normal.
+				continue
+			case <-ctx.Done():
+				return nil, nil, ctx.Err()
+			}
+		}
+		call := &opening{done: make(chan struct{})}
+		f.opening[key] = call
+		f.mu.Unlock()
+
+		// Criar fora do lock: o ping pode demorar e não deve bloquear outros datasources.
+		conn, release, err := f.open(ctx, driver, ds)
+		call.err = err
+
+		f.mu.Lock()
+		delete(f.opening, key)
+		f.mu.Unlock()
+		close(call.done)
+		return conn, release, err
+	}
+}
+
+// open cria o connector e o registra, aposentando o de versão anterior.
+func (f *Factory) open(ctx context.Context, driver Driver, ds *datasource.DataSource) (datasource.DatabaseConnector, func(), error) {
+	conn, err := driver.Open(ctx, ds)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	current, cached := f.entries[ds.Name]
+	if cached && current.version >= ds.Version {
+		// Uma versão mais nova foi registrada enquanto este connector era criado.
+		conn.Close()
+		c, release := f.acquire(current)
+		return c, release, nil
+	}
+	if cached {
+		current.retired = true
+		f.closeIfIdle(current)
+	}
+	e := &entry{version: ds.Version, conn: conn}
+	f.entries[ds.Name] = e
+	c, release := f.acquire(e)
+	return c, release, nil
+}
+
+// acquire conta uma referência ao connector; o release devolvido é idempotente. Exige f.mu.
+func (f *Factory) acquire(e *entry) (datasource.DatabaseConnector, func()) {
+	e.refs++
+	var once sync.Once
+	return e.conn, func() {
+		once.Do(func() {
+			f.mu.Lock()
+			defer f.mu.Unlock()
+			e.refs--
+			f.closeIfIdle(e)
+		})
+	}
+}
+
+// closeIfIdle fecha um connector aposentado sem referências. Exige f.mu.
+func (f *Factory) closeIfIdle(e *entry) {
+	if e.retired && e.refs == 0 {
+		// Close pode aguardar conexões sendo devolvidas ao pool; não bloquear quem chamou.
+		go e.conn.Close()
+	}
+}
+
+// Close fecha todos os connectors abertos.
+func (f *Factory) Close() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for name, e := range f.entries {
+		e.conn.Close()
+		delete(f.entries, name)
+	}
+}
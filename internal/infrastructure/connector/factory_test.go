@@ -0,0 +1,68 @@
+package connector
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"api-database/internal/domain/datasource"
+)
+
+type stubConnector struct {
+	datasource.DatabaseConnector
+	closed atomic.Bool
+}
+
+func (c *stubConnector) Close() { c.closed.Store(true) }
+
+func TestFactory_ConnectorOpensOncePerVersion(t *testing.T) {
+	var opens atomic.Int32
+	factory := NewFactory()
+	factory.Register("stub", Driver{Open: func(context.Context, *datasource.DataSource) (datasource.DatabaseConnector, error) {
+		opens.Add(1)
+		time.Sleep(20 * time.Millisecond)
+		return &stubConnector{}, nil
+	}})
+	ds := &datasource.DataSource{Name: "main", Type: "stub", Version: 1}
+
+	conns := make([]datasource.DatabaseConnector, 10)
+	releases := make([]func(), len(conns))
+	var wg sync.WaitGroup
+	for i := range conns {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			conn, release, err := factory.Connector(context.Background(), ds)
+			assert.NoError(t, err)
+			conns[i], releases[i] = conn, release
+		}(i)
+	}
+	wg.Wait()
+	assert.Equal(t, int32(1), opens.Load())
+	for _, conn := range conns {
+		assert.Same(t, conns[0], conn)
+	}
+
+	// Nova versão: abre outro connector; o anterior segue aberto enquanto estiver em uso
+	next, releaseNext, err := factory.Connector(context.Background(), &datasource.DataSource{Name: "main", Type: "stub", Version: 2})
+	require.NoError(t, err)
+	defer releaseNext()
+	assert.NotSame(t, conns[0], next)
+	assert.Equal(t, int32(2), opens.Load())
+	old := conns[0].(*stubConnector)
+	for _, release := range releases[1:] {
+		release()
+	}
+	releases[1]() // release é idempotente
+	time.Sleep(20 * time.Millisecond)
+	assert.False(t, old.closed.Load(), "in-flight request still holds the old connector")
+
+	releases[0]()
+	assert.Eventually(t, old.closed.Load, time.Second, 5*time.Millisecond)
+	assert.False(t, next.(*stubConnector).closed.Load())
+}
@@ -0,0 +1,120 @@
+package localdisk
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"api-database/internal/domain/job"
+)
+
+var jobIDRegex = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// ResultStore implementa job.ResultStore gravando um arquivo JSON por job em disco local.
+type ResultStore struct {
+	dir      string
+	maxBytes int64
+}
+
+// envelope é o conteúdo gravado em disco.
+type envelope struct {
+	ExpiresAt time.Time        `json:"expiresAt"`
+	Rows      []map[string]any `json:"rows"`
+}
+
+func NewResultStore(dir string, maxBytes int64) (*ResultStore, error) {
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, err
+	}
+	return &ResultStore{dir: dir, maxBytes: maxBytes}, nil
+}
+
+func (s *ResultStore) path(jobID string) (string, error) {
+	if !jobIDRegex.MatchString(jobID) {
+		return "", fmt.Errorf("invalid job id: %s", jobID)
+	}
+	return filepath.Join(s.dir, jobID+".json"), nil
+}
+
+func (s *ResultStore) Save(_ context.Context, jobID string, rows []map[string]any, expiresAt time.Time) (int64, error) {
+	path, err := s.path(jobID)
+	if err != nil {
+		return 0, err
+	}
+	payload, err := json.Marshal(envelope{ExpiresAt: expiresAt, Rows: rows})
+	if err != nil {
+		return 0, err
+	}
+	if s.maxBytes > 0 && int64(len(payload)) > s.maxBytes {
+		return 0, job.ErrResultTooLarge
+	}
+
+	// Escrita atômica: arquivo temporário + rename.
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, payload, 0o640); err != nil {
+		return 0, err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		_ = os.Remove(tmp)
+		return 0, err
+	}
+	return int64(len(payload)), nil
+}
+
+func (s *ResultStore) Load(_ context.Context, jobID string) ([]map[string]any, error) {
+	path, err := s.path(jobID)
+	if err != nil {
+		return nil, job.ErrResultNotFound
+	}
+	env, err := readEnvelope(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, job.ErrResultNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if time.Now().After(env.ExpiresAt) {
+		_ = os.Remove(path)
+		return nil, job.ErrResultNotFound
+	}
+	return env.Rows, nil
+}
+
+func (s *ResultStore) PurgeExpired(_ context.Context, now time.Time) (int, error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return 0, err
+	}
+	removed := 0
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".json") {
+			continue
+		}
+		path := filepath.Join(s.dir, e.Name())
+		env, err := readEnvelope(path)
+		if err != nil || now.After(env.ExpiresAt) {
+			if err := os.Remove(path); err == nil {
+				removed++
+			}
+		}
+	}
+	return removed, nil
+}
+
+func readEnvelope(path string) (*envelope, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var env envelope
+	if err := json.Unmarshal(b, &env); err != nil {
+		return nil, err
+	}
+	return &env, nil
+}
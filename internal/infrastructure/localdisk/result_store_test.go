@@ -0,0 +1,60 @@
+package localdisk
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"api-database/internal/domain/job"
+)
+
+func TestResultStore_SaveLoad(t *testing.T) {
+	store, err := NewResultStore(t.TempDir(), 0)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	rows := []map[string]any{{"id": float64(1), "name": "a"}}
+	size, err := store.Save(ctx, "job-1", rows, time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	assert.Greater(t, size, int64(0))
+
+	loaded, err := store.Load(ctx, "job-1")
+	require.NoError(t, err)
+	assert.Equal(t, rows, loaded)
+
+	_, err = store.Load(ctx, "missing")
+	assert.ErrorIs(t, err, job.ErrResultNotFound)
+	_, err = store.Load(ctx, "../etc/passwd")
+	assert.ErrorIs(t, err, job.ErrResultNotFound)
+}
+
+func TestResultStore_SizeCap(t *testing.T) {
+	store, err := NewResultStore(t.TempDir(), 32)
+	require.NoError(t, err)
+
+	_, err = store.Save(context.Background(), "job-1", []map[string]any{{"payload": "0123456789012345678901234567890123456789"}}, time.Now().Add(time.Hour))
+	assert.ErrorIs(t, err, job.ErrResultTooLarge)
+}
+
+func TestResultStore_Expiry(t *testing.T) {
+	store, err := NewResultStore(t.TempDir(), 0)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	_, err = store.Save(ctx, "expired", []map[string]any{{"id": 1}}, time.Now().Add(-time.Minute))
+	require.NoError(t, err)
+	_, err = store.Save(ctx, "fresh", []map[string]any{{"id": 2}}, time.Now().Add(time.Hour))
+	require.NoError(t, err)
+
+	removed, err := store.PurgeExpired(ctx, time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, 1, removed)
+
+	_, err = store.Load(ctx, "expired")
+	assert.ErrorIs(t, err, job.ErrResultNotFound)
+	_, err = store.Load(ctx, "fresh")
+	assert.NoError(t, err)
+}
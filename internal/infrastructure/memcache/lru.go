@@ -0,0 +1,153 @@
+package memcache
+
+import (
+	"container/list"
+	"strings"
+	"sync"
+	"time"
+)
+
+// LRU implementa cache.Cache em memória com expiração por TTL, limite de itens,
+// orçamento de bytes e quota de itens por grupo (tabela).
+type LRU struct {
+	mu         sync.Mutex
+	ll         *list.List
+	items      map[string]*list.Element
+	groups     map[string]int
+	bytes      int64
+	maxItems   int
+	maxBytes   int64
+	groupQuota int
+	ttl        time.Duration
+	now        func() time.Time
+}
+
+type entry struct {
+	key       string
+	group     string
+	value     any
+	size      int64
+	expiresAt time.Time
+}
+
+// maxEntryFraction limita cada item a uma fração do orçamento: o cache serve payloads
+// pequenos e frequentes, não arquivamento.
+const maxEntryFraction = 10
+
+func NewLRU(maxItems int, maxBytes int64, ttl time.Duration, groupQuota int) *LRU {
+	return &LRU{
+		ll:         list.New(),
+		items:      make(map[string]*list.Element),
+		groups:     make(map[string]int),
+		maxItems:   maxItems,
+		maxBytes:   maxBytes,
+		groupQuota: groupQuota,
+		ttl:        ttl,
+		now:        time.Now,
+	}
+}
+
+func (c *LRU) Get(key string) (any, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.items[key]
+	if !ok {
+		return nil, false
+	}
+	e := el.Value.(*entry)
+	if c.now().After(e.expiresAt) {
+		c.remove(el)
+		return nil, false
+	}
+	c.ll.MoveToFront(el)
+	return e.value, true
+}
+
+func (c *LRU) Set(key, group string, value any, size int64) {
+	if c.maxBytes > 0 && size > c.maxBytes/maxEntryFraction {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.items[key]; ok {
+		c.remove(el)
+	}
+	e := &entry{key: key, group: group, value: value, size: size, expiresAt: c.now().Add(c.ttl)}
+	c.items[key] = c.ll.PushFront(e)
+	c.groups[group]++
+	c.bytes += size
+
+	if c.groupQuota > 0 {
+		for c.groups[group] > c.groupQuota {
+			c.remove(c.oldestInGroup(group))
+		}
+	}
+	for (c.maxItems > 0 && c.ll.Len() > c.maxItems) || (c.maxBytes > 0 && c.bytes > c.maxBytes) {
+		c.remove(c.ll.Back())
+	}
+}
+
+// InvalidateGroup remove os itens do grupo.
+func (c *LRU) InvalidateGroup(group string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for el := c.ll.Back(); el != nil && c.groups[group] > 0; {
+		prev := el.Prev()
+		if el.Value.(*entry).group == group {
+			c.remove(el)
+		}
+		el = prev
+	}
+}
+
+// InvalidatePrefix remove os itens de todos os grupos iniciados por prefix.
+func (c *LRU) InvalidatePrefix(prefix string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for el := c.ll.Back(); el != nil; {
+		prev := el.Prev()
+		if strings.HasPrefix(el.Value.(*entry).group, prefix) {
+			c.remove(el)
+		}
+		el = prev
+	}
+}
+
+// Len retorna o número de itens armazenados.
+func (c *LRU) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.ll.Len()
+}
+
+// Bytes retorna o tamanho total armazenado.
+func (c *LRU) Bytes() int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.bytes
+}
+
+func (c *LRU) oldestInGroup(group string) *list.Element {
+	for el := c.ll.Back(); el != nil; el = el.Prev() {
+		if el.Value.(*entry).group == group {
+			return el
+		}
+	}
+	return nil
+}
+
+func (c *LRU) remove(el *list.Element) {
+	if el == nil {
+		return
+	}
+	e := el.Value.(*entry)
+	c.ll.Remove(el)
+	delete(c.items, e.key)
+	c.bytes -= e.size
+	if c.groups[e.group]--; c.groups[e.group] <= 0 {
+		delete(c.groups, e.group)
+	}
+}
@@ -0,0 +1,97 @@
+package memcache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLRU_EvictsLeastRecentlyUsed(t *testing.T) {
+	c := NewLRU(2, 0, time.Minute, 0)
+	c.Set("a", "t", 1, 1)
+	c.Set("b", "t", 2, 1)
+	_, _ = c.Get("a")
+	c.Set("c", "t", 3, 1)
+
+	_, ok := c.Get("b")
+	assert.False(t, ok, "b was least recently used")
+	v, ok := c.Get("a")
+	assert.True(t, ok)
+	assert.Equal(t, 1, v)
+	assert.Equal(t, 2, c.Len())
+}
+
+func TestLRU_ByteBudget(t *testing.T) {
+	c := NewLRU(0, 100, time.Minute, 0)
+	c.Set("a", "t", "a", 10)
+	c.Set("b", "t", "b", 10)
+	c.Set("big", "t", "big", 50) // acima de 10% do orçamento: não armazenado
+	_, ok := c.Get("big")
+	assert.False(t, ok)
+
+	for i := 0; i < 10; i++ {
+		c.Set(string(rune('c'+i)), "t", i, 10)
+	}
+	assert.LessOrEqual(t, c.Bytes(), int64(100))
+	_, ok = c.Get("a")
+	assert.False(t, ok)
+}
+
+func TestLRU_TTL(t *testing.T) {
+	c := NewLRU(10, 0, time.Minute, 0)
+	now := time.Now()
+	c.now = func() time.Time { return now }
+	c.Set("a", "t", 1, 1)
+
+	c.now = func() time.Time { return now.Add(2 * time.Minute) }
+	_, ok := c.Get("a")
+	assert.False(t, ok)
+	assert.Equal(t, 0, c.Len())
+}
+
+func TestLRU_GroupQuota(t *testing.T) {
+	c := NewLRU(10, 0, time.Minute, 2)
+	c.Set("u1", "users", 1, 1)
+	c.Set("o1", "orders", 1, 1)
+	c.Set("u2", "users", 2, 1)
+	c.Set("u3", "users", 3, 1)
+
+	_, ok := c.Get("u1")
+	assert.False(t, ok, "oldest entry of the group is evicted")
+	_, ok = c.Get("o1")
+	assert.True(t, ok, "other groups are untouched")
+	assert.Equal(t, 3, c.Len())
+}
+
+func TestLRU_InvalidateGroup(t *testing.T) {
+	c := NewLRU(10, 0, time.Minute, 0)
+	c.Set("a", "main/User", 1, 1)
+	c.Set("b", "main/Team", 2, 1)
+	c.Set("c", "main/User", 3, 1)
+
+	c.InvalidateGroup("main/User")
+	_, ok := c.Get("a")
+	assert.False(t, ok)
+	_, ok = c.Get("c")
+	assert.False(t, ok)
+	_, ok = c.Get("b")
+	assert.True(t, ok)
+	assert.Equal(t, 1, c.Len())
+}
+
+func TestLRU_InvalidatePrefix(t *testing.T) {
+	c := NewLRU(10, 0, time.Minute, 0)
+	c.Set("a", "main/User", 1, 1)
+	c.Set("b", "main/Team", 2, 1)
+	c.Set("c", "main2/User", 3, 1)
+
+	c.InvalidatePrefix("main/")
+	_, ok := c.Get("a")
+	assert.False(t, ok)
+	_, ok = c.Get("b")
+	assert.False(t, ok)
+	_, ok = c.Get("c")
+	assert.True(t, ok, "other datasources are untouched")
+	assert.Equal(t, 1, c.Len())
+}
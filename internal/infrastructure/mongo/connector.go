@@ -0,0 +1,228 @@
+package mongo
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readconcern"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+	"go.mongodb.org/mongo-driver/mongo/writeconcern"
+
+	"api-database/internal/domain/datasource"
+)
+
+// Connector executa comandos gerados pelo Translator em um datasource MongoDB.
+type Connector struct {
+	client *mongo.Client
+	db     *mongo.Database
+}
+
+// Open cria o connector de um datasource do tipo mongodb (datasource.DatabaseConnector).
+func Open(ctx context.Context, ds *datasource.DataSource) (datasource.DatabaseConnector, error) {
+	client, err := mongo.Connect(ctx, clientOptions(ds.Connection, ds.Pool))
+	if err != nil {
+		return nil, mapError(err)
+	}
+	if err := client.Ping(ctx, readpref.Primary()); err != nil {
+		_ = client.Disconnect(context.Background())
+		return nil, mapError(err)
+	}
+	return &Connector{client: client, db: client.Database(ds.Connection.Database)}, nil
+}
+
+func clientOptions(conn datasource.Connection, pool datasource.Pool) *options.ClientOptions {
+	uri := fmt.Sprintf("mongodb://%s:%d", conn.Host, conn.Port)
+	if conn.SSLMode != "" && conn.SSLMode != "disable" {
+		uri += "/?tls=true"
+	}
+	opts := options.Client().ApplyURI(uri)
+	if conn.User != "" {
+		opts.SetAuth(options.Credential{Username: conn.User, Password: conn.Password})
+	}
+	if pool.MaxConns > 0 {
+		opts.SetMaxPoolSize(uint64(pool.MaxConns))
+	}
+	if pool.MinConns > 0 {
+		opts.SetMinPoolSize(uint64(pool.MinConns))
+	}
+	if pool.MaxConnIdleTimeMs > 0 {
+		opts.SetMaxConnIdleTime(time.Duration(pool.MaxConnIdleTimeMs) * time.Millisecond)
+	}
+	return opts
+}
+
+// Query executa um comando que retorna cursor (find/aggregate) e normaliza os documentos.
+func (c *Connector) Query(ctx context.Context, stmt string, _ ...any) ([]map[string]any, error) {
+	return queryDocuments(ctx, c.db, stmt)
+}
+
+// Stream entrega os documentos a fn conforme o cursor avança.
+func (c *Connector) Stream(ctx context.Context, fn func(row map[string]any) error, stmt string, _ ...any) error {
+	return streamDocuments(ctx, c.db, fn, stmt)
+}
+
+// Execute executa um comando de escrita e retorna o campo "n" da resposta.
+func (c *Connector) Execute(ctx context.Context, stmt string, _ ...any) (int64, error) {
+	return executeCommand(ctx, c.db, stmt)
+}
+
+// Transaction executa fn em uma transação multi-documento (requer replica set).
+func (c *Connector) Transaction(ctx context.Context, opts datasource.TxOptions, fn func(tx datasource.Executor) error) error {
+	session, err := c.client.StartSession()
+	if err != nil {
+		return mapError(err)
+	}
+	defer session.EndSession(ctx)
+
+	txOpts := options.Transaction().SetWriteConcern(writeconcern.Majority())
+	if opts.Isolation == "serializable" || opts.Isolation == "repeatable read" {
+		txOpts.SetReadConcern(readconcern.Snapshot())
+	}
+	_, err = session.WithTransaction(ctx, func(sessCtx mongo.SessionContext) (any, error) {
+		return nil, fn(&sessionExecutor{ctx: sessCtx, db: c.db})
+	}, txOpts)
+	return mapError(err)
+}
+
+// Close encerra o client.
+func (c *Connector) Close() {
+	_ = c.client.Disconnect(context.Background())
+}
+
+// sessionExecutor executa comandos dentro da sessão da transação.
+type sessionExecutor struct {
+	ctx mongo.SessionContext
+	db  *mongo.Database
+}
+
+func (s *sessionExecutor) Query(_ context.Context, stmt string, _ ...any) ([]map[string]any, error) {
+	return queryDocuments(s.ctx, s.db, stmt)
+}
+
+func (s *sessionExecutor) Execute(_ context.Context, stmt string, _ ...any) (int64, error) {
+	return executeCommand(s.ctx, s.db, stmt)
+}
+
+func parseCommand(stmt string) (bson.D, error) {
+	var cmd bson.D
+	if err := bson.UnmarshalExtJSON([]byte(stmt), true, &cmd); err != nil {
+		return nil, fmt.Errorf("invalid mongo command: %w", err)
+	}
+	return cmd, nil
+}
+
+func queryDocuments(ctx context.Context, db *mongo.Database, stmt string) ([]map[string]any, error) {
+	var result []map[string]any
+	err := streamDocuments(ctx, db, func(row map[string]any) error {
+		result = append(result, row)
+		return nil
+	}, stmt)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// streamDocuments mapeia apenas os erros do driver; os de fn são devolvidos como vieram.
+func streamDocuments(ctx context.Context, db *mongo.Database, fn func(row map[string]any) error, stmt string) error {
+	cmd, err := parseCommand(stmt)
+	if err != nil {
+		return mapError(err)
+	}
+	cursor, err := db.RunCommandCursor(ctx, cmd)
+	if err != nil {
+		return mapError(err)
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var doc bson.M
+		if err := cursor.Decode(&doc); err != nil {
+			return mapError(err)
+		}
+		item := make(map[string]any, len(doc))
+		for k, v := range doc {
+			item[k] = normalizeValue(v)
+		}
+		if err := fn(item); err != nil {
+			return err
+		}
+	}
+	return mapError(cursor.Err())
+}
+
+func executeCommand(ctx context.Context, db *mongo.Database, stmt string) (int64, error) {
+	cmd, err := parseCommand(stmt)
+	if err != nil {
+		return 0, mapError(err)
+	}
+	var res bson.M
+	if err := db.RunCommand(ctx, cmd).Decode(&res); err != nil {
+		return 0, mapError(err)
+	}
+	switch n := res["n"].(type) {
+	case int32:
+		return int64(n), nil
+	case int64:
+		return n, nil
+	default:
+		return 0, nil
+	}
+}
+
+// normalizeValue converte tipos BSON nas mesmas formas JSON emitidas pelo connector Postgres.
+func normalizeValue(v any) any {
+	switch val := v.(type) {
+	case primitive.ObjectID:
+		return val.Hex()
+	case primitive.DateTime:
+		return val.Time().UTC().Format(time.RFC3339Nano)
+	case time.Time:
+		return val.UTC().Format(time.RFC3339Nano)
+	case primitive.Decimal128:
+		return val.String()
+	case primitive.Timestamp:
+		return time.Unix(int64(val.T), 0).UTC().Format(time.RFC3339Nano)
+	case primitive.Binary:
+		if (val.Subtype == 0x04 || val.Subtype == 0x03) && len(val.Data) == 16 {
+			return formatUUIDBytes(val.Data)
+		}
+		return string(val.Data)
+	case primitive.Regex:
+		return val.String()
+	case primitive.Null, primitive.Undefined:
+		return nil
+	case bson.M:
+		out := make(map[string]any, len(val))
+		for k, item := range val {
+			out[k] = normalizeValue(item)
+		}
+		return out
+	case bson.D:
+		out := make(map[string]any, len(val))
+		for _, e := range val {
+			out[e.Key] = normalizeValue(e.Value)
+		}
+		return out
+	case bson.A:
+		out := make([]any, len(val))
+		for i, item := range val {
+			out[i] = normalizeValue(item)
+		}
+		return out
+	default:
+		return v
+	}
+}
+
+// formatUUIDBytes converte 16 bytes em string UUID canonical (8-4-4-4-12).
+func formatUUIDBytes(b []byte) string {
+	hexStr := hex.EncodeToString(b)
+	return fmt.Sprintf("%s-%s-%s-%s-%s", hexStr[0:8], hexStr[8:12], hexStr[12:16], hexStr[16:20], hexStr[20:32])
+}
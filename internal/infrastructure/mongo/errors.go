@@ -0,0 +1,55 @@
+package mongo
+
+import (
+	"errors"
+	"net/http"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/x/mongo/driver/topology"
+
+	"api-database/internal/domain"
+)
+
+// mapError converte erros do driver MongoDB em AppError, como o connector Postgres: Details
+// traz apenas o código do servidor. A mensagem (com valores dos documentos ou, nos erros de
+// seleção de servidor, hosts e usuário) fica só como causa, para os logs.
+func mapError(err error) error {
+	if err == nil {
+		return nil
+	}
+	var appErr *domain.AppError
+	if errors.As(err, &appErr) {
+		return appErr
+	}
+
+	var cmdErr mongo.CommandError
+	if errors.As(err, &cmdErr) {
+		return mapCommandError(cmdErr)
+	}
+	if mongo.IsTimeout(err) {
+		return domain.NewAppError(domain.ErrQueryTimeout, "query timed out", http.StatusGatewayTimeout)
+	}
+	var selErr topology.ServerSelectionError
+	if mongo.IsNetworkError(err) || errors.As(err, &selErr) || errors.Is(err, mongo.ErrClientDisconnected) {
+		return domain.NewAppError(domain.ErrUnavailable, "datasource unavailable", http.StatusServiceUnavailable).WithCause(err)
+	}
+	return domain.QueryFailed(err)
+}
+
+func mapCommandError(cmdErr mongo.CommandError) *domain.AppError {
+	var err *domain.AppError
+	switch {
+	case cmdErr.HasErrorCode(50): // MaxTimeMSExpired
+		err = domain.NewAppError(domain.ErrQueryTimeout, "query timed out", http.StatusGatewayTimeout)
+	case cmdErr.HasErrorCode(13): // Unauthorized
+		err = domain.NewAppError(domain.ErrPermissionDenied, "permission denied on datasource", http.StatusForbidden)
+	case cmdErr.HasErrorCode(18): // AuthenticationFailed
+		err = domain.NewAppError(domain.ErrUnavailable, "datasource unavailable", http.StatusServiceUnavailable)
+	// chave duplicada, validação do schema, valores e expressões inválidos
+	case mongo.IsDuplicateKeyError(cmdErr), cmdErr.HasErrorCode(121), cmdErr.HasErrorCode(2), cmdErr.HasErrorCode(9):
+		err = domain.NewAppError(domain.ErrQueryFailed, "query rejected by datasource", http.StatusBadRequest)
+	default:
+		err = domain.NewAppError(domain.ErrQueryFailed, "query failed", http.StatusInternalServerError)
+	}
+	return err.WithDetails(map[string]interface{}{"errno": int(cmdErr.Code)}).WithCause(cmdErr)
+}
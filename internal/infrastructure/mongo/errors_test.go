@@ -0,0 +1,71 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"api-database/internal/domain"
+)
+
+func TestMapError(t *testing.T) {
+	cases := map[string]struct {
+		err     error
+		code    domain.ErrorCode
+		status  int
+		details map[string]interface{}
+	}{
+		"max time": {
+			mongo.CommandError{Code: 50, Name: "MaxTimeMSExpired", Message: "operation exceeded time limit"},
+			domain.ErrQueryTimeout, 504, map[string]interface{}{"errno": 50},
+		},
+		"unauthorized": {
+			mongo.CommandError{Code: 13, Name: "Unauthorized", Message: "not authorized on app to execute command"},
+			domain.ErrPermissionDenied, 403, map[string]interface{}{"errno": 13},
+		},
+		"bad credentials": {
+			fmt.Errorf("connect: %w", mongo.CommandError{Code: 18, Name: "AuthenticationFailed", Message: "Authentication failed."}),
+			domain.ErrUnavailable, 503, map[string]interface{}{"errno": 18},
+		},
+		"bad value": {
+			mongo.CommandError{Code: 2, Name: "BadValue", Message: "unknown operator: $foo"},
+			domain.ErrQueryFailed, 400, map[string]interface{}{"errno": 2},
+		},
+		"other": {
+			mongo.CommandError{Code: 1, Name: "InternalError", Message: "internal error"},
+			domain.ErrQueryFailed, 500, map[string]interface{}{"errno": 1},
+		},
+		"context deadline": {
+			fmt.Errorf("find: %w", context.DeadlineExceeded),
+			domain.ErrQueryTimeout, 504, nil,
+		},
+		"disconnected": {
+			mongo.ErrClientDisconnected,
+			domain.ErrUnavailable, 503, nil,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var appErr *domain.AppError
+			require.True(t, errors.As(mapError(tc.err), &appErr))
+			assert.Equal(t, tc.code, appErr.Code)
+			assert.Equal(t, tc.status, appErr.Status())
+			assert.Equal(t, tc.details, appErr.Details)
+		})
+	}
+}
+
+func TestMapError_UnclassifiedIsGeneric(t *testing.T) {
+	assert.NoError(t, mapError(nil))
+	plain := errors.New("server selection error: mongodb://app@db.internal:27017")
+	var appErr *domain.AppError
+	require.True(t, errors.As(mapError(plain), &appErr))
+	assert.Equal(t, domain.ErrQueryFailed, appErr.Code)
+	assert.Equal(t, "query failed", appErr.Message)
+	assert.ErrorIs(t, appErr, plain)
+}
@@ -0,0 +1,120 @@
+package mongo
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/gridfs"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"api-database/internal/domain/job"
+)
+
+const jobResultsBucket = "job_results"
+
+// ResultStoreGridFS implementa job.ResultStore usando GridFS (um arquivo JSON por job).
+type ResultStoreGridFS struct {
+	client   *mongo.Client
+	dbName   string
+	maxBytes int64
+}
+
+func NewResultStoreGridFS(client *mongo.Client, dbName string, maxBytes int64) *ResultStoreGridFS {
+	return &ResultStoreGridFS{client: client, dbName: dbName, maxBytes: maxBytes}
+}
+
+func (s *ResultStoreGridFS) bucket() (*gridfs.Bucket, error) {
+	return gridfs.NewBucket(s.client.Database(s.dbName), options.GridFSBucket().SetName(jobResultsBucket))
+}
+
+func (s *ResultStoreGridFS) Save(ctx context.Context, jobID string, rows []map[string]any, expiresAt time.Time) (int64, error) {
+	payload, err := json.Marshal(rows)
+	if err != nil {
+		return 0, err
+	}
+	if s.maxBytes > 0 && int64(len(payload)) > s.maxBytes {
+		return 0, job.ErrResultTooLarge
+	}
+
+	b, err := s.bucket()
+	if err != nil {
+		return 0, err
+	}
+	// Reprocessamento do mesmo job substitui o resultado anterior.
+	if err := b.DeleteContext(ctx, jobID); err != nil && !errors.Is(err, gridfs.ErrFileNotFound) {
+		return 0, err
+	}
+	opts := options.GridFSUpload().SetMetadata(bson.M{"expiresAt": expiresAt})
+	if err := b.UploadFromStreamWithID(jobID, jobID+".json", bytes.NewReader(payload), opts); err != nil {
+		return 0, err
+	}
+	return int64(len(payload)), nil
+}
+
+func (s *ResultStoreGridFS) Load(ctx context.Context, jobID string) ([]map[string]any, error) {
+	b, err := s.bucket()
+	if err != nil {
+		return nil, err
+	}
+
+	var file struct {
+		Metadata struct {
+			ExpiresAt time.Time `bson:"expiresAt"`
+		} `bson:"metadata"`
+	}
+	err = b.GetFilesCollection().FindOne(ctx, bson.M{"_id": jobID}).Decode(&file)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, job.ErrResultNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if time.Now().After(file.Metadata.ExpiresAt) {
+		return nil, job.ErrResultNotFound
+	}
+
+	var buf bytes.Buffer
+	if _, err := b.DownloadToStream(jobID, &buf); err != nil {
+		if errors.Is(err, gridfs.ErrFileNotFound) {
+			return nil, job.ErrResultNotFound
+		}
+		return nil, err
+	}
+	var rows []map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &rows); err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+func (s *ResultStoreGridFS) PurgeExpired(ctx context.Context, now time.Time) (int, error) {
+	b, err := s.bucket()
+	if err != nil {
+		return 0, err
+	}
+	cursor, err := b.FindContext(ctx, bson.M{"metadata.expiresAt": bson.M{"$lte": now}})
+	if err != nil {
+		return 0, err
+	}
+	defer cursor.Close(ctx)
+
+	removed := 0
+	for cursor.Next(ctx) {
+		var file struct {
+			ID any `bson:"_id"`
+		}
+		if err := cursor.Decode(&file); err != nil {
+			return removed, err
+		}
+		if err := b.DeleteContext(ctx, file.ID); err != nil && !errors.Is(err, gridfs.ErrFileNotFound) {
+			return removed, err
+		}
+		removed++
+	}
+	return removed, cursor.Err()
+}
@@ -0,0 +1,395 @@
+package mongo
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"api-database/internal/domain/query"
+)
+
+// Translator converte consultas neutras em comandos Mongo (find/aggregate)
+// serializados como Extended JSON canônico em Statement.Text.
+type Translator struct{}
+
+func NewTranslator() *Translator {
+	return &Translator{}
+}
+
+// Select gera um comando find com filter, projection, sort, skip e limit.
+func (t *Translator) Select(spec query.Select) (query.Statement, error) {
+	if spec.Aggregated() {
+		pipeline, err := groupPipeline(spec)
+		if err != nil {
+			return query.Statement{}, err
+		}
+		if sort := sortDoc(spec.OrderBy); len(sort) > 0 {
+			pipeline = append(pipeline, bson.D{{Key: "$sort", Value: sort}})
+		}
+		if spec.Offset > 0 {
+			pipeline = append(pipeline, bson.D{{Key: "$skip", Value: spec.Offset}})
+		}
+		if spec.Limit > 0 {
+			pipeline = append(pipeline, bson.D{{Key: "$limit", Value: spec.Limit}})
+		}
+		return aggregateCommand(spec.Table, pipeline)
+	}
+
+	filter, err := buildFilter(spec.Where)
+	if err != nil {
+		return query.Statement{}, err
+	}
+	if len(spec.After) > 0 {
+		seek, err := seekFilter(spec)
+		if err != nil {
+			return query.Statement{}, err
+		}
+		filter = bson.D{{Key: "$and", Value: bson.A{filter, seek}}}
+	}
+
+	cmd := bson.D{{Key: "find", Value: spec.Table}, {Key: "filter", Value: filter}}
+	if len(spec.Columns) > 0 {
+		projection := bson.D{}
+		includesID := false
+		for _, c := range spec.Columns {
+			projection = append(projection, bson.E{Key: c, Value: 1})
+			includesID = includesID || c == "_id"
+		}
+		if !includesID {
+			projection = append(projection, bson.E{Key: "_id", Value: 0})
+		}
+		cmd = append(cmd, bson.E{Key: "projection", Value: projection})
+	}
+	if sort := sortDoc(spec.OrderBy); len(sort) > 0 {
+		cmd = append(cmd, bson.E{Key: "sort", Value: sort})
+	}
+	if spec.Offset > 0 {
+		cmd = append(cmd, bson.E{Key: "skip", Value: spec.Offset})
+	}
+	if spec.Limit > 0 {
+		cmd = append(cmd, bson.E{Key: "limit", Value: spec.Limit})
+	}
+	return marshalCommand(cmd)
+}
+
+// Count gera um aggregate que retorna um único documento {total: n}. Em agregações,
+// conta os grupos resultantes.
+func (t *Translator) Count(spec query.Select) (query.Statement, error) {
+	var pipeline bson.A
+	if spec.Aggregated() {
+		stages, err := groupPipeline(spec)
+		if err != nil {
+			return query.Statement{}, err
+		}
+		pipeline = stages
+	} else {
+		filter, err := buildFilter(spec.Where)
+		if err != nil {
+			return query.Statement{}, err
+		}
+		pipeline = bson.A{bson.D{{Key: "$match", Value: filter}}}
+	}
+	pipeline = append(pipeline, bson.D{{Key: "$count", Value: "total"}})
+	return aggregateCommand(spec.Table, pipeline)
+}
+
+func aggregateCommand(collection string, pipeline bson.A) (query.Statement, error) {
+	return marshalCommand(bson.D{
+		{Key: "aggregate", Value: collection},
+		{Key: "pipeline", Value: pipeline},
+		{Key: "cursor", Value: bson.D{}},
+	})
+}
+
+func sortDoc(order []query.Order) bson.D {
+	sort := bson.D{}
+	for _, o := range order {
+		dir := 1
+		if o.Desc {
+			dir = -1
+		}
+		sort = append(sort, bson.E{Key: o.Column, Value: dir})
+	}
+	return sort
+}
+
+// groupPipeline gera $match → $group → $project (achata _id nas colunas de GroupBy) → $match (having).
+func groupPipeline(spec query.Select) (bson.A, error) {
+	filter, err := buildFilter(spec.Where)
+	if err != nil {
+		return nil, err
+	}
+
+	var id any // nil agrupa a coleção inteira
+	project := bson.D{{Key: "_id", Value: 0}}
+	if len(spec.GroupBy) > 0 {
+		key := bson.D{}
+		for _, c := range spec.GroupBy {
+			key = append(key, bson.E{Key: c, Value: "$" + c})
+			project = append(project, bson.E{Key: c, Value: "$_id." + c})
+		}
+		id = key
+	}
+	group := bson.D{{Key: "_id", Value: id}}
+	for _, a := range spec.Aggregates {
+		acc, err := accumulator(a)
+		if err != nil {
+			return nil, err
+		}
+		group = append(group, bson.E{Key: a.Alias, Value: acc})
+		project = append(project, bson.E{Key: a.Alias, Value: 1})
+	}
+
+	pipeline := bson.A{
+		bson.D{{Key: "$match", Value: filter}},
+		bson.D{{Key: "$group", Value: group}},
+		bson.D{{Key: "$project", Value: project}},
+	}
+	if spec.Having != nil {
+		having, err := buildFilter(spec.Having)
+		if err != nil {
+			return nil, err
+		}
+		pipeline = append(pipeline, bson.D{{Key: "$match", Value: having}})
+	}
+	return pipeline, nil
+}
+
+func accumulator(a query.Aggregate) (bson.D, error) {
+	if a.Column == "" {
+		if a.Func != query.AggCount {
+			return nil, fmt.Errorf("aggregate %s requires a column", a.Func)
+		}
+		return bson.D{{Key: "$sum", Value: 1}}, nil
+	}
+	field := "$" + a.Column
+	switch a.Func {
+	case query.AggCount:
+		// COUNT(col) ignora nulos e campos ausentes.
+		return bson.D{{Key: "$sum", Value: bson.D{{Key: "$cond", Value: bson.A{
+			bson.D{{Key: "$gt", Value: bson.A{field, nil}}}, 1, 0,
+		}}}}}, nil
+	case query.AggSum, query.AggAvg, query.AggMin, query.AggMax:
+		return bson.D{{Key: "$" + string(a.Func), Value: field}}, nil
+	default:
+		return nil, fmt.Errorf("unsupported aggregate function: %s", a.Func)
+	}
+}
+
+// seekFilter posiciona a paginação keyset após os valores de spec.After:
+// {$or: [{a: {$gt: x}}, {a: x, b: {$lt: y}}, ...]}.
+func seekFilter(spec query.Select) (bson.D, error) {
+	if len(spec.After) != len(spec.OrderBy) {
+		return nil, fmt.Errorf("seek values do not match order by columns")
+	}
+	branches := bson.A{}
+	for i, o := range spec.OrderBy {
+		prefix := bson.D{}
+		for j := 0; j < i; j++ {
+			col := spec.OrderBy[j].Column
+			values := seekValues(col, spec.After[j])
+			if len(values) == 1 {
+				prefix = append(prefix, bson.E{Key: col, Value: values[0]})
+			} else {
+				prefix = append(prefix, bson.E{Key: col, Value: bson.D{{Key: "$in", Value: values}}})
+			}
+		}
+		op := "$gt"
+		if o.Desc {
+			op = "$lt"
+		}
+		for _, v := range seekValues(o.Column, spec.After[i]) {
+			branch := append(bson.D{}, prefix...)
+			branch = append(branch, bson.E{Key: o.Column, Value: bson.D{{Key: op, Value: v}}})
+			branches = append(branches, branch)
+		}
+	}
+	return bson.D{{Key: "$or", Value: branches}}, nil
+}
+
+// seekValues devolve as formas possíveis de um valor do cursor. Datas saem da API como
+// strings RFC3339 e o cursor não guarda o tipo do campo: a string vale como data e como
+// texto (os operadores de comparação do MongoDB só casam valores do mesmo tipo).
+func seekValues(column string, v any) bson.A {
+	s, ok := v.(string)
+	if !ok {
+		return bson.A{v}
+	}
+	if column == "_id" {
+		if oid, err := primitive.ObjectIDFromHex(s); err == nil {
+			return bson.A{oid}
+		}
+	}
+	if t, err := time.Parse(time.RFC3339Nano, s); err == nil {
+		return bson.A{t, s}
+	}
+	return bson.A{s}
+}
+
+func marshalCommand(cmd bson.D) (query.Statement, error) {
+	b, err := bson.MarshalExtJSON(cmd, true, false)
+	if err != nil {
+		return query.Statement{}, err
+	}
+	return query.Statement{Text: string(b)}, nil
+}
+
+var comparisonOps = map[query.Operator]string{
+	query.OpEq:  "$eq",
+	query.OpNe:  "$ne",
+	query.OpGt:  "$gt",
+	query.OpGte: "$gte",
+	query.OpLt:  "$lt",
+	query.OpLte: "$lte",
+}
+
+func buildFilter(f *query.Filter) (bson.D, error) {
+	if f == nil {
+		return bson.D{}, nil
+	}
+	return translateFilter(*f)
+}
+
+func translateFilter(f query.Filter) (bson.D, error) {
+	switch f.Op {
+	case query.OpAnd, query.OpOr, query.OpNot:
+		children := bson.A{}
+		for _, child := range f.Children {
+			doc, err := translateFilter(child)
+			if err != nil {
+				return nil, err
+			}
+			children = append(children, doc)
+		}
+		if len(children) == 0 {
+			return bson.D{}, nil
+		}
+		switch f.Op {
+		case query.OpOr:
+			return bson.D{{Key: "$or", Value: children}}, nil
+		case query.OpNot:
+			// $not do Mongo só vale por campo; $nor com um único ramo nega o grupo todo.
+			return bson.D{{Key: "$nor", Value: bson.A{bson.D{{Key: "$and", Value: children}}}}}, nil
+		}
+		return bson.D{{Key: "$and", Value: children}}, nil
+	}
+
+	var cond bson.D
+	switch f.Op {
+	case query.OpIn, query.OpNin:
+		list, ok := f.Value.([]any)
+		if !ok || len(list) == 0 {
+			return nil, fmt.Errorf("operator %s requires a non-empty list", f.Op)
+		}
+		values := make(bson.A, len(list))
+		for i, v := range list {
+			value, err := filterValue(f.Column, v)
+			if err != nil {
+				return nil, err
+			}
+			values[i] = value
+		}
+		mongoOp := "$in"
+		if f.Op == query.OpNin {
+			mongoOp = "$nin"
+		}
+		cond = bson.D{{Key: mongoOp, Value: values}}
+	case query.OpBetween:
+		bounds, ok := f.Value.([]any)
+		if !ok || len(bounds) != 2 {
+			return nil, fmt.Errorf("operator %s requires [min, max]", f.Op)
+		}
+		lower, err := filterValue(f.Column, bounds[0])
+		if err != nil {
+			return nil, err
+		}
+		upper, err := filterValue(f.Column, bounds[1])
+		if err != nil {
+			return nil, err
+		}
+		cond = bson.D{{Key: "$gte", Value: lower}, {Key: "$lte", Value: upper}}
+	case query.OpIsNull:
+		// null também casa com campos ausentes, equivalente ao NULL do SQL.
+		if isNull, _ := f.Value.(bool); isNull {
+			cond = bson.D{{Key: "$eq", Value: nil}}
+		} else {
+			cond = bson.D{{Key: "$ne", Value: nil}}
+		}
+	case query.OpLike, query.OpILike:
+		pattern, ok := f.Value.(string)
+		if !ok {
+			return nil, fmt.Errorf("operator %s requires a string pattern", f.Op)
+		}
+		// Regex como valor direto: {$regex} não sobrevive ao round-trip de Extended JSON.
+		re := primitive.Regex{Pattern: likeToRegex(pattern)}
+		if f.Op == query.OpILike {
+			re.Options = "i"
+		}
+		return bson.D{{Key: f.Column, Value: re}}, nil
+	default:
+		mongoOp, ok := comparisonOps[f.Op]
+		if !ok {
+			return nil, fmt.Errorf("unsupported operator: %s", f.Op)
+		}
+		value, err := filterValue(f.Column, f.Value)
+		if err != nil {
+			return nil, err
+		}
+		cond = bson.D{{Key: mongoOp, Value: value}}
+	}
+	return bson.D{{Key: f.Column, Value: cond}}, nil
+}
+
+// likeToRegex converte um padrão LIKE (% e _) em expressão regular ancorada.
+func likeToRegex(pattern string) string {
+	var b strings.Builder
+	b.WriteString("^")
+	for _, r := range pattern {
+		switch r {
+		case '%':
+			b.WriteString(".*")
+		case '_':
+			b.WriteString(".")
+		default:
+			b.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	b.WriteString("$")
+	return b.String()
+}
+
+// filterValue aproxima a conversão implícita que o Postgres faz em parâmetros: _id
+// hexadecimal vira ObjectID. Sem o tipo do campo, uma string com formato de data pode ser
+// texto; datas precisam vir explícitas como {"$date": "2025-01-01T00:00:00Z"} (ou epoch em ms).
+func filterValue(column string, v any) (any, error) {
+	switch val := v.(type) {
+	case string:
+		if column == "_id" {
+			if oid, err := primitive.ObjectIDFromHex(val); err == nil {
+				return oid, nil
+			}
+		}
+		return val, nil
+	case map[string]any:
+		if raw, ok := val["$date"]; ok && len(val) == 1 {
+			return dateValue(column, raw)
+		}
+	}
+	return v, nil
+}
+
+func dateValue(column string, raw any) (time.Time, error) {
+	switch d := raw.(type) {
+	case string:
+		if t, err := time.Parse(time.RFC3339Nano, d); err == nil {
+			return t, nil
+		}
+	case float64:
+		return time.UnixMilli(int64(d)).UTC(), nil
+	}
+	return time.Time{}, fmt.Errorf("invalid $date for %s: expected an RFC3339 string or epoch milliseconds", column)
+}
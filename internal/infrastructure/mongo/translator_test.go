@@ -0,0 +1,214 @@
+package mongo
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"api-database/internal/domain/query"
+)
+
+func TestTranslatorSelect(t *testing.T) {
+	stmt, err := NewTranslator().Select(query.Select{
+		Table:   "users",
+		Columns: []string{"name", "email"},
+		Where: query.And(
+			query.Filter{Op: query.OpEq, Column: "role", Value: "PILOT"},
+			query.Filter{Op: query.OpGte, Column: "age", Value: float64(18)},
+		),
+		OrderBy: []query.Order{{Column: "name", Desc: true}},
+		Limit:   10,
+		Offset:  5,
+	})
+	require.NoError(t, err)
+
+	cmd, err := parseCommand(stmt.Text)
+	require.NoError(t, err)
+	m := cmd.Map()
+	assert.Equal(t, "users", m["find"])
+	assert.Equal(t, bson.D{{Key: "name", Value: int32(1)}, {Key: "email", Value: int32(1)}, {Key: "_id", Value: int32(0)}}, m["projection"])
+	assert.Equal(t, bson.D{{Key: "name", Value: int32(-1)}}, m["sort"])
+	assert.Equal(t, bson.D{{Key: "$and", Value: bson.A{
+		bson.D{{Key: "role", Value: bson.D{{Key: "$eq", Value: "PILOT"}}}},
+		bson.D{{Key: "age", Value: bson.D{{Key: "$gte", Value: float64(18)}}}},
+	}}}, m["filter"])
+	assert.EqualValues(t, 5, m["skip"])
+	assert.EqualValues(t, 10, m["limit"])
+}
+
+func TestTranslatorFilterValueConversion(t *testing.T) {
+	oid := primitive.NewObjectID()
+	stmt, err := NewTranslator().Count(query.Select{
+		Table: "users",
+		Where: query.And(
+			query.Filter{Op: query.OpEq, Column: "_id", Value: oid.Hex()},
+			query.Filter{Op: query.OpGt, Column: "createdAt", Value: map[string]any{"$date": "2025-01-01T00:00:00Z"}},
+			// Sem $date, texto com formato de data continua texto
+			query.Filter{Op: query.OpEq, Column: "code", Value: "2025-01-01T00:00:00Z"},
+		),
+	})
+	require.NoError(t, err)
+
+	cmd, err := parseCommand(stmt.Text)
+	require.NoError(t, err)
+	match := cmd.Map()["pipeline"].(bson.A)[0].(bson.D).Map()["$match"].(bson.D)
+	and := match.Map()["$and"].(bson.A)
+	assert.Equal(t, oid, and[0].(bson.D)[0].Value.(bson.D)[0].Value)
+	assert.Equal(t, primitive.NewDateTimeFromTime(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)), and[1].(bson.D)[0].Value.(bson.D)[0].Value)
+	assert.Equal(t, "2025-01-01T00:00:00Z", and[2].(bson.D)[0].Value.(bson.D)[0].Value)
+
+	_, err = NewTranslator().Select(query.Select{
+		Table: "users",
+		Where: query.And(query.Filter{Op: query.OpGt, Column: "createdAt", Value: map[string]any{"$date": "yesterday"}}),
+	})
+	assert.ErrorContains(t, err, "invalid $date for createdAt")
+}
+
+func TestNormalizeValue(t *testing.T) {
+	oid := primitive.NewObjectID()
+	dec, _ := primitive.ParseDecimal128("12.50")
+	when := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, oid.Hex(), normalizeValue(oid))
+	assert.Equal(t, "12.50", normalizeValue(dec))
+	assert.Equal(t, "2025-03-01T12:00:00Z", normalizeValue(primitive.NewDateTimeFromTime(when)))
+	assert.Equal(t, "67fb3c8f-2f6b-4b2e-b5f6-3e9c9efc1c89", normalizeValue(primitive.Binary{
+		Subtype: 0x04,
+		Data:    []byte{0x67, 0xfb, 0x3c, 0x8f, 0x2f, 0x6b, 0x4b, 0x2e, 0xb5, 0xf6, 0x3e, 0x9c, 0x9e, 0xfc, 0x1c, 0x89},
+	}))
+	assert.Equal(t, map[string]any{"id": oid.Hex(), "tags": []any{"a"}}, normalizeValue(bson.M{"id": oid, "tags": bson.A{"a"}}))
+}
+
+func TestTranslatorExtendedOperators(t *testing.T) {
+	stmt, err := NewTranslator().Select(query.Select{
+		Table: "users",
+		Where: query.And(
+			query.Filter{Op: query.OpIn, Column: "role", Value: []any{"PILOT", "ADMIN"}},
+			query.Filter{Op: query.OpNe, Column: "status", Value: "BANNED"},
+			query.Filter{Op: query.OpILike, Column: "email", Value: "%@acme.com"},
+			query.Filter{Op: query.OpIsNull, Column: "deletedAt", Value: true},
+			query.Filter{Op: query.OpBetween, Column: "age", Value: []any{float64(18), float64(30)}},
+		),
+	})
+	require.NoError(t, err)
+
+	cmd, err := parseCommand(stmt.Text)
+	require.NoError(t, err)
+	assert.Equal(t, bson.D{{Key: "$and", Value: bson.A{
+		bson.D{{Key: "role", Value: bson.D{{Key: "$in", Value: bson.A{"PILOT", "ADMIN"}}}}},
+		bson.D{{Key: "status", Value: bson.D{{Key: "$ne", Value: "BANNED"}}}},
+		bson.D{{Key: "email", Value: primitive.Regex{Pattern: `^.*@acme\.com$`, Options: "i"}}},
+		bson.D{{Key: "deletedAt", Value: bson.D{{Key: "$eq", Value: nil}}}},
+		bson.D{{Key: "age", Value: bson.D{{Key: "$gte", Value: float64(18)}, {Key: "$lte", Value: float64(30)}}}},
+	}}}, cmd.Map()["filter"])
+}
+
+func TestTranslatorBooleanGroups(t *testing.T) {
+	stmt, err := NewTranslator().Select(query.Select{
+		Table: "users",
+		Where: query.And(
+			query.Filter{Op: query.OpOr, Children: []query.Filter{
+				{Op: query.OpAnd, Children: []query.Filter{{Op: query.OpEq, Column: "status", Value: "A"}}},
+				{Op: query.OpAnd, Children: []query.Filter{{Op: query.OpEq, Column: "status", Value: "B"}}},
+			}},
+			query.Filter{Op: query.OpNot, Children: []query.Filter{{Op: query.OpEq, Column: "role", Value: "ADMIN"}}},
+		),
+	})
+	require.NoError(t, err)
+
+	cmd, err := parseCommand(stmt.Text)
+	require.NoError(t, err)
+	eq := func(col, v string) bson.D { return bson.D{{Key: col, Value: bson.D{{Key: "$eq", Value: v}}}} }
+	assert.Equal(t, bson.D{{Key: "$and", Value: bson.A{
+		bson.D{{Key: "$or", Value: bson.A{
+			bson.D{{Key: "$and", Value: bson.A{eq("status", "A")}}},
+			bson.D{{Key: "$and", Value: bson.A{eq("status", "B")}}},
+		}}},
+		bson.D{{Key: "$nor", Value: bson.A{bson.D{{Key: "$and", Value: bson.A{eq("role", "ADMIN")}}}}}},
+	}}}, cmd.Map()["filter"])
+}
+
+func TestTranslatorSeek(t *testing.T) {
+	oid := primitive.NewObjectID()
+	stmt, err := NewTranslator().Select(query.Select{
+		Table:   "users",
+		OrderBy: []query.Order{{Column: "age", Desc: true}, {Column: "_id", Desc: true}},
+		After:   []any{float64(30), oid.Hex()},
+	})
+	require.NoError(t, err)
+
+	cmd, err := parseCommand(stmt.Text)
+	require.NoError(t, err)
+	assert.Equal(t, bson.D{{Key: "$and", Value: bson.A{
+		bson.D{},
+		bson.D{{Key: "$or", Value: bson.A{
+			bson.D{{Key: "age", Value: bson.D{{Key: "$lt", Value: float64(30)}}}},
+			bson.D{{Key: "age", Value: float64(30)}, {Key: "_id", Value: bson.D{{Key: "$lt", Value: oid}}}},
+		}}},
+	}}}, cmd.Map()["filter"])
+}
+
+func TestTranslatorSeek_DateStrings(t *testing.T) {
+	stmt, err := NewTranslator().Select(query.Select{
+		Table:   "events",
+		OrderBy: []query.Order{{Column: "at"}, {Column: "_id"}},
+		After:   []any{"2025-01-01T00:00:00Z", float64(7)},
+	})
+	require.NoError(t, err)
+
+	// O cursor não sabe se "at" é data ou texto: os dois tipos entram no predicado
+	when := primitive.NewDateTimeFromTime(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+	cmd, err := parseCommand(stmt.Text)
+	require.NoError(t, err)
+	assert.Equal(t, bson.D{{Key: "$and", Value: bson.A{
+		bson.D{},
+		bson.D{{Key: "$or", Value: bson.A{
+			bson.D{{Key: "at", Value: bson.D{{Key: "$gt", Value: when}}}},
+			bson.D{{Key: "at", Value: bson.D{{Key: "$gt", Value: "2025-01-01T00:00:00Z"}}}},
+			bson.D{{Key: "at", Value: bson.D{{Key: "$in", Value: bson.A{when, "2025-01-01T00:00:00Z"}}}}, {Key: "_id", Value: bson.D{{Key: "$gt", Value: float64(7)}}}},
+		}}},
+	}}}, cmd.Map()["filter"])
+}
+
+func TestTranslatorAggregation(t *testing.T) {
+	stmt, err := NewTranslator().Select(query.Select{
+		Table:   "orders",
+		GroupBy: []string{"customerId"},
+		Aggregates: []query.Aggregate{
+			{Func: query.AggCount, Alias: "orders"},
+			{Func: query.AggAvg, Column: "amount", Alias: "avgAmount"},
+		},
+		Having:  query.And(query.Filter{Op: query.OpGte, Column: "orders", Value: float64(2)}),
+		OrderBy: []query.Order{{Column: "orders", Desc: true}},
+		Limit:   5,
+	})
+	require.NoError(t, err)
+
+	cmd, err := parseCommand(stmt.Text)
+	require.NoError(t, err)
+	m := cmd.Map()
+	assert.Equal(t, "orders", m["aggregate"])
+	assert.Equal(t, bson.A{
+		bson.D{{Key: "$match", Value: bson.D{}}},
+		bson.D{{Key: "$group", Value: bson.D{
+			{Key: "_id", Value: bson.D{{Key: "customerId", Value: "$customerId"}}},
+			{Key: "orders", Value: bson.D{{Key: "$sum", Value: int32(1)}}},
+			{Key: "avgAmount", Value: bson.D{{Key: "$avg", Value: "$amount"}}},
+		}}},
+		bson.D{{Key: "$project", Value: bson.D{
+			{Key: "_id", Value: int32(0)},
+			{Key: "customerId", Value: "$_id.customerId"},
+			{Key: "orders", Value: int32(1)},
+			{Key: "avgAmount", Value: int32(1)},
+		}}},
+		bson.D{{Key: "$match", Value: bson.D{{Key: "$and", Value: bson.A{
+			bson.D{{Key: "orders", Value: bson.D{{Key: "$gte", Value: float64(2)}}}},
+		}}}}},
+		bson.D{{Key: "$sort", Value: bson.D{{Key: "orders", Value: int32(-1)}}}},
+		bson.D{{Key: "$limit", Value: int32(5)}},
+	}, m["pipeline"])
+}
@@ -0,0 +1,105 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	driver "github.com/go-sql-driver/mysql"
+
+	"api-database/internal/domain/datasource"
+	"api-database/internal/infrastructure/sqldb"
+)
+
+// Open cria o connector de um datasource do tipo mysql (datasource.DatabaseConnector).
+func Open(ctx context.Context, ds *datasource.DataSource) (datasource.DatabaseConnector, error) {
+	connector, err := driver.NewConnector(buildConfig(ds.Connection))
+	if err != nil {
+		return nil, mapError(err)
+	}
+	db := sql.OpenDB(connector)
+	sqldb.ApplyPool(db, ds.Pool)
+	if err := db.PingContext(ctx); err != nil {
+		_ = db.Close()
+		return nil, mapError(err)
+	}
+	return sqldb.NewConnector(db, valueNormalizer(ds.UUIDColumns)).WithErrorMapper(mapError), nil
+}
+
+func buildConfig(conn datasource.Connection) *driver.Config {
+	cfg := driver.NewConfig()
+	cfg.User = conn.User
+	cfg.Passwd = conn.Password
+	cfg.Net = "tcp"
+	cfg.Addr = fmt.Sprintf("%s:%d", conn.Host, conn.Port)
+	cfg.DBName = conn.Database
+	cfg.ParseTime = true
+	cfg.Loc = time.UTC
+	switch conn.SSLMode {
+	case "", "disable":
+	case "skip-verify", "preferred":
+		cfg.TLSConfig = conn.SSLMode
+	default:
+		cfg.TLSConfig = "true"
+	}
+	return cfg
+}
+
+// valueNormalizer converte como UUID apenas as colunas binárias listadas em
+// DataSource.UUIDColumns: o MySQL não tem tipo UUID e nem todo BINARY(16) guarda um.
+func valueNormalizer(uuidColumns []string) sqldb.ValueNormalizer {
+	uuids := make(map[string]bool, len(uuidColumns))
+	for _, col := range uuidColumns {
+		uuids[col] = true
+	}
+	return func(column, dbType string, v any) any {
+		return normalizeValue(dbType, v, uuids[column])
+	}
+}
+
+// normalizeValue produz as mesmas formas JSON do connector Postgres:
+// datas em RFC3339 UTC, DECIMAL como string, BINARY(16) de colunas UUID como UUID e BIT como inteiro.
+func normalizeValue(dbType string, v any, uuid bool) any {
+	switch val := v.(type) {
+	case time.Time:
+		return val.UTC().Format(time.RFC3339Nano)
+	case []byte:
+		return normalizeBytes(strings.ToUpper(dbType), val, uuid)
+	default:
+		return v
+	}
+}
+
+func normalizeBytes(dbType string, b []byte, uuid bool) any {
+	switch dbType {
+	case "BIT":
+		var buf [8]byte
+		copy(buf[8-min(len(b), 8):], b)
+		return binary.BigEndian.Uint64(buf[:])
+	case "BINARY", "VARBINARY":
+		if uuid && len(b) == 16 {
+			return formatUUIDBytes(b)
+		}
+		return string(b)
+	case "JSON":
+		var out any
+		if err := json.Unmarshal(b, &out); err == nil {
+			return out
+		}
+		return string(b)
+	default:
+		// DECIMAL, VARCHAR, TEXT, ENUM etc.
+		return string(b)
+	}
+}
+
+// formatUUIDBytes converte 16 bytes em string UUID canonical (8-4-4-4-12).
+func formatUUIDBytes(b []byte) string {
+	hexStr := hex.EncodeToString(b)
+	return fmt.Sprintf("%s-%s-%s-%s-%s", hexStr[0:8], hexStr[8:12], hexStr[12:16], hexStr[16:20], hexStr[20:32])
+}
@@ -0,0 +1,46 @@
+package mysql
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"api-database/internal/domain/query"
+)
+
+func TestNormalizeValue(t *testing.T) {
+	when := time.Date(2025, 3, 1, 12, 0, 0, 0, time.FixedZone("BRT", -3*3600))
+	uuidBytes := []byte{0x67, 0xfb, 0x3c, 0x8f, 0x2f, 0x6b, 0x4b, 0x2e, 0xb5, 0xf6, 0x3e, 0x9c, 0x9e, 0xfc, 0x1c, 0x89}
+
+	assert.Equal(t, "2025-03-01T15:00:00Z", normalizeValue("DATETIME", when, false))
+	assert.Equal(t, "1234.50", normalizeValue("DECIMAL", []byte("1234.50"), false))
+	assert.Equal(t, uint64(1), normalizeValue("BIT", []byte{0x01}, false))
+	assert.Equal(t, uint64(258), normalizeValue("BIT", []byte{0x01, 0x02}, false))
+	assert.Equal(t, "67fb3c8f-2f6b-4b2e-b5f6-3e9c9efc1c89", normalizeValue("BINARY", uuidBytes, true))
+	assert.Equal(t, "abcdefghijklmnop", normalizeValue("VARCHAR", []byte("abcdefghijklmnop"), false))
+	assert.Equal(t, map[string]any{"a": float64(1)}, normalizeValue("JSON", []byte(`{"a":1}`), false))
+	assert.Equal(t, int64(7), normalizeValue("INT", int64(7), false))
+	assert.Nil(t, normalizeValue("VARCHAR", nil, false))
+}
+
+func TestValueNormalizer_UUIDOnlyForConfiguredColumns(t *testing.T) {
+	normalize := valueNormalizer([]string{"id"})
+	hash := []byte("0123456789abcdef")
+
+	assert.Equal(t, "30313233-3435-3637-3839-616263646566", normalize("id", "BINARY", hash))
+	assert.Equal(t, "0123456789abcdef", normalize("digest", "BINARY", hash))
+	assert.Equal(t, "0123456789abcdef", normalize("digest", "VARBINARY", hash))
+}
+
+func TestTranslator(t *testing.T) {
+	stmt, err := NewTranslator().Select(query.Select{
+		Table: "orders",
+		Where: query.And(query.Filter{Op: query.OpEq, Column: "status", Value: "paid"}),
+		Limit: 10,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM `orders` WHERE `status` = ? LIMIT ? OFFSET ?", stmt.Text)
+	assert.Equal(t, "`we``ird`", Dialect{}.QuoteIdent("we`ird"))
+}
@@ -0,0 +1,71 @@
+package mysql
+
+import (
+	"strings"
+
+	"api-database/internal/infrastructure/sqlbuilder"
+)
+
+// Dialect implementa sqlbuilder.Dialect para MySQL/MariaDB (crases e ?).
+type Dialect struct{}
+
+func (Dialect) QuoteIdent(name string) string {
+	return "`" + strings.ReplaceAll(name, "`", "``") + "`"
+}
+
+func (Dialect) Placeholder(int) string {
+	return "?"
+}
+
+// NewTranslator retorna o tradutor SQL do dialeto MySQL.
+func NewTranslator() *sqlbuilder.Translator {
+	return sqlbuilder.NewTranslator(Dialect{})
+}
+
+// NewInspector lê o catálogo do information_schema (schema padrão DATABASE()).
+func NewInspector() *sqlbuilder.Inspector {
+	return sqlbuilder.NewInspector(sqlbuilder.Catalog{
+		Tables: func(schemaName string) (string, []any) {
+			return tablesQuery, []any{schemaName}
+		},
+		Columns: func(schemaName, table string) (string, []any) {
+			return columnsQuery, []any{schemaName, table}
+		},
+		PrimaryKey: func(schemaName, table string) (string, []any) {
+			return primaryKeyQuery, []any{schemaName, table}
+		},
+		Indexes: func(schemaName, table string) (string, []any) {
+			return indexesQuery, []any{schemaName, table}
+		},
+		ForeignKeys: func(schemaName, table string) (string, []any) {
+			return foreignKeysQuery, []any{schemaName, table, table}
+		},
+	})
+}
+
+const tablesQuery = `SELECT TABLE_NAME AS table_name FROM information_schema.TABLES
+WHERE TABLE_SCHEMA = COALESCE(NULLIF(?, ''), DATABASE())
+ORDER BY TABLE_NAME`
+
+// columnsQuery usa COLUMN_TYPE, que preserva tamanho e sinal (ex.: int unsigned, varchar(255)).
+const columnsQuery = `SELECT COLUMN_NAME AS column_name, COLUMN_TYPE AS data_type, IS_NULLABLE AS is_nullable
+FROM information_schema.COLUMNS
+WHERE TABLE_SCHEMA = COALESCE(NULLIF(?, ''), DATABASE()) AND TABLE_NAME = ?
+ORDER BY ORDINAL_POSITION`
+
+const primaryKeyQuery = `SELECT COLUMN_NAME AS column_name
+FROM information_schema.KEY_COLUMN_USAGE
+WHERE TABLE_SCHEMA = COALESCE(NULLIF(?, ''), DATABASE()) AND TABLE_NAME = ? AND CONSTRAINT_NAME = 'PRIMARY'
+ORDER BY ORDINAL_POSITION`
+
+const indexesQuery = `SELECT INDEX_NAME AS index_name, COLUMN_NAME AS column_name, NON_UNIQUE = 0 AS is_unique
+FROM information_schema.STATISTICS
+WHERE TABLE_SCHEMA = COALESCE(NULLIF(?, ''), DATABASE()) AND TABLE_NAME = ?
+ORDER BY INDEX_NAME, SEQ_IN_INDEX`
+
+const foreignKeysQuery = `SELECT CONSTRAINT_NAME AS name, TABLE_NAME AS table_name, COLUMN_NAME AS column_name,
+	REFERENCED_TABLE_NAME AS ref_table, REFERENCED_COLUMN_NAME AS ref_column
+FROM information_schema.KEY_COLUMN_USAGE
+WHERE TABLE_SCHEMA = COALESCE(NULLIF(?, ''), DATABASE()) AND REFERENCED_TABLE_NAME IS NOT NULL
+	AND (TABLE_NAME = ? OR REFERENCED_TABLE_NAME = ?)
+ORDER BY TABLE_NAME, CONSTRAINT_NAME, ORDINAL_POSITION`
@@ -0,0 +1,85 @@
+package mysql
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"net"
+	"net/http"
+	"regexp"
+	"strings"
+
+	mysqldriver "github.com/go-sql-driver/mysql"
+
+	"api-database/internal/domain"
+)
+
+// quotedName captura o primeiro identificador entre aspas simples da mensagem do servidor
+// (ex.: Table 'app.Usr' doesn't exist, Unknown column 'mail' in 'field list').
+var quotedName = regexp.MustCompile(`'([^']+)'`)
+
+// mapError converte erros do driver MySQL em AppError, como o connector Postgres: Details
+// traz apenas o número do erro, o SQLSTATE e o identificador envolvido. A mensagem do
+// servidor e erros de conexão (com host e usuário do DSN) ficam só como causa, para os logs.
+func mapError(err error) error {
+	if err == nil {
+		return nil
+	}
+	var appErr *domain.AppError
+	if errors.As(err, &appErr) {
+		return appErr
+	}
+
+	var myErr *mysqldriver.MySQLError
+	if errors.As(err, &myErr) {
+		return mapMySQLError(myErr)
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return domain.NewAppError(domain.ErrQueryTimeout, "query timed out", http.StatusGatewayTimeout)
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) || errors.Is(err, mysqldriver.ErrInvalidConn) || errors.Is(err, driver.ErrBadConn) {
+		return domain.NewAppError(domain.ErrUnavailable, "datasource unavailable", http.StatusServiceUnavailable).WithCause(err)
+	}
+	return domain.QueryFailed(err)
+}
+
+func mapMySQLError(myErr *mysqldriver.MySQLError) *domain.AppError {
+	details := map[string]interface{}{"errno": int(myErr.Number)}
+	if myErr.SQLState != [5]byte{} {
+		details["sqlstate"] = string(myErr.SQLState[:])
+	}
+	name := func() string {
+		if m := quotedName.FindStringSubmatch(myErr.Message); m != nil {
+			return m[1]
+		}
+		return ""
+	}
+
+	var err *domain.AppError
+	switch myErr.Number {
+	case 1146: // ER_NO_SUCH_TABLE (a mensagem traz database.tabela)
+		err = domain.NewAppError(domain.ErrUndefinedTable, "table does not exist", http.StatusNotFound)
+		if table := name(); table != "" {
+			details["table"] = table[strings.LastIndex(table, ".")+1:]
+		}
+	case 1054: // ER_BAD_FIELD_ERROR
+		err = domain.NewAppError(domain.ErrUndefinedColumn, "column does not exist", http.StatusBadRequest)
+		if column := name(); column != "" {
+			details["column"] = column
+		}
+	case 3024, 1317, 1969: // max_execution_time, query interrompida, max_statement_time (MariaDB)
+		err = domain.NewAppError(domain.ErrQueryTimeout, "query timed out", http.StatusGatewayTimeout)
+	case 1142, 1143, 1044: // comando, coluna ou database sem privilégio
+		err = domain.NewAppError(domain.ErrPermissionDenied, "permission denied on datasource", http.StatusForbidden)
+	// credenciais do datasource inválidas, too many connections, servidor encerrando
+	case 1045, 1040, 1203, 1053:
+		err = domain.NewAppError(domain.ErrUnavailable, "datasource unavailable", http.StatusServiceUnavailable)
+	// chave duplicada, FK, NOT NULL, valores fora do tipo
+	case 1062, 1451, 1452, 1048, 1264, 1292, 1366, 1406:
+		err = domain.NewAppError(domain.ErrQueryFailed, "query rejected by datasource", http.StatusBadRequest)
+	default:
+		err = domain.NewAppError(domain.ErrQueryFailed, "query failed", http.StatusInternalServerError)
+	}
+	return err.WithDetails(details).WithCause(myErr)
+}
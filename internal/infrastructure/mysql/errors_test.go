@@ -0,0 +1,85 @@
+package mysql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+
+	mysqldriver "github.com/go-sql-driver/mysql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"api-database/internal/domain"
+)
+
+func TestMapError(t *testing.T) {
+	cases := map[string]struct {
+		err     error
+		code    domain.ErrorCode
+		status  int
+		details map[string]interface{}
+	}{
+		"undefined table": {
+			&mysqldriver.MySQLError{Number: 1146, SQLState: [5]byte{'4', '2', 'S', '0', '2'}, Message: "Table 'app.Usr' doesn't exist"},
+			domain.ErrUndefinedTable, 404, map[string]interface{}{"errno": 1146, "sqlstate": "42S02", "table": "Usr"},
+		},
+		"undefined column": {
+			fmt.Errorf("query: %w", &mysqldriver.MySQLError{Number: 1054, Message: "Unknown column 'mail' in 'field list'"}),
+			domain.ErrUndefinedColumn, 400, map[string]interface{}{"errno": 1054, "column": "mail"},
+		},
+		"max execution time": {
+			&mysqldriver.MySQLError{Number: 3024, Message: "Query execution was interrupted, maximum statement execution time exceeded"},
+			domain.ErrQueryTimeout, 504, map[string]interface{}{"errno": 3024},
+		},
+		"permission": {
+			&mysqldriver.MySQLError{Number: 1142, Message: "SELECT command denied to user 'app'@'10.0.0.5' for table 'secrets'"},
+			domain.ErrPermissionDenied, 403, map[string]interface{}{"errno": 1142},
+		},
+		"bad credentials": {
+			&mysqldriver.MySQLError{Number: 1045, Message: "Access denied for user 'app'@'10.0.0.5' (using password: YES)"},
+			domain.ErrUnavailable, 503, map[string]interface{}{"errno": 1045},
+		},
+		"duplicate key": {
+			&mysqldriver.MySQLError{Number: 1062, Message: "Duplicate entry 'a@example.com' for key 'email'"},
+			domain.ErrQueryFailed, 400, map[string]interface{}{"errno": 1062},
+		},
+		"other": {
+			&mysqldriver.MySQLError{Number: 1105, Message: "unknown error"},
+			domain.ErrQueryFailed, 500, map[string]interface{}{"errno": 1105},
+		},
+		"dial": {
+			&net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connect: connection refused")},
+			domain.ErrUnavailable, 503, nil,
+		},
+		"invalid connection": {
+			mysqldriver.ErrInvalidConn,
+			domain.ErrUnavailable, 503, nil,
+		},
+		"context deadline": {
+			fmt.Errorf("ping: %w", context.DeadlineExceeded),
+			domain.ErrQueryTimeout, 504, nil,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var appErr *domain.AppError
+			require.True(t, errors.As(mapError(tc.err), &appErr))
+			assert.Equal(t, tc.code, appErr.Code)
+			assert.Equal(t, tc.status, appErr.Status())
+			assert.Equal(t, tc.details, appErr.Details)
+			assert.NotContains(t, appErr.Message, "10.0.0.5")
+		})
+	}
+}
+
+func TestMapError_UnclassifiedIsGeneric(t *testing.T) {
+	assert.NoError(t, mapError(nil))
+	plain := errors.New("default addr for network 'db.internal:3306' unknown")
+	var appErr *domain.AppError
+	require.True(t, errors.As(mapError(plain), &appErr))
+	assert.Equal(t, domain.ErrQueryFailed, appErr.Code)
+	assert.Equal(t, "query failed", appErr.Message)
+	assert.ErrorIs(t, appErr, plain)
+}
@@ -0,0 +1,121 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"api-database/internal/domain/datasource"
+)
+
+// resultColumnsQuery completa as FieldDescriptions com o nome formatado do tipo, a categoria
+// (pg_type.typcategory) e a nulabilidade das colunas que vêm diretamente de uma tabela;
+// expressões (attnum 0) são consideradas anuláveis.
+const resultColumnsQuery = `SELECT format_type(f.typ, f.mod), COALESCE(t.typcategory::text, ''), COALESCE(NOT a.attnotnull, true)
+FROM unnest($1::oid[], $2::int4[], $3::oid[], $4::int4[]) WITH ORDINALITY AS f(typ, mod, rel, att, n)
+LEFT JOIN pg_type t ON t.oid = f.typ
+LEFT JOIN pg_attribute a ON a.attrelid = f.rel AND a.attnum = f.att AND f.att > 0
+ORDER BY f.n`
+
+// representation é a forma JSON de um tipo depois de normalizeValue.
+type representation struct {
+	json   string
+	format string
+}
+
+var representations = map[uint32]representation{
+	pgtype.BoolOID:        {"boolean", ""},
+	pgtype.Int2OID:        {"number", "integer"},
+	pgtype.Int4OID:        {"number", "integer"},
+	pgtype.Int8OID:        {"number", "integer"},
+	pgtype.OIDOID:         {"number", "integer"},
+	pgtype.Float4OID:      {"number", ""},
+	pgtype.Float8OID:      {"number", ""},
+	pgtype.NumericOID:     {"string", "decimal"},
+	pgtype.UUIDOID:        {"string", "uuid"},
+	pgtype.DateOID:        {"string", "date-time"},
+	pgtype.TimestampOID:   {"string", "date-time"},
+	pgtype.TimestamptzOID: {"string", "date-time"},
+	pgtype.TimeOID:        {"string", "time"},
+	pgtype.IntervalOID:    {"string", "duration"},
+	pgtype.JSONOID:        {"json", ""},
+	pgtype.JSONBOID:       {"json", ""},
+	pgtype.InetOID:        {"string", "inet"},
+	pgtype.CIDROID:        {"string", "cidr"},
+	pgtype.MacaddrOID:     {"string", "macaddr"},
+	pgtype.BitOID:         {"string", "bits"},
+	pgtype.VarbitOID:      {"string", "bits"},
+	// Multiranges têm a categoria dos ranges, mas viram arrays de ranges.
+	pgtype.Int4multirangeOID: {"array", ""},
+	pgtype.Int8multirangeOID: {"array", ""},
+	pgtype.NummultirangeOID:  {"array", ""},
+	pgtype.DatemultirangeOID: {"array", ""},
+	pgtype.TsmultirangeOID:   {"array", ""},
+	pgtype.TstzmultirangeOID: {"array", ""},
+}
+
+// categoryRepresentations cobre tipos sem entrada própria (domínios, enums, arrays e ranges
+// de qualquer tipo) pela categoria do catálogo.
+var categoryRepresentations = map[string]representation{
+	"A": {"array", ""},
+	"R": {"object", ""},
+	"B": {"boolean", ""},
+	"N": {"number", ""},
+}
+
+func representationOf(oid uint32, category string) representation {
+	if r, ok := representations[oid]; ok {
+		return r
+	}
+	if r, ok := categoryRepresentations[category]; ok {
+		return r
+	}
+	// Texto, enums e tipos desconhecidos chegam como string (ou bytes convertidos em string).
+	return representation{json: "string"}
+}
+
+// describeFields consulta o catálogo para descrever as colunas do resultado.
+func describeFields(ctx context.Context, q querier, fields []pgconn.FieldDescription) ([]datasource.ResultColumn, error) {
+	if len(fields) == 0 {
+		return []datasource.ResultColumn{}, nil
+	}
+	types := make([]uint32, len(fields))
+	mods := make([]int32, len(fields))
+	tables := make([]uint32, len(fields))
+	attrs := make([]int32, len(fields))
+	for i, fd := range fields {
+		types[i] = fd.DataTypeOID
+		mods[i] = fd.TypeModifier
+		tables[i] = fd.TableOID
+		attrs[i] = int32(fd.TableAttributeNumber)
+	}
+
+	rows, err := q.Query(ctx, resultColumnsQuery, types, mods, tables, attrs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	columns := make([]datasource.ResultColumn, 0, len(fields))
+	for i := 0; rows.Next(); i++ {
+		var typeName, category string
+		var nullable bool
+		if err := rows.Scan(&typeName, &category, &nullable); err != nil {
+			return nil, err
+		}
+		columns = append(columns, resultColumn(fields[i], typeName, category, nullable))
+	}
+	return columns, rows.Err()
+}
+
+func resultColumn(fd pgconn.FieldDescription, typeName, category string, nullable bool) datasource.ResultColumn {
+	r := representationOf(fd.DataTypeOID, category)
+	return datasource.ResultColumn{
+		Name:     fd.Name,
+		Type:     typeName,
+		JSON:     r.json,
+		Format:   r.format,
+		Nullable: nullable,
+	}
+}
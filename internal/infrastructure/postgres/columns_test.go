@@ -0,0 +1,43 @@
+package postgres
+
+import (
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+
+	"api-database/internal/domain/datasource"
+)
+
+func TestResultColumn(t *testing.T) {
+	cases := []struct {
+		name     string
+		oid      uint32
+		typeName string
+		category string
+		want     datasource.ResultColumn
+	}{
+		{"uuid", pgtype.UUIDOID, "uuid", "U", datasource.ResultColumn{Type: "uuid", JSON: "string", Format: "uuid"}},
+		{"int8", pgtype.Int8OID, "bigint", "N", datasource.ResultColumn{Type: "bigint", JSON: "number", Format: "integer"}},
+		{"numeric", pgtype.NumericOID, "numeric(10,2)", "N", datasource.ResultColumn{Type: "numeric(10,2)", JSON: "string", Format: "decimal"}},
+		{"timestamptz", pgtype.TimestamptzOID, "timestamp with time zone", "D", datasource.ResultColumn{Type: "timestamp with time zone", JSON: "string", Format: "date-time"}},
+		{"jsonb", pgtype.JSONBOID, "jsonb", "U", datasource.ResultColumn{Type: "jsonb", JSON: "json"}},
+		{"varchar", pgtype.VarcharOID, "character varying(255)", "S", datasource.ResultColumn{Type: "character varying(255)", JSON: "string"}},
+		{"int array", pgtype.Int4ArrayOID, "integer[]", "A", datasource.ResultColumn{Type: "integer[]", JSON: "array"}},
+		{"interval", pgtype.IntervalOID, "interval", "T", datasource.ResultColumn{Type: "interval", JSON: "string", Format: "duration"}},
+		{"multirange", pgtype.Int4multirangeOID, "int4multirange", "R", datasource.ResultColumn{Type: "int4multirange", JSON: "array"}},
+		{"enum", 16500, "role", "E", datasource.ResultColumn{Type: "role", JSON: "string"}},
+		{"range", pgtype.TstzrangeOID, "tstzrange", "R", datasource.ResultColumn{Type: "tstzrange", JSON: "object"}},
+		{"boolean domain", 16600, "flag", "B", datasource.ResultColumn{Type: "flag", JSON: "boolean"}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			fd := pgconn.FieldDescription{Name: "col", DataTypeOID: tc.oid}
+			want := tc.want
+			want.Name = "col"
+			want.Nullable = true
+			assert.Equal(t, want, resultColumn(fd, tc.typeName, tc.category, true))
+		})
+	}
+}
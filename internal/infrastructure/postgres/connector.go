@@ -2,60 +2,267 @@ package postgres
 
 import (
 	"context"
-	"encoding/hex"
 	"fmt"
 	"net/url"
+	"strconv"
 	"time"
 
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
+
+	"api-database/internal/domain/datasource"
 )
 
 // Connector executa consultas simples em PostgreSQL.
 type Connector struct {
 	pool *pgxpool.Pool
+	// statementTimeout é o statement_timeout das conexões do pool (zero = padrão do servidor).
+	statementTimeout time.Duration
+}
+
+// Open cria o connector de um datasource (datasource.DatabaseConnector).
+func Open(ctx context.Context, ds *datasource.DataSource) (datasource.DatabaseConnector, error) {
+	return NewConnector(ctx, ds.Connection, ds.Pool, ds.Limits)
 }
 
-// NewConnector cria um pool pgx a partir dos dados de conexão.
-func NewConnector(ctx context.Context, host string, port int, user, password, database, sslMode string) (*Connector, error) {
+// NewConnector cria um pool pgx a partir dos dados de conexão, do dimensionamento e dos
+// limites do datasource.
+func NewConnector(ctx context.Context, conn datasource.Connection, poolCfg datasource.Pool, limits datasource.Limits) (*Connector, error) {
+	cfg, err := buildPoolConfig(conn, poolCfg, limits)
+	if err != nil {
+		return nil, err
+	}
+	pool, err := pgxpool.NewWithConfig(ctx, cfg)
+	if err != nil {
+		return nil, mapError(err)
+	}
+	if err := pool.Ping(ctx); err != nil {
+		pool.Close()
+		return nil, mapError(err)
+	}
+	return &Connector{pool: pool, statementTimeout: time.Duration(limits.QueryTimeoutMs) * time.Millisecond}, nil
+}
+
+// buildPoolConfig monta a configuração do pgxpool aplicando os limites do datasource.
+// Limits.QueryTimeoutMs vira o statement_timeout das conexões: o servidor aborta a
+// consulta mesmo que o cancelamento pelo contexto não chegue.
+func buildPoolConfig(conn datasource.Connection, poolCfg datasource.Pool, limits datasource.Limits) (*pgxpool.Config, error) {
+	sslMode := conn.SSLMode
 	if sslMode == "" {
 		sslMode = "disable"
 	}
 	pgURL := &url.URL{
 		Scheme: "postgres",
-		User:   url.UserPassword(user, password),
-		Host:   fmt.Sprintf("%s:%d", host, port),
-		Path:   "/" + database,
+		User:   url.UserPassword(conn.User, conn.Password),
+		Host:   fmt.Sprintf("%s:%d", conn.Host, conn.Port),
+		Path:   "/" + conn.Database,
 		RawQuery: url.Values{
 			"sslmode": []string{sslMode},
 		}.Encode(),
 	}
-	connStr := pgURL.String()
-	pool, err := pgxpool.New(ctx, connStr)
+	cfg, err := pgxpool.ParseConfig(pgURL.String())
 	if err != nil {
 		return nil, err
 	}
-	if err := pool.Ping(ctx); err != nil {
-		pool.Close()
-		return nil, err
+	if poolCfg.MaxConns > 0 {
+		cfg.MaxConns = int32(poolCfg.MaxConns)
+	}
+	if poolCfg.MinConns > 0 {
+		cfg.MinConns = int32(poolCfg.MinConns)
+	}
+	if poolCfg.MaxConnIdleTimeMs > 0 {
+		cfg.MaxConnIdleTime = time.Duration(poolCfg.MaxConnIdleTimeMs) * time.Millisecond
+	}
+	if poolCfg.MaxConnLifetimeMs > 0 {
+		cfg.MaxConnLifetime = time.Duration(poolCfg.MaxConnLifetimeMs) * time.Millisecond
 	}
-	return &Connector{pool: pool}, nil
+	if limits.QueryTimeoutMs > 0 {
+		cfg.ConnConfig.RuntimeParams["statement_timeout"] = strconv.Itoa(limits.QueryTimeoutMs)
+	}
+	cfg.AfterConnect = func(_ context.Context, c *pgx.Conn) error {
+		registerTypes(c.TypeMap())
+		return nil
+	}
+	return cfg, nil
 }
 
 // Query retorna linhas como slice de map[string]any.
 func (c *Connector) Query(ctx context.Context, sql string, args ...any) ([]map[string]any, error) {
-	rows, err := c.pool.Query(ctx, sql, args...)
+	if timeout, ok := c.extendedTimeout(ctx); ok {
+		var rows []map[string]any
+		err := pgx.BeginFunc(ctx, c.pool, func(tx pgx.Tx) error {
+			if err := setLocalTimeout(ctx, tx, timeout); err != nil {
+				return err
+			}
+			var err error
+			rows, err = queryRows(ctx, tx, sql, args...)
+			return err
+		})
+		return rows, mapError(err)
+	}
+	rows, err := queryRows(ctx, c.pool, sql, args...)
+	return rows, mapError(err)
+}
+
+// Stream entrega as linhas a fn conforme chegam do servidor.
+func (c *Connector) Stream(ctx context.Context, fn func(row map[string]any) error, sql string, args ...any) error {
+	if timeout, ok := c.extendedTimeout(ctx); ok {
+		return mapError(pgx.BeginFunc(ctx, c.pool, func(tx pgx.Tx) error {
+			if err := setLocalTimeout(ctx, tx, timeout); err != nil {
+				return err
+			}
+			_, err := streamRows(ctx, tx, fn, sql, args...)
+			return err
+		}))
+	}
+	_, err := streamRows(ctx, c.pool, fn, sql, args...)
+	return mapError(err)
+}
+
+// QueryColumns é Query acompanhada da descrição das colunas do resultado
+// (datasource.ColumnDescriber), obtida das FieldDescriptions e do catálogo.
+func (c *Connector) QueryColumns(ctx context.Context, sql string, args ...any) ([]map[string]any, []datasource.ResultColumn, error) {
+	var rows []map[string]any
+	var columns []datasource.ResultColumn
+	run := func(q querier) error {
+		var fields []pgconn.FieldDescription
+		var err error
+		if rows, fields, err = queryFields(ctx, q, sql, args...); err != nil {
+			return err
+		}
+		columns, err = describeFields(ctx, q, fields)
+		return err
+	}
+	var err error
+	if timeout, ok := c.extendedTimeout(ctx); ok {
+		err = pgx.BeginFunc(ctx, c.pool, func(tx pgx.Tx) error {
+			if err := setLocalTimeout(ctx, tx, timeout); err != nil {
+				return err
+			}
+			return run(tx)
+		})
+	} else {
+		err = run(c.pool)
+	}
+	if err != nil {
+		return nil, nil, mapError(err)
+	}
+	return rows, columns, nil
+}
+
+// Execute executa uma instrução sem retorno de linhas e informa as linhas afetadas.
+func (c *Connector) Execute(ctx context.Context, sql string, args ...any) (int64, error) {
+	if timeout, ok := c.extendedTimeout(ctx); ok {
+		var affected int64
+		err := pgx.BeginFunc(ctx, c.pool, func(tx pgx.Tx) error {
+			if err := setLocalTimeout(ctx, tx, timeout); err != nil {
+				return err
+			}
+			tag, err := tx.Exec(ctx, sql, args...)
+			affected = tag.RowsAffected()
+			return err
+		})
+		return affected, mapError(err)
+	}
+	tag, err := c.pool.Exec(ctx, sql, args...)
+	if err != nil {
+		return 0, mapError(err)
+	}
+	return tag.RowsAffected(), nil
+}
+
+// Transaction executa fn em uma transação; erro em fn (ou panic) provoca rollback.
+func (c *Connector) Transaction(ctx context.Context, opts datasource.TxOptions, fn func(tx datasource.Executor) error) error {
+	txOpts := pgx.TxOptions{IsoLevel: pgx.TxIsoLevel(opts.Isolation)}
+	if opts.ReadOnly {
+		txOpts.AccessMode = pgx.ReadOnly
+	}
+	err := pgx.BeginTxFunc(ctx, c.pool, txOpts, func(tx pgx.Tx) error {
+		if timeout, ok := c.extendedTimeout(ctx); ok {
+			if err := setLocalTimeout(ctx, tx, timeout); err != nil {
+				return err
+			}
+		}
+		return fn(&txExecutor{tx: tx})
+	})
+	return mapError(err)
+}
+
+// extendedTimeout indica se o prazo do contexto excede o statement_timeout do pool (ex.:
+// orçamento maior das consultas assíncronas) e retorna o tempo restante.
+func (c *Connector) extendedTimeout(ctx context.Context) (time.Duration, bool) {
+	if c.statementTimeout <= 0 {
+		return 0, false
+	}
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return 0, false
+	}
+	remaining := time.Until(deadline)
+	return remaining, remaining > c.statementTimeout
+}
+
+// setLocalTimeout eleva o statement_timeout apenas até o fim da transação.
+func setLocalTimeout(ctx context.Context, tx pgx.Tx, timeout time.Duration) error {
+	_, err := tx.Exec(ctx, "SET LOCAL statement_timeout = "+strconv.FormatInt(timeout.Milliseconds(), 10))
+	return err
+}
+
+// txExecutor expõe uma pgx.Tx como datasource.Executor.
+type txExecutor struct {
+	tx pgx.Tx
+}
+
+func (t *txExecutor) Query(ctx context.Context, sql string, args ...any) ([]map[string]any, error) {
+	rows, err := queryRows(ctx, t.tx, sql, args...)
+	return rows, mapError(err)
+}
+
+func (t *txExecutor) Execute(ctx context.Context, sql string, args ...any) (int64, error) {
+	tag, err := t.tx.Exec(ctx, sql, args...)
+	if err != nil {
+		return 0, mapError(err)
+	}
+	return tag.RowsAffected(), nil
+}
+
+// querier é satisfeito por *pgxpool.Pool e pgx.Tx.
+type querier interface {
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+}
+
+func queryRows(ctx context.Context, q querier, sql string, args ...any) ([]map[string]any, error) {
+	result, _, err := queryFields(ctx, q, sql, args...)
+	return result, err
+}
+
+func queryFields(ctx context.Context, q querier, sql string, args ...any) ([]map[string]any, []pgconn.FieldDescription, error) {
+	var result []map[string]any
+	fields, err := streamRows(ctx, q, func(row map[string]any) error {
+		result = append(result, row)
+		return nil
+	}, sql, args...)
+	if err != nil {
+		return nil, nil, err
+	}
+	return result, fields, nil
+}
+
+// streamRows entrega as linhas a fn e retorna uma cópia das FieldDescriptions do resultado.
+func streamRows(ctx context.Context, q querier, fn func(row map[string]any) error, sql string, args ...any) ([]pgconn.FieldDescription, error) {
+	rows, err := q.Query(ctx, sql, args...)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	fieldDescriptions := rows.FieldDescriptions()
-	cols := make([]string, len(fieldDescriptions))
-	for i, fd := range fieldDescriptions {
-		cols[i] = string(fd.Name)
+	fields := append([]pgconn.FieldDescription(nil), rows.FieldDescriptions()...)
+	cols := make([]string, len(fields))
+	for i, fd := range fields {
+		cols[i] = fd.Name
 	}
 
-	var result []map[string]any
 	for rows.Next() {
 		values, err := rows.Values()
 		if err != nil {
@@ -65,38 +272,14 @@ func (c *Connector) Query(ctx context.Context, sql string, args ...any) ([]map[s
 		for i, col := range cols {
 			item[col] = normalizeValue(values[i])
 		}
-		result = append(result, item)
+		if err := fn(item); err != nil {
+			return nil, err
+		}
 	}
-	return result, rows.Err()
+	return fields, rows.Err()
 }
 
 // Close fecha o pool.
 func (c *Connector) Close() {
 	c.pool.Close()
 }
-
-// normalizeValue converte tipos binários/UUID/time em formas mais amigáveis.
-func normalizeValue(v any) any {
-	switch val := v.(type) {
-	case [16]byte:
-		return formatUUIDBytes(val[:])
-	case []byte:
-		if len(val) == 16 {
-			return formatUUIDBytes(val)
-		}
-		return string(val)
-	case time.Time:
-		return val.UTC().Format(time.RFC3339Nano)
-	default:
-		return v
-	}
-}
-
-// formatUUIDBytes converte 16 bytes em string UUID canonical (8-4-4-4-12).
-func formatUUIDBytes(b []byte) string {
-	if len(b) != 16 {
-		return string(b)
-	}
-	hexStr := hex.EncodeToString(b)
-	return fmt.Sprintf("%s-%s-%s-%s-%s", hexStr[0:8], hexStr[8:12], hexStr[12:16], hexStr[16:20], hexStr[20:32])
-}
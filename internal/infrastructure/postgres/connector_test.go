@@ -0,0 +1,81 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"api-database/internal/domain/datasource"
+	"api-database/internal/domain/query"
+)
+
+func TestBuildPoolConfig(t *testing.T) {
+	conn := datasource.Connection{Host: "db", Port: 5432, User: "u", Password: "p@ss", Database: "app"}
+
+	cfg, err := buildPoolConfig(conn, datasource.Pool{
+		MaxConns:          8,
+		MinConns:          2,
+		MaxConnIdleTimeMs: 30000,
+		MaxConnLifetimeMs: 600000,
+	}, datasource.Limits{QueryTimeoutMs: 2500})
+	require.NoError(t, err)
+	assert.Equal(t, int32(8), cfg.MaxConns)
+	assert.Equal(t, int32(2), cfg.MinConns)
+	assert.Equal(t, 30*time.Second, cfg.MaxConnIdleTime)
+	assert.Equal(t, 10*time.Minute, cfg.MaxConnLifetime)
+	assert.Equal(t, "db", cfg.ConnConfig.Host)
+	assert.Equal(t, "p@ss", cfg.ConnConfig.Password)
+	assert.Equal(t, "2500", cfg.ConnConfig.RuntimeParams["statement_timeout"])
+}
+
+func TestBuildPoolConfig_Defaults(t *testing.T) {
+	defaults, err := buildPoolConfig(datasource.Connection{Host: "db", Port: 5432}, datasource.Pool{}, datasource.Limits{})
+	require.NoError(t, err)
+	assert.Greater(t, defaults.MaxConns, int32(0))
+	assert.Equal(t, int32(0), defaults.MinConns)
+	assert.NotContains(t, defaults.ConnConfig.RuntimeParams, "statement_timeout")
+}
+
+func TestExtendedTimeout(t *testing.T) {
+	c := &Connector{statementTimeout: time.Second}
+
+	_, ok := c.extendedTimeout(context.Background())
+	assert.False(t, ok)
+
+	short, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+	_, ok = c.extendedTimeout(short)
+	assert.False(t, ok)
+
+	long, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	timeout, ok := c.extendedTimeout(long)
+	assert.True(t, ok)
+	assert.Greater(t, timeout, 50*time.Second)
+
+	_, ok = (&Connector{}).extendedTimeout(long)
+	assert.False(t, ok)
+}
+
+func TestDialectArrayValue(t *testing.T) {
+	v, err := Dialect{}.ArrayValue([]any{"a", `b"c\d`, float64(1.5), float64(3), true, nil})
+	require.NoError(t, err)
+	assert.Equal(t, `{"a","b\"c\\d","1.5","3","true",NULL}`, v)
+
+	_, err = Dialect{}.ArrayValue([]any{map[string]any{"a": 1}})
+	assert.Error(t, err)
+
+	stmt, err := NewTranslator().Select(query.Select{
+		Table: "User",
+		Where: query.And(
+			query.Filter{Op: query.OpIn, Column: "role", Value: []any{"PILOT", "ADMIN"}},
+			query.Filter{Op: query.OpNin, Column: "id", Value: []any{float64(7)}},
+		),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, `SELECT * FROM "User" WHERE "role" = ANY($1) AND "id" <> ALL($2) LIMIT $3 OFFSET $4`, stmt.Text)
+	assert.Equal(t, []any{`{"PILOT","ADMIN"}`, `{"7"}`, 0, 0}, stmt.Args)
+}
@@ -0,0 +1,167 @@
+package postgres
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"api-database/internal/infrastructure/sqlbuilder"
+)
+
+// Dialect implementa sqlbuilder.Dialect para PostgreSQL (aspas duplas e $n).
+type Dialect struct{}
+
+func (Dialect) QuoteIdent(name string) string {
+	return "\"" + strings.ReplaceAll(name, "\"", "\"\"") + "\""
+}
+
+func (Dialect) Placeholder(n int) string {
+	return "$" + strconv.Itoa(n)
+}
+
+// ILike usa o operador nativo do PostgreSQL.
+func (Dialect) ILike(column, placeholder string) string {
+	return column + " ILIKE " + placeholder
+}
+
+// AnyOf compara com um único parâmetro de array: o número de parâmetros não cresce com a
+// lista e o plano da consulta é o mesmo para qualquer tamanho.
+func (Dialect) AnyOf(column, placeholder string, negate bool) string {
+	if negate {
+		return column + " <> ALL(" + placeholder + ")"
+	}
+	return column + " = ANY(" + placeholder + ")"
+}
+
+// ArrayValue monta o literal de array em texto ({"a","b"}). Como nos parâmetros escalares
+// enviados como texto, o servidor converte cada elemento para o tipo da coluna.
+func (Dialect) ArrayValue(values []any) (any, error) {
+	var b strings.Builder
+	b.WriteByte('{')
+	for i, v := range values {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		elem, ok := arrayElement(v)
+		if !ok {
+			return nil, fmt.Errorf("unsupported list value: %v", v)
+		}
+		if v == nil {
+			b.WriteString("NULL")
+			continue
+		}
+		b.WriteByte('"')
+		b.WriteString(arrayEscaper.Replace(elem))
+		b.WriteByte('"')
+	}
+	b.WriteByte('}')
+	return b.String(), nil
+}
+
+// arrayEscaper escapa aspas e barras dentro de um elemento entre aspas.
+var arrayEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+
+// arrayElement formata um valor escalar do filtro como texto.
+func arrayElement(v any) (string, bool) {
+	switch val := v.(type) {
+	case nil:
+		return "", true
+	case string:
+		return val, true
+	case bool:
+		return strconv.FormatBool(val), true
+	case float64:
+		return strconv.FormatFloat(val, 'f', -1, 64), true
+	case float32:
+		return strconv.FormatFloat(float64(val), 'f', -1, 32), true
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, json.Number:
+		return fmt.Sprint(val), true
+	case time.Time:
+		return val.Format(time.RFC3339Nano), true
+	default:
+		return "", false
+	}
+}
+
+// Returning devolve as linhas afetadas pelas escritas.
+func (Dialect) Returning(columns string) string {
+	return "RETURNING " + columns
+}
+
+// NewTranslator retorna o tradutor SQL do dialeto PostgreSQL.
+func NewTranslator() *sqlbuilder.Translator {
+	return sqlbuilder.NewTranslator(Dialect{})
+}
+
+// NewInspector lê o catálogo via information_schema e pg_catalog (schema padrão "public").
+func NewInspector() *sqlbuilder.Inspector {
+	return sqlbuilder.NewInspector(sqlbuilder.Catalog{
+		Tables: func(schemaName string) (string, []any) {
+			return tablesQuery, []any{defaultSchema(schemaName)}
+		},
+		Columns: func(schemaName, table string) (string, []any) {
+			return columnsQuery, []any{defaultSchema(schemaName), table}
+		},
+		PrimaryKey: func(schemaName, table string) (string, []any) {
+			return primaryKeyQuery, []any{defaultSchema(schemaName), table}
+		},
+		Indexes: func(schemaName, table string) (string, []any) {
+			return indexesQuery, []any{defaultSchema(schemaName), table}
+		},
+		ForeignKeys: func(schemaName, table string) (string, []any) {
+			return foreignKeysQuery, []any{defaultSchema(schemaName), table}
+		},
+	})
+}
+
+func defaultSchema(name string) string {
+	if name == "" {
+		return "public"
+	}
+	return name
+}
+
+const tablesQuery = `SELECT table_name FROM information_schema.tables
+WHERE table_schema = $1 AND table_type IN ('BASE TABLE', 'VIEW')
+ORDER BY table_name`
+
+// columnsQuery usa udt_name para tipos definidos pelo usuário e arrays (ex.: _int4).
+const columnsQuery = `SELECT column_name,
+	CASE WHEN data_type IN ('USER-DEFINED', 'ARRAY') THEN udt_name ELSE data_type END AS data_type,
+	is_nullable
+FROM information_schema.columns
+WHERE table_schema = $1 AND table_name = $2
+ORDER BY ordinal_position`
+
+const primaryKeyQuery = `SELECT kcu.column_name
+FROM information_schema.table_constraints tc
+JOIN information_schema.key_column_usage kcu
+	ON kcu.constraint_schema = tc.constraint_schema AND kcu.constraint_name = tc.constraint_name
+	AND kcu.table_name = tc.table_name
+WHERE tc.constraint_type = 'PRIMARY KEY' AND tc.table_schema = $1 AND tc.table_name = $2
+ORDER BY kcu.ordinal_position`
+
+// indexesQuery expande pg_index.indkey; colunas de expressão (attnum 0) ficam de fora.
+const indexesQuery = `SELECT i.relname AS index_name, a.attname AS column_name, ix.indisunique AS is_unique
+FROM pg_index ix
+JOIN pg_class t ON t.oid = ix.indrelid
+JOIN pg_namespace n ON n.oid = t.relnamespace
+JOIN pg_class i ON i.oid = ix.indexrelid
+CROSS JOIN LATERAL unnest(ix.indkey::int2[]) WITH ORDINALITY AS k(attnum, ord)
+JOIN pg_attribute a ON a.attrelid = t.oid AND a.attnum = k.attnum
+WHERE n.nspname = $1 AND t.relname = $2
+ORDER BY i.relname, k.ord`
+
+// foreignKeysQuery pareia colunas de FKs compostas via position_in_unique_constraint.
+const foreignKeysQuery = `SELECT kcu.constraint_name AS name, kcu.table_name, kcu.column_name,
+	ref.table_name AS ref_table, ref.column_name AS ref_column
+FROM information_schema.referential_constraints rc
+JOIN information_schema.key_column_usage kcu
+	ON kcu.constraint_schema = rc.constraint_schema AND kcu.constraint_name = rc.constraint_name
+JOIN information_schema.key_column_usage ref
+	ON ref.constraint_schema = rc.unique_constraint_schema AND ref.constraint_name = rc.unique_constraint_name
+	AND ref.ordinal_position = kcu.position_in_unique_constraint
+WHERE kcu.table_schema = $1 AND (kcu.table_name = $2 OR ref.table_name = $2)
+ORDER BY kcu.table_name, kcu.constraint_name, kcu.ordinal_position`
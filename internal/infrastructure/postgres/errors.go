@@ -0,0 +1,85 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"api-database/internal/domain"
+)
+
+// quotedName captura o primeiro identificador entre aspas da mensagem do servidor
+// (ex.: relation "User" does not exist).
+var quotedName = regexp.MustCompile(`"([^"]+)"`)
+
+// mapError converte erros do pgx em AppError. Details traz apenas o SQLSTATE e o
+// identificador envolvido: mensagem, Detail, Hint e Where podem conter valores das
+// linhas, trechos do SQL ou dados de conexão e não são repassados ao cliente (ficam só
+// como causa, para os logs). Erros não classificados viram QUERY_FAILED.
+func mapError(err error) error {
+	if err == nil {
+		return nil
+	}
+	var appErr *domain.AppError
+	if errors.As(err, &appErr) {
+		return appErr
+	}
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return mapPgError(pgErr)
+	}
+	if pgconn.Timeout(err) || errors.Is(err, context.DeadlineExceeded) {
+		return domain.NewAppError(domain.ErrQueryTimeout, "query timed out", http.StatusGatewayTimeout)
+	}
+	var connErr *pgconn.ConnectError
+	if errors.As(err, &connErr) {
+		return domain.NewAppError(domain.ErrUnavailable, "datasource unavailable", http.StatusServiceUnavailable).WithCause(err)
+	}
+	return domain.QueryFailed(err)
+}
+
+func mapPgError(pgErr *pgconn.PgError) *domain.AppError {
+	details := map[string]interface{}{"sqlstate": pgErr.Code}
+	name := func(field string) string {
+		if field != "" {
+			return field
+		}
+		if m := quotedName.FindStringSubmatch(pgErr.Message); m != nil {
+			return m[1]
+		}
+		return ""
+	}
+
+	var err *domain.AppError
+	switch {
+	case pgErr.Code == "42P01": // undefined_table
+		err = domain.NewAppError(domain.ErrUndefinedTable, "table does not exist", http.StatusNotFound)
+		if table := name(pgErr.TableName); table != "" {
+			details["table"] = table
+		}
+	case pgErr.Code == "42703": // undefined_column
+		err = domain.NewAppError(domain.ErrUndefinedColumn, "column does not exist", http.StatusBadRequest)
+		if column := name(pgErr.ColumnName); column != "" {
+			details["column"] = column
+		}
+	case pgErr.Code == "57014": // query_canceled (statement_timeout ou cancelamento)
+		err = domain.NewAppError(domain.ErrQueryTimeout, "query timed out", http.StatusGatewayTimeout)
+	case pgErr.Code == "42501": // insufficient_privilege
+		err = domain.NewAppError(domain.ErrPermissionDenied, "permission denied on datasource", http.StatusForbidden)
+	// connection_exception, credenciais do datasource inválidas, too_many_connections,
+	// shutdown e cannot_connect_now
+	case strings.HasPrefix(pgErr.Code, "08"), strings.HasPrefix(pgErr.Code, "28"),
+		pgErr.Code == "53300", pgErr.Code == "57P01", pgErr.Code == "57P02", pgErr.Code == "57P03":
+		err = domain.NewAppError(domain.ErrUnavailable, "datasource unavailable", http.StatusServiceUnavailable)
+	case strings.HasPrefix(pgErr.Code, "22"), strings.HasPrefix(pgErr.Code, "23"): // dados inválidos / restrições
+		err = domain.NewAppError(domain.ErrQueryFailed, "query rejected by datasource", http.StatusBadRequest)
+	default:
+		err = domain.NewAppError(domain.ErrQueryFailed, "query failed", http.StatusInternalServerError)
+	}
+	return err.WithDetails(details).WithCause(pgErr)
+}
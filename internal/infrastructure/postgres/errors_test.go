@@ -0,0 +1,86 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"api-database/internal/domain"
+)
+
+func TestMapError(t *testing.T) {
+	cases := map[string]struct {
+		err     error
+		code    domain.ErrorCode
+		status  int
+		details map[string]interface{}
+	}{
+		"undefined table": {
+			&pgconn.PgError{Code: "42P01", Message: `relation "Usr" does not exist`, Position: 15},
+			domain.ErrUndefinedTable, 404, map[string]interface{}{"sqlstate": "42P01", "table": "Usr"},
+		},
+		"undefined column": {
+			fmt.Errorf("query: %w", &pgconn.PgError{Code: "42703", Message: `column "mail" does not exist`, Hint: `Perhaps you meant "email".`}),
+			domain.ErrUndefinedColumn, 400, map[string]interface{}{"sqlstate": "42703", "column": "mail"},
+		},
+		"statement timeout": {
+			&pgconn.PgError{Code: "57014", Message: "canceling statement due to statement timeout"},
+			domain.ErrQueryTimeout, 504, map[string]interface{}{"sqlstate": "57014"},
+		},
+		"permission": {
+			&pgconn.PgError{Code: "42501", Message: "permission denied for table secrets"},
+			domain.ErrPermissionDenied, 403, map[string]interface{}{"sqlstate": "42501"},
+		},
+		"too many connections": {
+			&pgconn.PgError{Code: "53300", Message: "sorry, too many clients already"},
+			domain.ErrUnavailable, 503, map[string]interface{}{"sqlstate": "53300"},
+		},
+		"bad credentials": {
+			&pgconn.PgError{Code: "28P01", Message: `password authentication failed for user "app"`},
+			domain.ErrUnavailable, 503, map[string]interface{}{"sqlstate": "28P01"},
+		},
+		"invalid data": {
+			&pgconn.PgError{Code: "22P02", Message: `invalid input syntax for type integer: "abc"`, Detail: "row data"},
+			domain.ErrQueryFailed, 400, map[string]interface{}{"sqlstate": "22P02"},
+		},
+		"other": {
+			&pgconn.PgError{Code: "XX000", Message: "internal error"},
+			domain.ErrQueryFailed, 500, map[string]interface{}{"sqlstate": "XX000"},
+		},
+		"context deadline": {
+			fmt.Errorf("acquire: %w", context.DeadlineExceeded),
+			domain.ErrQueryTimeout, 504, nil,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var appErr *domain.AppError
+			require.True(t, errors.As(mapError(tc.err), &appErr))
+			assert.Equal(t, tc.code, appErr.Code)
+			assert.Equal(t, tc.status, appErr.Status())
+			assert.Equal(t, tc.details, appErr.Details)
+		})
+	}
+}
+
+func TestMapError_PassThrough(t *testing.T) {
+	assert.NoError(t, mapError(nil))
+	appErr := domain.NewAppError(domain.ErrInvalidInput, "bad", 400)
+	assert.Same(t, appErr, mapError(appErr))
+}
+
+func TestMapError_UnclassifiedIsGeneric(t *testing.T) {
+	plain := errors.New(`failed to connect to host=db.internal user=app: closed pool`)
+	var appErr *domain.AppError
+	require.True(t, errors.As(mapError(plain), &appErr))
+	assert.Equal(t, domain.ErrQueryFailed, appErr.Code)
+	assert.Equal(t, "query failed", appErr.Message)
+	assert.Equal(t, 500, appErr.Status())
+	// A mensagem original fica apenas como causa (logs)
+	assert.Same(t, plain, errors.Unwrap(appErr))
+}
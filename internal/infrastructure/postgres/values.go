@@ -0,0 +1,250 @@
+package postgres
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net"
+	"net/netip"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// registerTypes ajusta o mapa de tipos de cada conexão: json/jsonb (e seus arrays) chegam
+// como json.RawMessage em vez de map[string]any/float64, preservando números grandes.
+func registerTypes(m *pgtype.Map) {
+	jsonType := &pgtype.Type{Name: "json", OID: pgtype.JSONOID, Codec: rawJSONCodec{pgtype.JSONCodec{}}}
+	jsonbType := &pgtype.Type{Name: "jsonb", OID: pgtype.JSONBOID, Codec: rawJSONCodec{pgtype.JSONBCodec{}}}
+	m.RegisterType(jsonType)
+	m.RegisterType(jsonbType)
+	m.RegisterType(&pgtype.Type{Name: "_json", OID: pgtype.JSONArrayOID, Codec: &pgtype.ArrayCodec{ElementType: jsonType}})
+	m.RegisterType(&pgtype.Type{Name: "_jsonb", OID: pgtype.JSONBArrayOID, Codec: &pgtype.ArrayCodec{ElementType: jsonbType}})
+}
+
+// rawJSONCodec mantém a codificação do codec original e decodifica o documento sem interpretá-lo.
+type rawJSONCodec struct {
+	pgtype.Codec
+}
+
+func (c rawJSONCodec) DecodeValue(m *pgtype.Map, oid uint32, format int16, src []byte) (any, error) {
+	if src == nil {
+		return nil, nil
+	}
+	raw, err := c.DecodeDatabaseSQLValue(m, oid, format, src)
+	if err != nil {
+		return nil, err
+	}
+	b, ok := raw.([]byte)
+	if !ok {
+		return nil, fmt.Errorf("unexpected json value %T", raw)
+	}
+	return json.RawMessage(b), nil
+}
+
+// normalizeValue converte os valores do pgx em formas JSON sem perda: decimais como string,
+// intervalos em ISO-8601, json como documento nativo, arrays elemento a elemento e ranges
+// como objeto {lower, upper, lowerInclusive, upperInclusive}.
+func normalizeValue(v any) any {
+	switch val := v.(type) {
+	case [16]byte:
+		return formatUUIDBytes(val[:])
+	case json.RawMessage:
+		return val
+	case []byte:
+		if len(val) == 16 {
+			return formatUUIDBytes(val)
+		}
+		return string(val)
+	case time.Time:
+		return val.UTC().Format(time.RFC3339Nano)
+	case pgtype.InfinityModifier:
+		return val.String()
+	case float64:
+		return normalizeFloat(val)
+	case float32:
+		return normalizeFloat(float64(val))
+	case pgtype.Numeric:
+		return formatNumeric(val)
+	case pgtype.Interval:
+		if !val.Valid {
+			return nil
+		}
+		return formatInterval(val)
+	case pgtype.Time:
+		if !val.Valid {
+			return nil
+		}
+		return formatTime(val.Microseconds)
+	case netip.Prefix:
+		return formatPrefix(val)
+	case net.HardwareAddr:
+		return val.String()
+	case pgtype.Bits:
+		if !val.Valid {
+			return nil
+		}
+		return formatBits(val)
+	case pgtype.Range[any]:
+		return formatRange(val)
+	case pgtype.Multirange[pgtype.Range[any]]:
+		out := make([]any, len(val))
+		for i, r := range val {
+			out[i] = formatRange(r)
+		}
+		return out
+	case []any:
+		out := make([]any, len(val))
+		for i, item := range val {
+			out[i] = normalizeValue(item)
+		}
+		return out
+	default:
+		return v
+	}
+}
+
+// normalizeFloat troca NaN e infinitos (que encoding/json rejeita) pela grafia do Postgres.
+func normalizeFloat(f float64) any {
+	switch {
+	case math.IsNaN(f):
+		return "NaN"
+	case math.IsInf(f, 1):
+		return "Infinity"
+	case math.IsInf(f, -1):
+		return "-Infinity"
+	default:
+		return f
+	}
+}
+
+// formatNumeric usa a representação textual do Postgres, sem passar por float64.
+func formatNumeric(n pgtype.Numeric) any {
+	v, err := n.Value()
+	if err != nil || v == nil {
+		return nil
+	}
+	return v
+}
+
+// formatInterval gera a duração ISO-8601 (PnYnMnDTnHnMnS). Como no intervalstyle
+// iso_8601 do Postgres, cada componente mantém o próprio sinal (ex.: P1Y-2M).
+func formatInterval(iv pgtype.Interval) string {
+	var b strings.Builder
+	b.WriteByte('P')
+	if years := iv.Months / 12; years != 0 {
+		fmt.Fprintf(&b, "%dY", years)
+	}
+	if months := iv.Months % 12; months != 0 {
+		fmt.Fprintf(&b, "%dM", months)
+	}
+	if iv.Days != 0 {
+		fmt.Fprintf(&b, "%dD", iv.Days)
+	}
+
+	us := iv.Microseconds
+	hours := us / int64(time.Hour/time.Microsecond)
+	us -= hours * int64(time.Hour/time.Microsecond)
+	minutes := us / int64(time.Minute/time.Microsecond)
+	us -= minutes * int64(time.Minute/time.Microsecond)
+	if hours != 0 || minutes != 0 || us != 0 {
+		b.WriteByte('T')
+		if hours != 0 {
+			fmt.Fprintf(&b, "%dH", hours)
+		}
+		if minutes != 0 {
+			fmt.Fprintf(&b, "%dM", minutes)
+		}
+		if us != 0 {
+			b.WriteString(formatSeconds(us))
+			b.WriteByte('S')
+		}
+	}
+	if b.Len() == 1 {
+		return "PT0S"
+	}
+	return b.String()
+}
+
+// formatSeconds escreve microssegundos como segundos com fração mínima (ex.: 6.5).
+func formatSeconds(us int64) string {
+	sign := ""
+	if us < 0 {
+		sign = "-"
+		us = -us
+	}
+	s := strconv.FormatInt(us/1e6, 10)
+	if frac := us % 1e6; frac != 0 {
+		s += "." + strings.TrimRight(fmt.Sprintf("%06d", frac), "0")
+	}
+	return sign + s
+}
+
+// formatTime escreve time (sem fuso) como HH:MM:SS[.ffffff]; o Postgres aceita 24:00:00.
+func formatTime(us int64) string {
+	hours := us / int64(time.Hour/time.Microsecond)
+	us -= hours * int64(time.Hour/time.Microsecond)
+	minutes := us / int64(time.Minute/time.Microsecond)
+	us -= minutes * int64(time.Minute/time.Microsecond)
+	s := fmt.Sprintf("%02d:%02d:%02d", hours, minutes, us/1e6)
+	if frac := us % 1e6; frac != 0 {
+		s += "." + strings.TrimRight(fmt.Sprintf("%06d", frac), "0")
+	}
+	return s
+}
+
+// formatPrefix segue a saída do Postgres para inet: a máscara é omitida quando cobre o
+// endereço inteiro (192.168.0.1 em vez de 192.168.0.1/32).
+func formatPrefix(p netip.Prefix) string {
+	if p.Bits() == p.Addr().BitLen() {
+		return p.Addr().String()
+	}
+	return p.String()
+}
+
+func formatBits(b pgtype.Bits) string {
+	var s strings.Builder
+	for i := int32(0); i < b.Len; i++ {
+		if b.Bytes[i/8]&(0x80>>(i%8)) != 0 {
+			s.WriteByte('1')
+		} else {
+			s.WriteByte('0')
+		}
+	}
+	return s.String()
+}
+
+// formatRange representa limites ausentes (infinitos) como null; ranges vazios como {"empty": true}.
+func formatRange(r pgtype.Range[any]) any {
+	if !r.Valid {
+		return nil
+	}
+	if r.LowerType == pgtype.Empty {
+		return map[string]any{"empty": true}
+	}
+	out := map[string]any{
+		"lower":          nil,
+		"upper":          nil,
+		"lowerInclusive": r.LowerType == pgtype.Inclusive,
+		"upperInclusive": r.UpperType == pgtype.Inclusive,
+	}
+	if r.LowerType != pgtype.Unbounded {
+		out["lower"] = normalizeValue(r.Lower)
+	}
+	if r.UpperType != pgtype.Unbounded {
+		out["upper"] = normalizeValue(r.Upper)
+	}
+	return out
+}
+
+// formatUUIDBytes converte 16 bytes em string UUID canonical (8-4-4-4-12).
+func formatUUIDBytes(b []byte) string {
+	if len(b) != 16 {
+		return string(b)
+	}
+	hexStr := hex.EncodeToString(b)
+	return fmt.Sprintf("%s-%s-%s-%s-%s", hexStr[0:8], hexStr[8:12], hexStr[12:16], hexStr[16:20], hexStr[20:32])
+}
@@ -0,0 +1,94 @@
+package postgres
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestNormalizeValue decodifica o formato texto de cada tipo com o mapa de tipos das conexões
+// e confere o JSON final da resposta.
+func TestNormalizeValue(t *testing.T) {
+	m := pgtype.NewMap()
+	registerTypes(m)
+
+	cases := []struct {
+		name string
+		oid  uint32
+		text string
+		want string
+	}{
+		{"bool", pgtype.BoolOID, "t", `true`},
+		{"int8", pgtype.Int8OID, "9007199254740993", `9007199254740993`},
+		{"float8", pgtype.Float8OID, "1.5", `1.5`},
+		{"float8 NaN", pgtype.Float8OID, "NaN", `"NaN"`},
+		{"float8 infinity", pgtype.Float8OID, "-Infinity", `"-Infinity"`},
+		{"numeric", pgtype.NumericOID, "12345678901234567890.0123456789", `"12345678901234567890.0123456789"`},
+		{"numeric scale", pgtype.NumericOID, "1234.50", `"1234.50"`},
+		{"numeric NaN", pgtype.NumericOID, "NaN", `"NaN"`},
+		{"text", pgtype.TextOID, "olá", `"olá"`},
+		{"uuid", pgtype.UUIDOID, "67fb3c8f-2f6b-4b2e-b5f6-3e9c9efc1c89", `"67fb3c8f-2f6b-4b2e-b5f6-3e9c9efc1c89"`},
+		{"timestamptz", pgtype.TimestamptzOID, "2025-03-01 12:00:00.5+00", `"2025-03-01T12:00:00.5Z"`},
+		{"timestamptz infinity", pgtype.TimestamptzOID, "infinity", `"infinity"`},
+		{"date", pgtype.DateOID, "2025-03-01", `"2025-03-01T00:00:00Z"`},
+		{"time", pgtype.TimeOID, "13:45:06.25", `"13:45:06.25"`},
+		{"interval", pgtype.IntervalOID, "1 year 2 mons 3 days 04:05:06.5", `"P1Y2M3DT4H5M6.5S"`},
+		{"interval negative", pgtype.IntervalOID, "-1 days -00:30:00", `"P-1DT-30M"`},
+		{"interval zero", pgtype.IntervalOID, "00:00:00", `"PT0S"`},
+		{"json", pgtype.JSONOID, `{"b": 1, "a": 12345678901234567890}`, `{"b":1,"a":12345678901234567890}`},
+		{"jsonb", pgtype.JSONBOID, `{"a": [1, 2.50]}`, `{"a":[1,2.50]}`},
+		{"jsonb array", pgtype.JSONBArrayOID, `{"{\"a\": 1}","[2]"}`, `[{"a": 1},[2]]`},
+		{"int4 array", pgtype.Int4ArrayOID, "{1,2,NULL}", `[1,2,null]`},
+		{"numeric array", pgtype.NumericArrayOID, "{1.10,2}", `["1.10","2"]`},
+		{"uuid array", pgtype.UUIDArrayOID, "{67fb3c8f-2f6b-4b2e-b5f6-3e9c9efc1c89}", `["67fb3c8f-2f6b-4b2e-b5f6-3e9c9efc1c89"]`},
+		{"text array", pgtype.TextArrayOID, `{a,"b c"}`, `["a","b c"]`},
+		{"int4range", pgtype.Int4rangeOID, "[1,10)", `{"lower":1,"lowerInclusive":true,"upper":10,"upperInclusive":false}`},
+		{"numrange unbounded", pgtype.NumrangeOID, "(1.5,)", `{"lower":"1.5","lowerInclusive":false,"upper":null,"upperInclusive":false}`},
+		{"tstzrange", pgtype.TstzrangeOID, `["2025-03-01 00:00:00+00","2025-04-01 00:00:00+00")`, `{"lower":"2025-03-01T00:00:00Z","lowerInclusive":true,"upper":"2025-04-01T00:00:00Z","upperInclusive":false}`},
+		{"empty range", pgtype.Int4rangeOID, "empty", `{"empty":true}`},
+		{"int4multirange", pgtype.Int4multirangeOID, "{[1,3),[5,7)}", `[{"lower":1,"lowerInclusive":true,"upper":3,"upperInclusive":false},{"lower":5,"lowerInclusive":true,"upper":7,"upperInclusive":false}]`},
+		{"inet host", pgtype.InetOID, "192.168.0.1", `"192.168.0.1"`},
+		{"inet network", pgtype.InetOID, "192.168.0.1/24", `"192.168.0.1/24"`},
+		{"inet ipv6", pgtype.InetOID, "::1", `"::1"`},
+		{"cidr", pgtype.CIDROID, "10.0.0.0/8", `"10.0.0.0/8"`},
+		{"macaddr", pgtype.MacaddrOID, "08:00:2b:01:02:03", `"08:00:2b:01:02:03"`},
+		{"varbit", pgtype.VarbitOID, "10110", `"10110"`},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			typ, ok := m.TypeForOID(tc.oid)
+			require.True(t, ok)
+			v, err := typ.Codec.DecodeValue(m, tc.oid, pgtype.TextFormatCode, []byte(tc.text))
+			require.NoError(t, err)
+			got, err := json.Marshal(normalizeValue(v))
+			require.NoError(t, err)
+			assert.JSONEq(t, tc.want, string(got))
+			if tc.oid == pgtype.JSONOID || tc.oid == pgtype.JSONBOID {
+				// Documento preservado como veio do banco (números grandes e ordem das chaves).
+				assert.Equal(t, tc.want, string(got))
+			}
+		})
+	}
+}
+
+func TestNormalizeValue_Binary(t *testing.T) {
+	m := pgtype.NewMap()
+	registerTypes(m)
+
+	typ, ok := m.TypeForOID(pgtype.JSONBOID)
+	require.True(t, ok)
+	v, err := typ.Codec.DecodeValue(m, pgtype.JSONBOID, pgtype.BinaryFormatCode, append([]byte{1}, `{"a":1}`...))
+	require.NoError(t, err)
+	assert.Equal(t, json.RawMessage(`{"a":1}`), normalizeValue(v))
+
+	assert.Nil(t, normalizeValue(nil))
+	assert.Nil(t, normalizeValue(pgtype.Numeric{}))
+	assert.Equal(t, "Infinity", normalizeValue(float32(math.Inf(1))))
+	assert.Equal(t, "2025-03-01T15:00:00Z", normalizeValue(time.Date(2025, 3, 1, 12, 0, 0, 0, time.FixedZone("", -3*3600))))
+	assert.Equal(t, "67fb3c8f-2f6b-4b2e-b5f6-3e9c9efc1c89", normalizeValue([16]byte{0x67, 0xfb, 0x3c, 0x8f, 0x2f, 0x6b, 0x4b, 0x2e, 0xb5, 0xf6, 0x3e, 0x9c, 0x9e, 0xfc, 0x1c, 0x89}))
+}
@@ -72,8 +72,9 @@ func (c *Client) Publish(ctx context.Context, payload any) error {
 	)
 }
 
-// Consume starts consuming messages and passes each to handler.
-func (c *Client) Consume(ctx context.Context, handler func(context.Context, []byte) error) error {
+// Consume starts consuming messages and passes each to handler, bounded by timeout
+// (zero disables the per-message deadline).
+func (c *Client) Consume(ctx context.Context, timeout time.Duration, handler func(context.Context, []byte) error) error {
 	deliveries, err := c.ch.Consume(
 		c.queue,
 		"",
@@ -96,7 +97,10 @@ func (c *Client) Consume(ctx context.Context, handler func(context.Context, []by
 				if !ok {
 					return
 				}
-				msgCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+				msgCtx, cancel := ctx, context.CancelFunc(func() {})
+				if timeout > 0 {
+					msgCtx, cancel = context.WithTimeout(ctx, timeout)
+				}
 				if err := handler(msgCtx, d.Body); err != nil {
 					_ = d.Nack(false, true)
 				} else {
@@ -0,0 +1,180 @@
+package sqlbuilder
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"api-database/internal/domain/schema"
+)
+
+// Catalog fornece as consultas de catálogo de um dialeto. Cada consulta recebe schema
+// e tabela e devolve a instrução com seus parâmetros.
+type Catalog struct {
+	// Tables deve retornar table_name de cada tabela/view do schema, em ordem alfabética.
+	Tables func(schemaName string) (string, []any)
+	// Columns deve retornar column_name, data_type e is_nullable (YES/NO) na ordem da tabela.
+	Columns func(schemaName, table string) (string, []any)
+	// PrimaryKey deve retornar column_name das colunas da chave primária, em ordem.
+	PrimaryKey func(schemaName, table string) (string, []any)
+	// Indexes deve retornar uma linha por coluna indexada, ordenada por índice e posição,
+	// com index_name, column_name e is_unique.
+	Indexes func(schemaName, table string) (string, []any)
+	// ForeignKeys deve retornar uma linha por coluna de FK, ordenada por constraint e
+	// posição, com name, table_name, column_name, ref_table e ref_column.
+	ForeignKeys func(schemaName, table string) (string, []any)
+}
+
+// Inspector implementa schema.Inspector a partir de um Catalog.
+type Inspector struct {
+	catalog Catalog
+}
+
+func NewInspector(catalog Catalog) *Inspector {
+	return &Inspector{catalog: catalog}
+}
+
+// Tables lista os nomes de tabela do schema.
+func (i *Inspector) Tables(ctx context.Context, q schema.Querier, schemaName string) ([]string, error) {
+	stmt, args := i.catalog.Tables(schemaName)
+	rows, err := q.Query(ctx, stmt, args...)
+	if err != nil {
+		return nil, err
+	}
+	tables := make([]string, 0, len(rows))
+	for _, row := range rows {
+		if name := text(row["table_name"]); name != "" {
+			tables = append(tables, name)
+		}
+	}
+	return tables, nil
+}
+
+// Describe combina colunas, chave primária, chaves estrangeiras e índices da tabela.
+func (i *Inspector) Describe(ctx context.Context, q schema.Querier, schemaName, table string) (*schema.Table, error) {
+	stmt, args := i.catalog.Columns(schemaName, table)
+	rows, err := q.Query(ctx, stmt, args...)
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, nil
+	}
+
+	desc := &schema.Table{
+		Name:         table,
+		Columns:      make([]schema.Column, 0, len(rows)),
+		PrimaryKey:   []string{},
+		ForeignKeys:  []schema.ForeignKey{},
+		ReferencedBy: []schema.ForeignKey{},
+		Indexes:      []schema.Index{},
+	}
+	for _, row := range rows {
+		desc.Columns = append(desc.Columns, schema.Column{
+			Name:     text(row["column_name"]),
+			Type:     text(row["data_type"]),
+			Nullable: strings.EqualFold(text(row["is_nullable"]), "YES"),
+		})
+	}
+
+	stmt, args = i.catalog.PrimaryKey(schemaName, table)
+	if rows, err = q.Query(ctx, stmt, args...); err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		desc.PrimaryKey = append(desc.PrimaryKey, text(row["column_name"]))
+	}
+
+	keys, err := i.ForeignKeys(ctx, q, schemaName, table)
+	if err != nil {
+		return nil, err
+	}
+	for _, fk := range keys {
+		// Autorreferência aparece nas duas listas.
+		if fk.Table == table {
+			desc.ForeignKeys = append(desc.ForeignKeys, fk)
+		}
+		if fk.RefTable == table {
+			desc.ReferencedBy = append(desc.ReferencedBy, fk)
+		}
+	}
+
+	stmt, args = i.catalog.Indexes(schemaName, table)
+	if rows, err = q.Query(ctx, stmt, args...); err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		name, column := text(row["index_name"]), text(row["column_name"])
+		if column == "" {
+			continue // índices sobre expressões
+		}
+		if n := len(desc.Indexes); n > 0 && desc.Indexes[n-1].Name == name {
+			desc.Indexes[n-1].Columns = append(desc.Indexes[n-1].Columns, column)
+			continue
+		}
+		desc.Indexes = append(desc.Indexes, schema.Index{Name: name, Columns: []string{column}, Unique: truthy(row["is_unique"])})
+	}
+	return desc, nil
+}
+
+// ForeignKeys agrupa as linhas do catálogo em chaves (compostas ou não).
+func (i *Inspector) ForeignKeys(ctx context.Context, q schema.Querier, schemaName, table string) ([]schema.ForeignKey, error) {
+	stmt, args := i.catalog.ForeignKeys(schemaName, table)
+	rows, err := q.Query(ctx, stmt, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	var keys []schema.ForeignKey
+	for _, row := range rows {
+		name, source := text(row["name"]), text(row["table_name"])
+		column, refTable, refColumn := text(row["column_name"]), text(row["ref_table"]), text(row["ref_column"])
+		if column == "" || refTable == "" || refColumn == "" {
+			continue
+		}
+		if n := len(keys); n > 0 && keys[n-1].Name == name && keys[n-1].Table == source {
+			keys[n-1].Columns = append(keys[n-1].Columns, column)
+			keys[n-1].RefColumns = append(keys[n-1].RefColumns, refColumn)
+			continue
+		}
+		keys = append(keys, schema.ForeignKey{
+			Name:       name,
+			Table:      source,
+			Columns:    []string{column},
+			RefTable:   refTable,
+			RefColumns: []string{refColumn},
+		})
+	}
+	return keys, nil
+}
+
+// text lê valores de catálogo, que alguns drivers devolvem como []byte ou números.
+func text(v any) string {
+	switch val := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return val
+	case []byte:
+		return string(val)
+	default:
+		return fmt.Sprint(val)
+	}
+}
+
+// truthy interpreta booleanos de catálogo (bool, 0/1 ou texto, conforme o driver).
+func truthy(v any) bool {
+	switch val := v.(type) {
+	case bool:
+		return val
+	case int64:
+		return val != 0
+	case int32:
+		return val != 0
+	case int:
+		return val != 0
+	default:
+		s := strings.ToLower(text(v))
+		return s == "1" || s == "t" || s == "true" || s == "yes"
+	}
+}
@@ -0,0 +1,420 @@
+package sqlbuilder
+
+import (
+	"fmt"
+	"strings"
+
+	"api-database/internal/domain/query"
+)
+
+// Dialect isola as diferenças de sintaxe entre bancos SQL.
+type Dialect interface {
+	// QuoteIdent escapa um identificador (tabela, coluna, schema).
+	QuoteIdent(name string) string
+	// Placeholder retorna o marcador do n-ésimo parâmetro (1-based).
+	Placeholder(n int) string
+}
+
+// ILiker é implementado por dialetos com LIKE case-insensitive nativo (ex.: ILIKE no
+// PostgreSQL). Sem ele, $ilike é gerado como LOWER(col) LIKE LOWER(?).
+type ILiker interface {
+	ILike(column, placeholder string) string
+}
+
+// ArrayBinder é implementado por dialetos que comparam com uma lista em um único parâmetro
+// (PostgreSQL: col = ANY($1)). Sem ele, $in/$nin geram um placeholder por valor.
+type ArrayBinder interface {
+	// ArrayValue converte a lista no valor do parâmetro.
+	ArrayValue(values []any) (any, error)
+	// AnyOf compara a coluna com o array; negate gera a forma de $nin.
+	AnyOf(column, placeholder string, negate bool) string
+}
+
+// Returner é implementado por dialetos com RETURNING (PostgreSQL, SQLite). Sem ele, as
+// escritas retornam query.ErrUnsupported.
+type Returner interface {
+	Returning(columns string) string
+}
+
+// Translator gera SQL parametrizado para um Dialect.
+type Translator struct {
+	dialect Dialect
+}
+
+func NewTranslator(dialect Dialect) *Translator {
+	return &Translator{dialect: dialect}
+}
+
+// Select monta SELECT ... FROM ... WHERE ... [GROUP BY ... HAVING ...] ORDER BY ... LIMIT ... OFFSET.
+func (t *Translator) Select(spec query.Select) (query.Statement, error) {
+	b := &builder{dialect: t.dialect}
+	list, err := t.selectList(spec)
+	if err != nil {
+		return query.Statement{}, err
+	}
+	from, err := t.from(b, spec, true)
+	if err != nil {
+		return query.Statement{}, err
+	}
+
+	parts := append([]string{"SELECT", list}, from...)
+	if order := t.orderBy(spec.OrderBy); order != "" {
+		parts = append(parts, "ORDER BY", order)
+	}
+	parts = append(parts, "LIMIT", b.bind(spec.Limit), "OFFSET", b.bind(spec.Offset))
+
+	return query.Statement{Text: strings.Join(parts, " "), Args: b.args}, nil
+}
+
+// Count monta SELECT COUNT(1) AS total com o mesmo filtro do Select. Em agregações,
+// conta os grupos resultantes.
+func (t *Translator) Count(spec query.Select) (query.Statement, error) {
+	b := &builder{dialect: t.dialect}
+	from, err := t.from(b, spec, false)
+	if err != nil {
+		return query.Statement{}, err
+	}
+
+	if spec.Aggregated() {
+		list, err := t.selectList(spec)
+		if err != nil {
+			return query.Statement{}, err
+		}
+		inner := strings.Join(append([]string{"SELECT", list}, from...), " ")
+		return query.Statement{Text: "SELECT COUNT(1) AS total FROM (" + inner + ") AS grouped", Args: b.args}, nil
+	}
+	parts := append([]string{"SELECT COUNT(1) AS total"}, from...)
+	return query.Statement{Text: strings.Join(parts, " "), Args: b.args}, nil
+}
+
+// from monta FROM ... WHERE ... GROUP BY ... HAVING, compartilhado por Select e Count.
+func (t *Translator) from(b *builder, spec query.Select, withSeek bool) ([]string, error) {
+	where, err := b.where(spec.Where)
+	if err != nil {
+		return nil, err
+	}
+	if withSeek {
+		seek, err := t.seek(b, spec)
+		if err != nil {
+			return nil, err
+		}
+		if seek != "" {
+			if where != "" {
+				where += " AND "
+			}
+			where += seek
+		}
+	}
+
+	parts := []string{"FROM", t.tableRef(spec)}
+	if where != "" {
+		parts = append(parts, "WHERE", where)
+	}
+	if len(spec.GroupBy) > 0 {
+		parts = append(parts, "GROUP BY", t.columnList(spec.GroupBy))
+	}
+	if spec.Having != nil {
+		// HAVING referencia aliases; nem todo banco aceita alias ali, então usa a expressão.
+		aliases := make(map[string]string, len(spec.Aggregates))
+		for _, a := range spec.Aggregates {
+			expr, err := t.aggregateExpr(a)
+			if err != nil {
+				return nil, err
+			}
+			aliases[a.Alias] = expr
+		}
+		b.aliases = aliases
+		having, err := b.where(spec.Having)
+		b.aliases = nil
+		if err != nil {
+			return nil, err
+		}
+		if having != "" {
+			parts = append(parts, "HAVING", having)
+		}
+	}
+	return parts, nil
+}
+
+// seek gera o predicado da paginação keyset. Com todas as colunas na mesma direção usa
+// comparação de tuplas, (a, b) > ($1, $2); com direções mistas ou colunas anuláveis expande
+// em a > $1 OR (a = $2 AND b < $3).
+func (t *Translator) seek(b *builder, spec query.Select) (string, error) {
+	if len(spec.After) == 0 {
+		return "", nil
+	}
+	if len(spec.After) != len(spec.OrderBy) {
+		return "", fmt.Errorf("seek values do not match order by columns")
+	}
+
+	cmp := func(o query.Order) string {
+		if o.Desc {
+			return "<"
+		}
+		return ">"
+	}
+	sameDirection, nullable := true, false
+	for i, o := range spec.OrderBy {
+		sameDirection = sameDirection && o.Desc == spec.OrderBy[0].Desc
+		nullable = nullable || o.Nullable || spec.After[i] == nil
+	}
+	if nullable {
+		return t.nullableSeek(b, spec), nil
+	}
+	if sameDirection {
+		cols := make([]string, len(spec.OrderBy))
+		phs := make([]string, len(spec.OrderBy))
+		for i, o := range spec.OrderBy {
+			cols[i] = t.dialect.QuoteIdent(o.Column)
+			phs[i] = b.bind(spec.After[i])
+		}
+		if len(cols) == 1 {
+			return fmt.Sprintf("%s %s %s", cols[0], cmp(spec.OrderBy[0]), phs[0]), nil
+		}
+		return fmt.Sprintf("(%s) %s (%s)", strings.Join(cols, ", "), cmp(spec.OrderBy[0]), strings.Join(phs, ", ")), nil
+	}
+
+	branches := make([]string, len(spec.OrderBy))
+	for i, o := range spec.OrderBy {
+		terms := make([]string, 0, i+1)
+		for j := 0; j < i; j++ {
+			terms = append(terms, fmt.Sprintf("%s = %s", t.dialect.QuoteIdent(spec.OrderBy[j].Column), b.bind(spec.After[j])))
+		}
+		terms = append(terms, fmt.Sprintf("%s %s %s", t.dialect.QuoteIdent(o.Column), cmp(o), b.bind(spec.After[i])))
+		branches[i] = "(" + strings.Join(terms, " AND ") + ")"
+	}
+	return "(" + strings.Join(branches, " OR ") + ")", nil
+}
+
+// nullableSeek expande o predicado keyset tratando NULL como o maior valor (a mesma regra
+// do ORDER BY): depois de um valor em ASC vêm os maiores e os nulos; em DESC, os menores; e
+// depois de um NULL, em DESC, todos os não nulos.
+func (t *Translator) nullableSeek(b *builder, spec query.Select) string {
+	var branches []string
+	for i, o := range spec.OrderBy {
+		terms := make([]string, 0, i+1)
+		for j := 0; j < i; j++ {
+			col := t.dialect.QuoteIdent(spec.OrderBy[j].Column)
+			if spec.After[j] == nil {
+				terms = append(terms, col+" IS NULL")
+			} else {
+				terms = append(terms, fmt.Sprintf("%s = %s", col, b.bind(spec.After[j])))
+			}
+		}
+		col := t.dialect.QuoteIdent(o.Column)
+		switch {
+		case spec.After[i] == nil && o.Desc:
+			terms = append(terms, col+" IS NOT NULL")
+		case spec.After[i] == nil:
+			continue // nada vem depois de NULL em ASC
+		case o.Desc:
+			terms = append(terms, fmt.Sprintf("%s < %s", col, b.bind(spec.After[i])))
+		case o.Nullable:
+			terms = append(terms, fmt.Sprintf("(%s > %s OR %s IS NULL)", col, b.bind(spec.After[i]), col))
+		default:
+			terms = append(terms, fmt.Sprintf("%s > %s", col, b.bind(spec.After[i])))
+		}
+		branches = append(branches, "("+strings.Join(terms, " AND ")+")")
+	}
+	if len(branches) == 0 {
+		return "1 = 0"
+	}
+	return "(" + strings.Join(branches, " OR ") + ")"
+}
+
+func (t *Translator) tableRef(spec query.Select) string {
+	return t.qualified(spec.Schema, spec.Table)
+}
+
+func (t *Translator) qualified(schema, table string) string {
+	if schema != "" {
+		return t.dialect.QuoteIdent(schema) + "." + t.dialect.QuoteIdent(table)
+	}
+	return t.dialect.QuoteIdent(table)
+}
+
+func (t *Translator) selectList(spec query.Select) (string, error) {
+	if !spec.Aggregated() {
+		if len(spec.Columns) == 0 {
+			return "*", nil
+		}
+		return t.columnList(spec.Columns), nil
+	}
+	items := make([]string, 0, len(spec.GroupBy)+len(spec.Aggregates))
+	for _, c := range spec.GroupBy {
+		items = append(items, t.dialect.QuoteIdent(c))
+	}
+	for _, a := range spec.Aggregates {
+		expr, err := t.aggregateExpr(a)
+		if err != nil {
+			return "", err
+		}
+		items = append(items, expr+" AS "+t.dialect.QuoteIdent(a.Alias))
+	}
+	return strings.Join(items, ", "), nil
+}
+
+func (t *Translator) columnList(columns []string) string {
+	cols := make([]string, len(columns))
+	for i, c := range columns {
+		cols[i] = t.dialect.QuoteIdent(c)
+	}
+	return strings.Join(cols, ", ")
+}
+
+var aggregateFuncs = map[query.AggregateFunc]string{
+	query.AggCount: "COUNT",
+	query.AggSum:   "SUM",
+	query.AggAvg:   "AVG",
+	query.AggMin:   "MIN",
+	query.AggMax:   "MAX",
+}
+
+func (t *Translator) aggregateExpr(a query.Aggregate) (string, error) {
+	fn, ok := aggregateFuncs[a.Func]
+	if !ok {
+		return "", fmt.Errorf("unsupported aggregate function: %s", a.Func)
+	}
+	if a.Column == "" {
+		if a.Func != query.AggCount {
+			return "", fmt.Errorf("aggregate %s requires a column", a.Func)
+		}
+		return fn + "(*)", nil
+	}
+	return fn + "(" + t.dialect.QuoteIdent(a.Column) + ")", nil
+}
+
+func (t *Translator) orderBy(order []query.Order) string {
+	clauses := make([]string, 0, len(order))
+	for _, o := range order {
+		dir := "ASC"
+		if o.Desc {
+			dir = "DESC"
+		}
+		if o.Nullable {
+			// NULLS LAST/FIRST não existe no MySQL; (col IS NULL) fixa a posição dos nulos.
+			clauses = append(clauses, fmt.Sprintf("(%s IS NULL) %s", t.dialect.QuoteIdent(o.Column), dir))
+		}
+		clauses = append(clauses, fmt.Sprintf("%s %s", t.dialect.QuoteIdent(o.Column), dir))
+	}
+	return strings.Join(clauses, ", ")
+}
+
+// builder acumula parâmetros durante a geração de uma instrução.
+type builder struct {
+	dialect Dialect
+	args    []any
+	aliases map[string]string // alias → expressão de agregado (apenas no HAVING)
+}
+
+// column resolve o operando de uma condição: alias de agregado ou coluna.
+func (b *builder) column(name string) string {
+	if expr, ok := b.aliases[name]; ok {
+		return expr
+	}
+	return b.dialect.QuoteIdent(name)
+}
+
+func (b *builder) bind(v any) string {
+	b.args = append(b.args, v)
+	return b.dialect.Placeholder(len(b.args))
+}
+
+func (b *builder) where(f *query.Filter) (string, error) {
+	if f == nil {
+		return "", nil
+	}
+	return b.filter(*f)
+}
+
+// group junta as condições com sep; subgrupos AND/OR com mais de uma condição ficam
+// entre parênteses para preservar a precedência.
+func (b *builder) group(children []query.Filter, sep string) (string, error) {
+	clauses := make([]string, 0, len(children))
+	for _, child := range children {
+		clause, err := b.filter(child)
+		if err != nil {
+			return "", err
+		}
+		if clause == "" {
+			continue
+		}
+		if (child.Op == query.OpAnd || child.Op == query.OpOr) && len(child.Children) > 1 {
+			clause = "(" + clause + ")"
+		}
+		clauses = append(clauses, clause)
+	}
+	return strings.Join(clauses, sep), nil
+}
+
+var comparisonOps = map[query.Operator]string{
+	query.OpEq:   "=",
+	query.OpNe:   "<>",
+	query.OpLike: "LIKE",
+	query.OpGt:   ">",
+	query.OpGte:  ">=",
+	query.OpLt:   "<",
+	query.OpLte:  "<=",
+}
+
+func (b *builder) filter(f query.Filter) (string, error) {
+	switch f.Op {
+	case query.OpAnd:
+		return b.group(f.Children, " AND ")
+	case query.OpOr:
+		return b.group(f.Children, " OR ")
+	case query.OpNot:
+		clause, err := b.group(f.Children, " AND ")
+		if err != nil || clause == "" {
+			return clause, err
+		}
+		return "NOT (" + clause + ")", nil
+	}
+
+	col := b.column(f.Column)
+	switch f.Op {
+	case query.OpIn, query.OpNin:
+		list, ok := f.Value.([]any)
+		if !ok || len(list) == 0 {
+			return "", fmt.Errorf("operator %s requires a non-empty list", f.Op)
+		}
+		if binder, ok := b.dialect.(ArrayBinder); ok {
+			array, err := binder.ArrayValue(list)
+			if err != nil {
+				return "", fmt.Errorf("operator %s: %w", f.Op, err)
+			}
+			return binder.AnyOf(col, b.bind(array), f.Op == query.OpNin), nil
+		}
+		placeholders := make([]string, len(list))
+		for i, v := range list {
+			placeholders[i] = b.bind(v)
+		}
+		keyword := "IN"
+		if f.Op == query.OpNin {
+			keyword = "NOT IN"
+		}
+		return fmt.Sprintf("%s %s (%s)", col, keyword, strings.Join(placeholders, ", ")), nil
+	case query.OpBetween:
+		bounds, ok := f.Value.([]any)
+		if !ok || len(bounds) != 2 {
+			return "", fmt.Errorf("operator %s requires [min, max]", f.Op)
+		}
+		return fmt.Sprintf("%s BETWEEN %s AND %s", col, b.bind(bounds[0]), b.bind(bounds[1])), nil
+	case query.OpIsNull:
+		if isNull, _ := f.Value.(bool); isNull {
+			return col + " IS NULL", nil
+		}
+		return col + " IS NOT NULL", nil
+	case query.OpILike:
+		if liker, ok := b.dialect.(ILiker); ok {
+			return liker.ILike(col, b.bind(f.Value)), nil
+		}
+		return fmt.Sprintf("LOWER(%s) LIKE LOWER(%s)", col, b.bind(f.Value)), nil
+	}
+
+	sqlOp, ok := comparisonOps[f.Op]
+	if !ok {
+		return "", fmt.Errorf("unsupported operator: %s", f.Op)
+	}
+	return fmt.Sprintf("%s %s %s", col, sqlOp, b.bind(f.Value)), nil
+}
@@ -0,0 +1,188 @@
+package sqlbuilder
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"api-database/internal/domain/query"
+)
+
+// testDialect usa aspas duplas e $n, como o PostgreSQL.
+type testDialect struct{}
+
+func (testDialect) QuoteIdent(name string) string { return `"` + name + `"` }
+func (testDialect) Placeholder(n int) string      { return "$" + strconv.Itoa(n) }
+
+func TestTranslatorSelect(t *testing.T) {
+	tr := NewTranslator(testDialect{})
+	stmt, err := tr.Select(query.Select{
+		Schema:  "public",
+		Table:   "User",
+		Columns: []string{"id", "email"},
+		Where: query.And(
+			query.Filter{Op: query.OpEq, Column: "role", Value: "PILOT"},
+			query.Filter{Op: query.OpGte, Column: "createdAt", Value: "2025-01-01"},
+		),
+		OrderBy: []query.Order{{Column: "createdAt", Desc: true}},
+		Limit:   10,
+		Offset:  20,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, `SELECT "id", "email" FROM "public"."User" WHERE "role" = $1 AND "createdAt" >= $2 ORDER BY "createdAt" DESC LIMIT $3 OFFSET $4`, stmt.Text)
+	assert.Equal(t, []any{"PILOT", "2025-01-01", 10, 20}, stmt.Args)
+}
+
+func TestTranslatorSelect_NoFilter(t *testing.T) {
+	tr := NewTranslator(testDialect{})
+	stmt, err := tr.Select(query.Select{Table: "User", Limit: 100})
+	require.NoError(t, err)
+	assert.Equal(t, `SELECT * FROM "User" LIMIT $1 OFFSET $2`, stmt.Text)
+}
+
+func TestTranslatorCount(t *testing.T) {
+	tr := NewTranslator(testDialect{})
+	stmt, err := tr.Count(query.Select{
+		Table: "User",
+		Where: query.And(query.Filter{Op: query.OpLt, Column: "age", Value: 30}),
+		Limit: 10,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, `SELECT COUNT(1) AS total FROM "User" WHERE "age" < $1`, stmt.Text)
+	assert.Equal(t, []any{30}, stmt.Args)
+}
+
+func TestTranslatorUnsupportedOperator(t *testing.T) {
+	tr := NewTranslator(testDialect{})
+	_, err := tr.Select(query.Select{
+		Table: "User",
+		Where: query.And(query.Filter{Op: "regex", Column: "name", Value: "x"}),
+	})
+	assert.Error(t, err)
+}
+
+func TestTranslatorSelect_ExtendedOperators(t *testing.T) {
+	tr := NewTranslator(testDialect{})
+	stmt, err := tr.Select(query.Select{
+		Table: "User",
+		Where: query.And(
+			query.Filter{Op: query.OpIn, Column: "role", Value: []any{"PILOT", "ADMIN"}},
+			query.Filter{Op: query.OpNin, Column: "status", Value: []any{"BANNED"}},
+			query.Filter{Op: query.OpNe, Column: "id", Value: 7},
+			query.Filter{Op: query.OpLike, Column: "email", Value: "%@acme.com"},
+			query.Filter{Op: query.OpILike, Column: "name", Value: "jo%"},
+			query.Filter{Op: query.OpIsNull, Column: "deletedAt", Value: true},
+			query.Filter{Op: query.OpIsNull, Column: "verifiedAt", Value: false},
+			query.Filter{Op: query.OpBetween, Column: "age", Value: []any{18, 30}},
+		),
+		Limit: 10,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, `SELECT * FROM "User" WHERE "role" IN ($1, $2) AND "status" NOT IN ($3) AND "id" <> $4`+
+		` AND "email" LIKE $5 AND LOWER("name") LIKE LOWER($6) AND "deletedAt" IS NULL AND "verifiedAt" IS NOT NULL`+
+		` AND "age" BETWEEN $7 AND $8 LIMIT $9 OFFSET $10`, stmt.Text)
+	assert.Equal(t, []any{"PILOT", "ADMIN", "BANNED", 7, "%@acme.com", "jo%", 18, 30, 10, 0}, stmt.Args)
+}
+
+func TestTranslatorSelect_NullableSeek(t *testing.T) {
+	tr := NewTranslator(testDialect{})
+	order := []query.Order{{Column: "role", Nullable: true}, {Column: "id"}}
+
+	stmt, err := tr.Select(query.Select{Table: "User", OrderBy: order, After: []any{"A", 3}, Limit: 2})
+	require.NoError(t, err)
+	assert.Equal(t, `SELECT * FROM "User" WHERE ((("role" > $1 OR "role" IS NULL)) OR ("role" = $2 AND "id" > $3))`+
+		` ORDER BY ("role" IS NULL) ASC, "role" ASC, "id" ASC LIMIT $4 OFFSET $5`, stmt.Text)
+	assert.Equal(t, []any{"A", "A", 3, 2, 0}, stmt.Args)
+
+	// Depois de um NULL em ASC só restam os nulos seguintes
+	stmt, err = tr.Select(query.Select{Table: "User", OrderBy: order, After: []any{nil, 3}, Limit: 2})
+	require.NoError(t, err)
+	assert.Equal(t, `SELECT * FROM "User" WHERE (("role" IS NULL AND "id" > $1))`+
+		` ORDER BY ("role" IS NULL) ASC, "role" ASC, "id" ASC LIMIT $2 OFFSET $3`, stmt.Text)
+
+	desc := []query.Order{{Column: "role", Desc: true, Nullable: true}, {Column: "id", Desc: true}}
+	stmt, err = tr.Select(query.Select{Table: "User", OrderBy: desc, After: []any{nil, 3}, Limit: 2})
+	require.NoError(t, err)
+	assert.Equal(t, `SELECT * FROM "User" WHERE (("role" IS NOT NULL) OR ("role" IS NULL AND "id" < $1))`+
+		` ORDER BY ("role" IS NULL) DESC, "role" DESC, "id" DESC LIMIT $2 OFFSET $3`, stmt.Text)
+}
+
+func TestTranslatorSelect_InRequiresList(t *testing.T) {
+	tr := NewTranslator(testDialect{})
+	_, err := tr.Select(query.Select{Table: "User", Where: query.And(query.Filter{Op: query.OpIn, Column: "role", Value: []any{}})})
+	assert.Error(t, err)
+}
+
+func TestTranslatorSelect_BooleanGroups(t *testing.T) {
+	tr := NewTranslator(testDialect{})
+	stmt, err := tr.Select(query.Select{
+		Table: "User",
+		Where: query.And(
+			query.Filter{Op: query.OpEq, Column: "active", Value: true},
+			query.Filter{Op: query.OpOr, Children: []query.Filter{
+				{Op: query.OpAnd, Children: []query.Filter{{Op: query.OpEq, Column: "status", Value: "A"}}},
+				{Op: query.OpAnd, Children: []query.Filter{
+					{Op: query.OpEq, Column: "status", Value: "B"},
+					{Op: query.OpGt, Column: "age", Value: 18},
+				}},
+			}},
+			query.Filter{Op: query.OpNot, Children: []query.Filter{{Op: query.OpEq, Column: "role", Value: "ADMIN"}}},
+		),
+		Limit: 10,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, `SELECT * FROM "User" WHERE "active" = $1 AND ("status" = $2 OR ("status" = $3 AND "age" > $4))`+
+		` AND NOT ("role" = $5) LIMIT $6 OFFSET $7`, stmt.Text)
+	assert.Equal(t, []any{true, "A", "B", 18, "ADMIN", 10, 0}, stmt.Args)
+}
+
+func TestTranslatorSelect_SeekMixedDirections(t *testing.T) {
+	tr := NewTranslator(testDialect{})
+	stmt, err := tr.Select(query.Select{
+		Table:   "User",
+		Where:   query.And(query.Filter{Op: query.OpEq, Column: "role", Value: "PILOT"}),
+		OrderBy: []query.Order{{Column: "createdAt", Desc: true}, {Column: "id"}},
+		After:   []any{"2025-01-01", 7},
+		Limit:   10,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, `SELECT * FROM "User" WHERE "role" = $1 AND (("createdAt" < $2) OR ("createdAt" = $3 AND "id" > $4))`+
+		` ORDER BY "createdAt" DESC, "id" ASC LIMIT $5 OFFSET $6`, stmt.Text)
+	assert.Equal(t, []any{"PILOT", "2025-01-01", "2025-01-01", 7, 10, 0}, stmt.Args)
+}
+
+func TestTranslatorSelect_Aggregation(t *testing.T) {
+	tr := NewTranslator(testDialect{})
+	spec := query.Select{
+		Table:   "Order",
+		Where:   query.And(query.Filter{Op: query.OpEq, Column: "status", Value: "PAID"}),
+		GroupBy: []string{"customerId"},
+		Aggregates: []query.Aggregate{
+			{Func: query.AggCount, Alias: "orders"},
+			{Func: query.AggSum, Column: "amount", Alias: "total"},
+		},
+		Having:  query.And(query.Filter{Op: query.OpGt, Column: "total", Value: 100}),
+		OrderBy: []query.Order{{Column: "total", Desc: true}},
+		Limit:   10,
+	}
+
+	stmt, err := tr.Select(spec)
+	require.NoError(t, err)
+	assert.Equal(t, `SELECT "customerId", COUNT(*) AS "orders", SUM("amount") AS "total" FROM "Order" WHERE "status" = $1`+
+		` GROUP BY "customerId" HAVING SUM("amount") > $2 ORDER BY "total" DESC LIMIT $3 OFFSET $4`, stmt.Text)
+	assert.Equal(t, []any{"PAID", 100, 10, 0}, stmt.Args)
+
+	count, err := tr.Count(spec)
+	require.NoError(t, err)
+	assert.Equal(t, `SELECT COUNT(1) AS total FROM (SELECT "customerId", COUNT(*) AS "orders", SUM("amount") AS "total" FROM "Order"`+
+		` WHERE "status" = $1 GROUP BY "customerId" HAVING SUM("amount") > $2) AS grouped`, count.Text)
+	assert.Equal(t, []any{"PAID", 100}, count.Args)
+}
+
+func TestTranslatorSelect_AggregateRequiresColumn(t *testing.T) {
+	tr := NewTranslator(testDialect{})
+	_, err := tr.Select(query.Select{Table: "Order", Aggregates: []query.Aggregate{{Func: query.AggSum, Alias: "s"}}})
+	assert.Error(t, err)
+}
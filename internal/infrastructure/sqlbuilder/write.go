@@ -0,0 +1,98 @@
+package sqlbuilder
+
+import (
+	"fmt"
+	"strings"
+
+	"api-database/internal/domain/query"
+)
+
+// Insert monta INSERT INTO ... (cols) VALUES (...), (...) RETURNING ...
+func (t *Translator) Insert(spec query.Insert) (query.Statement, error) {
+	returning, err := t.returning(spec.Returning)
+	if err != nil {
+		return query.Statement{}, err
+	}
+	if len(spec.Columns) == 0 || len(spec.Rows) == 0 {
+		return query.Statement{}, fmt.Errorf("insert requires columns and rows")
+	}
+
+	b := &builder{dialect: t.dialect}
+	rows := make([]string, len(spec.Rows))
+	for i, row := range spec.Rows {
+		if len(row) != len(spec.Columns) {
+			return query.Statement{}, fmt.Errorf("row %d has %d values for %d columns", i, len(row), len(spec.Columns))
+		}
+		phs := make([]string, len(row))
+		for j, v := range row {
+			phs[j] = b.bind(v)
+		}
+		rows[i] = "(" + strings.Join(phs, ", ") + ")"
+	}
+	text := fmt.Sprintf("INSERT INTO %s (%s) VALUES %s %s",
+		t.qualified(spec.Schema, spec.Table), t.columnList(spec.Columns), strings.Join(rows, ", "), returning)
+	return query.Statement{Text: text, Args: b.args}, nil
+}
+
+// Update monta UPDATE ... SET ... WHERE ... RETURNING ...; Where é obrigatório.
+func (t *Translator) Update(spec query.Update) (query.Statement, error) {
+	returning, err := t.returning(spec.Returning)
+	if err != nil {
+		return query.Statement{}, err
+	}
+	if len(spec.Set) == 0 {
+		return query.Statement{}, fmt.Errorf("update requires at least one column")
+	}
+
+	b := &builder{dialect: t.dialect}
+	set := make([]string, len(spec.Set))
+	for i, a := range spec.Set {
+		set[i] = t.dialect.QuoteIdent(a.Column) + " = " + b.bind(a.Value)
+	}
+	where, err := t.requiredWhere(b, spec.Where)
+	if err != nil {
+		return query.Statement{}, err
+	}
+	text := fmt.Sprintf("UPDATE %s SET %s WHERE %s %s",
+		t.qualified(spec.Schema, spec.Table), strings.Join(set, ", "), where, returning)
+	return query.Statement{Text: text, Args: b.args}, nil
+}
+
+// Delete monta DELETE FROM ... WHERE ... RETURNING ...; Where é obrigatório.
+func (t *Translator) Delete(spec query.Delete) (query.Statement, error) {
+	returning, err := t.returning(spec.Returning)
+	if err != nil {
+		return query.Statement{}, err
+	}
+	b := &builder{dialect: t.dialect}
+	where, err := t.requiredWhere(b, spec.Where)
+	if err != nil {
+		return query.Statement{}, err
+	}
+	text := fmt.Sprintf("DELETE FROM %s WHERE %s %s", t.qualified(spec.Schema, spec.Table), where, returning)
+	return query.Statement{Text: text, Args: b.args}, nil
+}
+
+func (t *Translator) returning(columns []string) (string, error) {
+	r, ok := t.dialect.(Returner)
+	if !ok {
+		return "", fmt.Errorf("writes require RETURNING: %w", query.ErrUnsupported)
+	}
+	list := "*"
+	if len(columns) > 0 {
+		list = t.columnList(columns)
+	}
+	return r.Returning(list), nil
+}
+
+// requiredWhere recusa escritas sem filtro, que atingiriam a tabela inteira.
+func (t *Translator) requiredWhere(b *builder, f *query.Filter) (string, error) {
+	where, err := b.where(f)
+	if err != nil {
+		return "", err
+	}
+	if where == "" {
+		return "", fmt.Errorf("update and delete require a filter")
+	}
+	return where, nil
+}
@@ -0,0 +1,66 @@
+package sqlbuilder
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"api-database/internal/domain/query"
+)
+
+// returningDialect acrescenta RETURNING ao testDialect.
+type returningDialect struct{ testDialect }
+
+func (returningDialect) Returning(columns string) string { return "RETURNING " + columns }
+
+func TestTranslatorInsert(t *testing.T) {
+	tr := NewTranslator(returningDialect{})
+	stmt, err := tr.Insert(query.Insert{
+		Schema:    "public",
+		Table:     "User",
+		Columns:   []string{"email", "role"},
+		Rows:      [][]any{{"a@example.com", "PILOT"}, {"b@example.com", nil}},
+		Returning: []string{"id", "email"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, `INSERT INTO "public"."User" ("email", "role") VALUES ($1, $2), ($3, $4) RETURNING "id", "email"`, stmt.Text)
+	assert.Equal(t, []any{"a@example.com", "PILOT", "b@example.com", nil}, stmt.Args)
+
+	_, err = tr.Insert(query.Insert{Table: "User", Columns: []string{"email"}, Rows: [][]any{{"a", "b"}}})
+	assert.Error(t, err)
+}
+
+func TestTranslatorUpdateAndDelete(t *testing.T) {
+	tr := NewTranslator(returningDialect{})
+	where := query.And(query.Filter{Op: query.OpIn, Column: "id", Value: []any{1, 2}})
+
+	stmt, err := tr.Update(query.Update{
+		Table: "User",
+		Set:   []query.Assignment{{Column: "active", Value: false}, {Column: "role", Value: "ADMIN"}},
+		Where: where,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, `UPDATE "User" SET "active" = $1, "role" = $2 WHERE "id" IN ($3, $4) RETURNING *`, stmt.Text)
+	assert.Equal(t, []any{false, "ADMIN", 1, 2}, stmt.Args)
+
+	stmt, err = tr.Delete(query.Delete{Table: "User", Where: where, Returning: []string{"id"}})
+	require.NoError(t, err)
+	assert.Equal(t, `DELETE FROM "User" WHERE "id" IN ($1, $2) RETURNING "id"`, stmt.Text)
+	assert.Equal(t, []any{1, 2}, stmt.Args)
+}
+
+func TestTranslatorWrite_RequiresFilter(t *testing.T) {
+	tr := NewTranslator(returningDialect{})
+	_, err := tr.Update(query.Update{Table: "User", Set: []query.Assignment{{Column: "role", Value: "A"}}})
+	assert.Error(t, err)
+	_, err = tr.Delete(query.Delete{Table: "User", Where: query.And()})
+	assert.Error(t, err)
+}
+
+func TestTranslatorWrite_RequiresReturning(t *testing.T) {
+	tr := NewTranslator(testDialect{})
+	_, err := tr.Delete(query.Delete{Table: "User", Where: query.And(query.Filter{Op: query.OpEq, Column: "id", Value: 1})})
+	assert.True(t, errors.Is(err, query.ErrUnsupported))
+}
@@ -0,0 +1,183 @@
+package sqldb
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"api-database/internal/domain/datasource"
+)
+
+// ValueNormalizer converte um valor escaneado conforme o nome e o tipo da coluna (DatabaseTypeName).
+type ValueNormalizer func(column, dbType string, v any) any
+
+// ErrorMapper converte erros do driver em AppError, sem repassar ao cliente mensagens que
+// podem conter dados de conexão ou valores das linhas.
+type ErrorMapper func(err error) error
+
+// Connector implementa datasource.DatabaseConnector sobre database/sql.
+// Cada driver (MySQL, SQLite) fornece o *sql.DB, a normalização de tipos e o mapeamento de erros.
+type Connector struct {
+	db        *sql.DB
+	normalize ValueNormalizer
+	mapError  ErrorMapper
+}
+
+func NewConnector(db *sql.DB, normalize ValueNormalizer) *Connector {
+	return &Connector{db: db, normalize: normalize, mapError: func(err error) error { return err }}
+}
+
+// WithErrorMapper define a conversão dos erros do driver (padrão: repassados sem alteração).
+func (c *Connector) WithErrorMapper(mapError ErrorMapper) *Connector {
+	c.mapError = mapError
+	return c
+}
+
+// ApplyPool aplica o dimensionamento do datasource ao pool do database/sql.
+func ApplyPool(db *sql.DB, pool datasource.Pool) {
+	if pool.MaxConns > 0 {
+		db.SetMaxOpenConns(pool.MaxConns)
+	}
+	if pool.MinConns > 0 {
+		db.SetMaxIdleConns(pool.MinConns)
+	}
+	if pool.MaxConnIdleTimeMs > 0 {
+		db.SetConnMaxIdleTime(time.Duration(pool.MaxConnIdleTimeMs) * time.Millisecond)
+	}
+	if pool.MaxConnLifetimeMs > 0 {
+		db.SetConnMaxLifetime(time.Duration(pool.MaxConnLifetimeMs) * time.Millisecond)
+	}
+}
+
+// Query retorna linhas como slice de map[string]any.
+func (c *Connector) Query(ctx context.Context, stmt string, args ...any) ([]map[string]any, error) {
+	return queryRows(ctx, c.db, c.normalize, c.mapError, stmt, args...)
+}
+
+// Stream entrega as linhas a fn conforme são lidas do driver.
+func (c *Connector) Stream(ctx context.Context, fn func(row map[string]any) error, stmt string, args ...any) error {
+	return streamRows(ctx, c.db, c.normalize, c.mapError, fn, stmt, args...)
+}
+
+// Execute executa uma instrução sem retorno de linhas e informa as linhas afetadas.
+func (c *Connector) Execute(ctx context.Context, stmt string, args ...any) (int64, error) {
+	res, err := c.db.ExecContext(ctx, stmt, args...)
+	if err != nil {
+		return 0, c.mapError(err)
+	}
+	return res.RowsAffected()
+}
+
+// Transaction executa fn em uma transação; erro em fn (ou panic) provoca rollback.
+func (c *Connector) Transaction(ctx context.Context, opts datasource.TxOptions, fn func(tx datasource.Executor) error) (err error) {
+	tx, err := c.db.BeginTx(ctx, &sql.TxOptions{Isolation: isolationLevel(opts.Isolation), ReadOnly: opts.ReadOnly})
+	if err != nil {
+		return c.mapError(err)
+	}
+	defer func() {
+		if p := recover(); p != nil {
+			_ = tx.Rollback()
+			panic(p)
+		}
+		if err != nil {
+			_ = tx.Rollback()
+			return
+		}
+		if err = tx.Commit(); err != nil {
+			err = c.mapError(err)
+		}
+	}()
+	return fn(&txExecutor{tx: tx, normalize: c.normalize, mapError: c.mapError})
+}
+
+// Close fecha o pool.
+func (c *Connector) Close() {
+	_ = c.db.Close()
+}
+
+func isolationLevel(level string) sql.IsolationLevel {
+	switch level {
+	case "read uncommitted":
+		return sql.LevelReadUncommitted
+	case "read committed":
+		return sql.LevelReadCommitted
+	case "repeatable read":
+		return sql.LevelRepeatableRead
+	case "serializable":
+		return sql.LevelSerializable
+	default:
+		return sql.LevelDefault
+	}
+}
+
+// txExecutor expõe uma *sql.Tx como datasource.Executor.
+type txExecutor struct {
+	tx        *sql.Tx
+	normalize ValueNormalizer
+	mapError  ErrorMapper
+}
+
+func (t *txExecutor) Query(ctx context.Context, stmt string, args ...any) ([]map[string]any, error) {
+	return queryRows(ctx, t.tx, t.normalize, t.mapError, stmt, args...)
+}
+
+func (t *txExecutor) Execute(ctx context.Context, stmt string, args ...any) (int64, error) {
+	res, err := t.tx.ExecContext(ctx, stmt, args...)
+	if err != nil {
+		return 0, t.mapError(err)
+	}
+	return res.RowsAffected()
+}
+
+// querier é satisfeito por *sql.DB e *sql.Tx.
+type querier interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+}
+
+func queryRows(ctx context.Context, q querier, normalize ValueNormalizer, mapError ErrorMapper, stmt string, args ...any) ([]map[string]any, error) {
+	var result []map[string]any
+	err := streamRows(ctx, q, normalize, mapError, func(row map[string]any) error {
+		result = append(result, row)
+		return nil
+	}, stmt, args...)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// streamRows mapeia apenas os erros do driver; os de fn são devolvidos como vieram.
+func streamRows(ctx context.Context, q querier, normalize ValueNormalizer, mapError ErrorMapper, fn func(row map[string]any) error, stmt string, args ...any) error {
+	rows, err := q.QueryContext(ctx, stmt, args...)
+	if err != nil {
+		return mapError(err)
+	}
+	defer rows.Close()
+
+	types, err := rows.ColumnTypes()
+	if err != nil {
+		return mapError(err)
+	}
+
+	values := make([]any, len(types))
+	ptrs := make([]any, len(types))
+	for i := range values {
+		ptrs[i] = &values[i]
+	}
+	for rows.Next() {
+		if err := rows.Scan(ptrs...); err != nil {
+			return mapError(err)
+		}
+		item := make(map[string]any, len(types))
+		for i, ct := range types {
+			item[ct.Name()] = normalize(ct.Name(), ct.DatabaseTypeName(), values[i])
+		}
+		if err := fn(item); err != nil {
+			return err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return mapError(err)
+	}
+	return nil
+}
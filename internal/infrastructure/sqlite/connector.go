@@ -0,0 +1,83 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	_ "modernc.org/sqlite" // driver "sqlite" (Go puro, sem CGO)
+
+	"api-database/internal/domain/datasource"
+	"api-database/internal/infrastructure/sqldb"
+)
+
+// Open cria o connector de um datasource do tipo sqlite; Connection.Database é o caminho do arquivo.
+func Open(ctx context.Context, ds *datasource.DataSource) (datasource.DatabaseConnector, error) {
+	if ds.Connection.Database == "" {
+		return nil, fmt.Errorf("sqlite datasource %q: connection.database (file path) is required", ds.Name)
+	}
+	db, err := sql.Open("sqlite", buildDSN(ds.Connection.Database))
+	if err != nil {
+		return nil, mapError(err)
+	}
+	sqldb.ApplyPool(db, ds.Pool)
+	if err := db.PingContext(ctx); err != nil {
+		_ = db.Close()
+		return nil, mapError(err)
+	}
+	return sqldb.NewConnector(db, valueNormalizer(ds.UUIDColumns)).WithErrorMapper(mapError), nil
+}
+
+// buildDSN monta a URI file: do caminho; o caminho é escapado para que '?', '#' ou '%' no
+// nome do arquivo não sejam lidos como parâmetros.
+func buildDSN(path string) string {
+	params := url.Values{}
+	params.Add("_pragma", "busy_timeout(5000)")
+	params.Add("_pragma", "foreign_keys(1)")
+	dsn := url.URL{Scheme: "file", Path: path, OmitHost: true, RawQuery: params.Encode()}
+	return dsn.String()
+}
+
+// valueNormalizer converte como UUID apenas BLOBs de colunas declaradas como UUID ou
+// listadas em DataSource.UUIDColumns: um hash de 16 bytes não é um UUID.
+func valueNormalizer(uuidColumns []string) sqldb.ValueNormalizer {
+	uuids := make(map[string]bool, len(uuidColumns))
+	for _, col := range uuidColumns {
+		uuids[col] = true
+	}
+	return func(column, dbType string, v any) any {
+		return normalizeValue(dbType, v, uuids[column] || strings.EqualFold(dbType, "UUID"))
+	}
+}
+
+// normalizeValue produz as mesmas formas JSON do connector Postgres:
+// datas em RFC3339 UTC, BLOB de 16 bytes de colunas UUID como UUID e BOOLEAN como bool.
+func normalizeValue(dbType string, v any, uuid bool) any {
+	switch val := v.(type) {
+	case time.Time:
+		return val.UTC().Format(time.RFC3339Nano)
+	case []byte:
+		if uuid && len(val) == 16 {
+			return formatUUIDBytes(val)
+		}
+		return string(val)
+	case int64:
+		switch strings.ToUpper(dbType) {
+		case "BOOLEAN", "BOOL":
+			return val != 0
+		}
+		return val
+	default:
+		return v
+	}
+}
+
+// formatUUIDBytes converte 16 bytes em string UUID canonical (8-4-4-4-12).
+func formatUUIDBytes(b []byte) string {
+	hexStr := hex.EncodeToString(b)
+	return fmt.Sprintf("%s-%s-%s-%s-%s", hexStr[0:8], hexStr[8:12], hexStr[12:16], hexStr[16:20], hexStr[20:32])
+}
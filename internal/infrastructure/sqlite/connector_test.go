@@ -0,0 +1,100 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"api-database/internal/domain"
+	"api-database/internal/domain/datasource"
+)
+
+func openTestDB(t *testing.T, path string, uuidColumns ...string) datasource.DatabaseConnector {
+	t.Helper()
+	conn, err := Open(context.Background(), &datasource.DataSource{
+		Name:        "main",
+		Connection:  datasource.Connection{Database: path},
+		UUIDColumns: uuidColumns,
+	})
+	require.NoError(t, err)
+	t.Cleanup(conn.Close)
+	return conn
+}
+
+func TestOpen_PathWithURICharacters(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "a?mode=ro#b%20c")
+	require.NoError(t, os.Mkdir(dir, 0o755))
+	path := filepath.Join(dir, "main.db")
+
+	conn := openTestDB(t, path)
+	_, err := conn.Execute(context.Background(), `CREATE TABLE t (id INTEGER)`)
+	require.NoError(t, err)
+
+	// O arquivo foi criado no caminho informado, sem parte dele virar parâmetro da URI
+	_, err = os.Stat(path)
+	assert.NoError(t, err)
+}
+
+func TestNormalize_UUIDOnlyWhenKnown(t *testing.T) {
+	conn := openTestDB(t, filepath.Join(t.TempDir(), "main.db"), "ownerId")
+	ctx := context.Background()
+	_, err := conn.Execute(ctx, `CREATE TABLE t (id UUID, ownerId BLOB, digest BLOB)`)
+	require.NoError(t, err)
+	raw := []byte{0x67, 0xfb, 0x3c, 0x8f, 0x2f, 0x6b, 0x4b, 0x2e, 0xb5, 0xf6, 0x3e, 0x9c, 0x9e, 0xfc, 0x1c, 0x89}
+	_, err = conn.Execute(ctx, `INSERT INTO t VALUES (?, ?, ?)`, raw, raw, []byte("0123456789abcdef"))
+	require.NoError(t, err)
+
+	rows, err := conn.Query(ctx, `SELECT id, ownerId, digest FROM t`)
+	require.NoError(t, err)
+	require.Len(t, rows, 1)
+	assert.Equal(t, "67fb3c8f-2f6b-4b2e-b5f6-3e9c9efc1c89", rows[0]["id"])
+	assert.Equal(t, "67fb3c8f-2f6b-4b2e-b5f6-3e9c9efc1c89", rows[0]["ownerId"])
+	assert.Equal(t, "0123456789abcdef", rows[0]["digest"])
+}
+
+func TestMapError(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "main.db")
+	conn := openTestDB(t, path)
+	ctx := context.Background()
+	_, err := conn.Execute(ctx, `CREATE TABLE t (id INTEGER PRIMARY KEY, email TEXT UNIQUE)`)
+	require.NoError(t, err)
+	_, err = conn.Execute(ctx, `INSERT INTO t VALUES (1, 'a@example.com')`)
+	require.NoError(t, err)
+
+	cases := map[string]struct {
+		stmt    string
+		code    domain.ErrorCode
+		status  int
+		details map[string]interface{}
+	}{
+		"undefined table":  {`SELECT * FROM "Usr"`, domain.ErrUndefinedTable, 404, map[string]interface{}{"errno": 1, "table": "Usr"}},
+		"undefined column": {`SELECT mail FROM t`, domain.ErrUndefinedColumn, 400, map[string]interface{}{"errno": 1, "column": "mail"}},
+		"constraint":       {`INSERT INTO t VALUES (2, 'a@example.com')`, domain.ErrQueryFailed, 400, map[string]interface{}{"errno": 19}},
+		"syntax":           {`SELEC 1`, domain.ErrQueryFailed, 500, map[string]interface{}{"errno": 1}},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := conn.Query(ctx, tc.stmt)
+			var appErr *domain.AppError
+			require.True(t, errors.As(err, &appErr), "got %v", err)
+			assert.Equal(t, tc.code, appErr.Code)
+			assert.Equal(t, tc.status, appErr.Status())
+			assert.Equal(t, tc.details, appErr.Details)
+			assert.NotContains(t, appErr.Message, path)
+		})
+	}
+}
+
+func TestMapError_UnclassifiedIsGeneric(t *testing.T) {
+	assert.NoError(t, mapError(nil))
+	var appErr *domain.AppError
+	require.True(t, errors.As(mapError(sql.ErrConnDone), &appErr))
+	assert.Equal(t, domain.ErrQueryFailed, appErr.Code)
+	assert.ErrorIs(t, appErr, sql.ErrConnDone)
+}
@@ -0,0 +1,72 @@
+package sqlite
+
+import (
+	"strings"
+
+	"api-database/internal/infrastructure/sqlbuilder"
+)
+
+// Dialect implementa sqlbuilder.Dialect para SQLite (aspas duplas e ?).
+type Dialect struct{}
+
+func (Dialect) QuoteIdent(name string) string {
+	return "\"" + strings.ReplaceAll(name, "\"", "\"\"") + "\""
+}
+
+func (Dialect) Placeholder(int) string {
+	return "?"
+}
+
+// Returning devolve as linhas afetadas pelas escritas (SQLite 3.35+).
+func (Dialect) Returning(columns string) string {
+	return "RETURNING " + columns
+}
+
+// NewTranslator retorna o tradutor SQL do dialeto SQLite.
+func NewTranslator() *sqlbuilder.Translator {
+	return sqlbuilder.NewTranslator(Dialect{})
+}
+
+// NewInspector lê o catálogo via sqlite_master e funções pragma (SQLite não tem
+// information_schema). FKs sem coluna de destino explícita apontam para a chave primária.
+func NewInspector() *sqlbuilder.Inspector {
+	return sqlbuilder.NewInspector(sqlbuilder.Catalog{
+		Tables: func(string) (string, []any) {
+			return tablesQuery, nil
+		},
+		Columns: func(_, table string) (string, []any) {
+			return columnsQuery, []any{table}
+		},
+		PrimaryKey: func(_, table string) (string, []any) {
+			return primaryKeyQuery, []any{table}
+		},
+		Indexes: func(_, table string) (string, []any) {
+			return indexesQuery, []any{table}
+		},
+		ForeignKeys: func(_, table string) (string, []any) {
+			return foreignKeysQuery, []any{table, table}
+		},
+	})
+}
+
+const tablesQuery = `SELECT name AS table_name FROM sqlite_master
+WHERE type IN ('table', 'view') AND name NOT LIKE 'sqlite_%'
+ORDER BY name`
+
+const columnsQuery = `SELECT name AS column_name, type AS data_type,
+	CASE WHEN "notnull" = 1 OR pk > 0 THEN 'NO' ELSE 'YES' END AS is_nullable
+FROM pragma_table_info(?)
+ORDER BY cid`
+
+const primaryKeyQuery = `SELECT name AS column_name FROM pragma_table_info(?) WHERE pk > 0 ORDER BY pk`
+
+const indexesQuery = `SELECT il.name AS index_name, ii.name AS column_name, il."unique" AS is_unique
+FROM pragma_index_list(?) il JOIN pragma_index_info(il.name) ii
+ORDER BY il.name, ii.seqno`
+
+const foreignKeysQuery = `SELECT m.name || '_fk_' || p.id AS name, m.name AS table_name, p."from" AS column_name,
+	p."table" AS ref_table,
+	COALESCE(p."to", (SELECT ti.name FROM pragma_table_info(p."table") ti WHERE ti.pk = p.seq + 1)) AS ref_column
+FROM sqlite_master m JOIN pragma_foreign_key_list(m.name) p
+WHERE m.type = 'table' AND (m.name = ? OR p."table" = ?)
+ORDER BY m.name, p.id, p.seq`
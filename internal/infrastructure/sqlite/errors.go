@@ -0,0 +1,70 @@
+package sqlite
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	sqlitedriver "modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+
+	"api-database/internal/domain"
+)
+
+// mapError converte erros do driver SQLite em AppError, como o connector Postgres: Details
+// traz apenas o código primário do SQLite e o identificador envolvido. A mensagem do driver
+// (com caminho do arquivo ou trechos do SQL) fica só como causa, para os logs.
+func mapError(err error) error {
+	if err == nil {
+		return nil
+	}
+	var appErr *domain.AppError
+	if errors.As(err, &appErr) {
+		return appErr
+	}
+
+	var liteErr *sqlitedriver.Error
+	if errors.As(err, &liteErr) {
+		return mapSQLiteError(liteErr)
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return domain.NewAppError(domain.ErrQueryTimeout, "query timed out", http.StatusGatewayTimeout)
+	}
+	return domain.QueryFailed(err)
+}
+
+func mapSQLiteError(liteErr *sqlitedriver.Error) *domain.AppError {
+	code := liteErr.Code() & 0xff // código estendido → primário
+	details := map[string]interface{}{"errno": code}
+	// Ex.: "SQL logic error: no such table: Usr (1)"
+	name := func(prefix string) string {
+		_, rest, ok := strings.Cut(liteErr.Error(), prefix)
+		if !ok {
+			return ""
+		}
+		rest, _, _ = strings.Cut(rest, " (")
+		return strings.TrimSpace(rest)
+	}
+
+	var err *domain.AppError
+	switch {
+	case code == sqlite3.SQLITE_ERROR && name("no such table:") != "":
+		err = domain.NewAppError(domain.ErrUndefinedTable, "table does not exist", http.StatusNotFound)
+		details["table"] = name("no such table:")
+	case code == sqlite3.SQLITE_ERROR && name("no such column:") != "":
+		err = domain.NewAppError(domain.ErrUndefinedColumn, "column does not exist", http.StatusBadRequest)
+		details["column"] = name("no such column:")
+	case code == sqlite3.SQLITE_INTERRUPT: // cancelamento pelo contexto
+		err = domain.NewAppError(domain.ErrQueryTimeout, "query timed out", http.StatusGatewayTimeout)
+	case code == sqlite3.SQLITE_PERM, code == sqlite3.SQLITE_AUTH, code == sqlite3.SQLITE_READONLY:
+		err = domain.NewAppError(domain.ErrPermissionDenied, "permission denied on datasource", http.StatusForbidden)
+	case code == sqlite3.SQLITE_BUSY, code == sqlite3.SQLITE_LOCKED, code == sqlite3.SQLITE_CANTOPEN:
+		err = domain.NewAppError(domain.ErrUnavailable, "datasource unavailable", http.StatusServiceUnavailable)
+	case code == sqlite3.SQLITE_CONSTRAINT, code == sqlite3.SQLITE_MISMATCH, code == sqlite3.SQLITE_TOOBIG:
+		err = domain.NewAppError(domain.ErrQueryFailed, "query rejected by datasource", http.StatusBadRequest)
+	default:
+		err = domain.NewAppError(domain.ErrQueryFailed, "query failed", http.StatusInternalServerError)
+	}
+	return err.WithDetails(details).WithCause(liteErr)
+}
@@ -0,0 +1,90 @@
+package httpserver
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"api-database/internal/application/data"
+	httpmiddleware "api-database/internal/presentation/http/middleware"
+	"api-database/internal/telemetry"
+)
+
+// HandleBatch atende POST /batch/{source}: operações em ordem, em uma única transação.
+func (h *DataHandler) HandleBatch(w http.ResponseWriter, r *http.Request) {
+	source := chi.URLParam(r, "source")
+
+	var req data.BatchRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, decodeError(err))
+		return
+	}
+
+	start := time.Now()
+	resp, err := h.service.ExecuteBatch(r.Context(), httpmiddleware.GetAPIKeyFromContext(r.Context()), source, req)
+	status := "success"
+	rows := 0
+	if err != nil {
+		status = data.MetricStatus(err)
+		writeError(w, asAppError(r.Context(), err))
+	} else {
+		for _, result := range resp.Results {
+			rows += result.Rows
+		}
+		writeJSON(w, http.StatusOK, resp)
+	}
+
+	if h.metrics != nil {
+		h.metrics.RecordQuery(telemetry.QueryMetric{
+			DataSource: source,
+			Status:     status,
+			Latency:    time.Since(start).Milliseconds(),
+			Rows:       rows,
+			APIKey:     h.apiKey(r),
+			Shape:      "batch",
+		})
+	}
+}
+
+// HandleQueryBatch atende POST /data/_batch: consultas independentes executadas em paralelo,
+// cada uma com o próprio status; registra uma métrica por consulta.
+func (h *DataHandler) HandleQueryBatch(w http.ResponseWriter, r *http.Request) {
+	var queries []data.BatchQuery
+	if err := json.NewDecoder(r.Body).Decode(&queries); err != nil {
+		writeError(w, decodeError(err))
+		return
+	}
+
+	results, err := h.service.QueryBatch(r.Context(), httpmiddleware.GetAPIKeyFromContext(r.Context()), queries)
+	if err != nil {
+		writeError(w, asAppError(r.Context(), err))
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"results": results})
+
+	if h.metrics == nil {
+		return
+	}
+	apiKey := h.apiKey(r)
+	for i, result := range results {
+		metric := telemetry.QueryMetric{
+			DataSource: queries[i].Source,
+			Table:      queries[i].Table,
+			Status:     "success",
+			APIKey:     apiKey,
+			Shape:      result.Shape,
+		}
+		if result.Error != nil {
+			metric.Status = data.MetricStatus(result.Error)
+		} else {
+			metric.Latency = result.Response.Metadata.TookMs
+			metric.Rows = result.Response.Metadata.Rows
+			if result.Response.Cache != "" {
+				h.metrics.RecordCache(result.Response.Cache == data.CacheHit)
+			}
+		}
+		h.metrics.RecordQuery(metric)
+	}
+}
@@ -1,8 +1,11 @@
 package httpserver
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
@@ -23,10 +26,39 @@ type DataHandler struct {
 	metrics *telemetry.Metrics
 	jobs    job.JobRepository
 	queue   *rabbitmq.Client
+	results job.ResultStore
+	// autoAsyncP95Ms > 0 habilita o roteamento automático para async pelo histórico de latência.
+	autoAsyncP95Ms int64
 }
 
-func NewDataHandler(service *data.QueryService, metrics *telemetry.Metrics, jobs job.JobRepository, queue *rabbitmq.Client) *DataHandler {
-	return &DataHandler{service: service, metrics: metrics, jobs: jobs, queue: queue}
+// asyncDecision explica por que uma requisição foi enviada para a fila.
+type asyncDecision struct {
+	Reason      string `json:"reason"`
+	P95Ms       int64  `json:"p95Ms,omitempty"`
+	ThresholdMs int64  `json:"thresholdMs,omitempty"`
+	Samples     int    `json:"samples,omitempty"`
+}
+
+const (
+	// headerForceSync permite ao cliente recusar o roteamento automático para async.
+	headerForceSync = "X-Force-Sync"
+	// headerAsyncReason informa por que a requisição foi enfileirada.
+	headerAsyncReason = "X-Async-Reason"
+	// headerCache indica se a resposta veio do cache de resultados (HIT|MISS).
+	headerCache = "X-Cache"
+	// minLatencySamples evita decidir com histórico insuficiente.
+	minLatencySamples = 5
+)
+
+func NewDataHandler(service *data.QueryService, metrics *telemetry.Metrics, jobs job.JobRepository, queue *rabbitmq.Client, results job.ResultStore) *DataHandler {
+	return &DataHandler{service: service, metrics: metrics, jobs: jobs, queue: queue, results: results}
+}
+
+// WithAutoAsync habilita o envio automático para a fila quando o p95 histórico de
+// {source}/{table}/{shape} ultrapassa thresholdMs (ThresholdsConfig.AsyncSwitchP95Ms).
+func (h *DataHandler) WithAutoAsync(thresholdMs int) *DataHandler {
+	h.autoAsyncP95Ms = int64(thresholdMs)
+	return h
 }
 
 func (h *DataHandler) HandleQuery(w http.ResponseWriter, r *http.Request) {
@@ -35,41 +67,49 @@ func (h *DataHandler) HandleQuery(w http.ResponseWriter, r *http.Request) {
 
 	var req data.QueryRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		appErr := domain.NewAppError(domain.ErrInvalidInput, "invalid request body", http.StatusBadRequest)
-		writeError(w, appErr)
+		writeError(w, decodeError(err))
 		return
 	}
 
+	shape := data.QueryShape(req)
 	asyncRequested := strings.EqualFold(r.URL.Query().Get("async"), "true") || strings.EqualFold(r.Header.Get("Prefer"), "respond-async")
 	if asyncRequested && h.queue != nil && h.jobs != nil {
-		h.enqueueAsync(w, r, source, table, req)
+		h.enqueueAsync(w, r, source, table, req, asyncDecision{Reason: "requested"})
+		return
+	}
+	if decision, ok := h.predictAsync(r, source, table, shape); ok {
+		h.enqueueAsync(w, r, source, table, req, decision)
 		return
 	}
 
-	resp, err := h.service.QueryTable(r.Context(), source, table, req)
+	start := time.Now()
+	resp, err := h.service.QueryTable(r.Context(), httpmiddleware.GetAPIKeyFromContext(r.Context()), source, table, req)
 	if err != nil {
-		appErr, ok := err.(*domain.AppError)
-		if !ok {
-			appErr = domain.NewAppError(domain.ErrInternal, err.Error(), http.StatusInternalServerError)
-		}
-		writeError(w, appErr)
+		writeError(w, asAppError(r.Context(), err))
 
-		// Registrar métrica de erro
+		// Registrar métrica de erro; a latência de um timeout (≈ prazo) entra no p95 do shape
 		if h.metrics != nil {
 			h.metrics.RecordQuery(telemetry.QueryMetric{
 				DataSource:  source,
 				Table:       table,
-				Status:      "error",
-				Latency:     0,
+				Status:      data.MetricStatus(err),
+				Latency:     time.Since(start).Milliseconds(),
 				Rows:        0,
 				JobID:       "",
 				PayloadHash: "",
 				APIKey:      h.apiKey(r),
+				Shape:       shape,
 			})
 		}
 		return
 	}
 
+	if resp.Cache != "" {
+		w.Header().Set(headerCache, resp.Cache)
+		if h.metrics != nil {
+			h.metrics.RecordCache(resp.Cache == data.CacheHit)
+		}
+	}
 	writeJSON(w, http.StatusOK, resp)
 
 	// Registrar métrica de sucesso
@@ -83,17 +123,44 @@ func (h *DataHandler) HandleQuery(w http.ResponseWriter, r *http.Request) {
 			JobID:       "",
 			PayloadHash: "",
 			APIKey:      h.apiKey(r),
+			Shape:       shape,
 		})
 	}
 }
 
+// predictAsync decide se a consulta deve ir para a fila com base no p95 histórico.
+func (h *DataHandler) predictAsync(r *http.Request, source, table, shape string) (asyncDecision, bool) {
+	if h.autoAsyncP95Ms <= 0 || h.metrics == nil || h.queue == nil || h.jobs == nil {
+		return asyncDecision{}, false
+	}
+	if strings.EqualFold(r.Header.Get(headerForceSync), "true") {
+		return asyncDecision{}, false
+	}
+	p95, samples := h.metrics.LatencyP95(source, table, shape)
+	if samples < minLatencySamples || p95 <= h.autoAsyncP95Ms {
+		return asyncDecision{}, false
+	}
+	return asyncDecision{
+		Reason:      "predicted_latency",
+		P95Ms:       p95,
+		ThresholdMs: h.autoAsyncP95Ms,
+		Samples:     samples,
+	}, true
+}
+
 // enqueueAsync cria job, publica na fila e retorna 202.
-func (h *DataHandler) enqueueAsync(w http.ResponseWriter, r *http.Request, source, table string, req data.QueryRequest) {
+func (h *DataHandler) enqueueAsync(w http.ResponseWriter, r *http.Request, source, table string, req data.QueryRequest, decision asyncDecision) {
 	if h.queue == nil || h.jobs == nil {
 		writeError(w, domain.NewAppError(domain.ErrInternal, "async queue unavailable", http.StatusServiceUnavailable))
 		return
 	}
 
+	// Falhar cedo: o worker repete a validação, mas o cliente recebe 403 sem criar job.
+	if err := h.service.Authorize(httpmiddleware.GetAPIKeyFromContext(r.Context()), source, table, req); err != nil {
+		writeError(w, asAppError(r.Context(), err))
+		return
+	}
+
 	payloadHash, err := data.HashQueryRequest(req)
 	if err != nil {
 		writeError(w, domain.NewAppError(domain.ErrInternal, "failed to hash request", http.StatusInternalServerError))
@@ -134,11 +201,13 @@ func (h *DataHandler) enqueueAsync(w http.ResponseWriter, r *http.Request, sourc
 		return
 	}
 
+	w.Header().Set(headerAsyncReason, decision.Reason)
 	writeJSON(w, http.StatusAccepted, map[string]any{
 		"jobId":       jobID,
 		"status":      job.StatusQueued,
 		"payloadHash": payloadHash,
 		"message":     "Job enqueued for async processing",
+		"async":       decision,
 	})
 }
 
@@ -154,9 +223,91 @@ func (h *DataHandler) HandleJobStatus(w http.ResponseWriter, r *http.Request) {
 		writeError(w, domain.NewAppError(domain.ErrNotFound, "job not found", http.StatusNotFound))
 		return
 	}
+	if !h.canReadJob(r, record) {
+		writeError(w, domain.NewAppError(domain.ErrForbidden, "job belongs to another API key", http.StatusForbidden))
+		return
+	}
 	writeJSON(w, http.StatusOK, record)
 }
 
+// canReadJob restringe status, histórico e resultado de um job à chave que o criou (ou admin).
+func (h *DataHandler) canReadJob(r *http.Request, record *job.QueryJob) bool {
+	ak := httpmiddleware.GetAPIKeyFromContext(r.Context())
+	if ak == nil {
+		return record.VisibleTo("", false)
+	}
+	return record.VisibleTo(ak.Key, ak.IsAdmin())
+}
+
+// HandleJobResult retorna as linhas persistidas de um job concluído, paginadas por limit/offset.
+func (h *DataHandler) HandleJobResult(w http.ResponseWriter, r *http.Request) {
+	if h.jobs == nil || h.results == nil {
+		writeError(w, domain.NewAppError(domain.ErrResultUnavailable, "job result store unavailable", http.StatusServiceUnavailable))
+		return
+	}
+	jobID := chi.URLParam(r, "jobID")
+	record, err := h.jobs.GetByID(r.Context(), jobID)
+	if err != nil || record == nil {
+		writeError(w, domain.NewAppError(domain.ErrNotFound, "job not found", http.StatusNotFound))
+		return
+	}
+
+	if !h.canReadJob(r, record) {
+		writeError(w, domain.NewAppError(domain.ErrForbidden, "job belongs to another API key", http.StatusForbidden))
+		return
+	}
+
+	if record.Status != job.StatusSucceeded {
+		writeError(w, domain.NewAppError(domain.ErrJobNotReady, "job has not succeeded", http.StatusConflict).
+			WithDetails(map[string]interface{}{"status": record.Status}))
+		return
+	}
+	if !record.ResultStored {
+		appErr := domain.NewAppError(domain.ErrResultUnavailable, "job result was not stored", http.StatusNotFound)
+		if record.ResultError != "" {
+			appErr = appErr.WithDetails(map[string]interface{}{"reason": record.ResultError})
+		}
+		writeError(w, appErr)
+		return
+	}
+
+	rows, err := h.results.Load(r.Context(), jobID)
+	if errors.Is(err, job.ErrResultNotFound) {
+		writeError(w, domain.NewAppError(domain.ErrResultUnavailable, "job result expired", http.StatusGone))
+		return
+	}
+	if err != nil {
+		writeError(w, domain.NewAppError(domain.ErrInternal, "failed to load job result", http.StatusInternalServerError))
+		return
+	}
+
+	limit := queryInt(r, "limit", 100)
+	if limit <= 0 {
+		limit = 100
+	}
+	if limit > 500 {
+		limit = 500
+	}
+	offset := queryInt(r, "offset", 0)
+	if offset < 0 {
+		offset = 0
+	}
+	total := len(rows)
+	start := min(offset, total)
+	end := min(start+limit, total)
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"data": rows[start:end],
+		"metadata": map[string]any{
+			"jobId":  jobID,
+			"rows":   end - start,
+			"total":  total,
+			"limit":  limit,
+			"offset": offset,
+		},
+	})
+}
+
 // HandleJobsByHash retorna jobs associados a um hash de payload.
 func (h *DataHandler) HandleJobsByHash(w http.ResponseWriter, r *http.Request) {
 	if h.jobs == nil {
@@ -169,7 +320,22 @@ func (h *DataHandler) HandleJobsByHash(w http.ResponseWriter, r *http.Request) {
 		writeError(w, domain.NewAppError(domain.ErrInternal, "failed to fetch jobs", http.StatusInternalServerError))
 		return
 	}
-	writeJSON(w, http.StatusOK, items)
+	// O hash é previsível a partir do payload: listar apenas os jobs da própria chave.
+	visible := make([]*job.QueryJob, 0, len(items))
+	for _, item := range items {
+		if h.canReadJob(r, item) {
+			visible = append(visible, item)
+		}
+	}
+	writeJSON(w, http.StatusOK, visible)
+}
+
+func queryInt(r *http.Request, name string, fallback int) int {
+	v, err := strconv.Atoi(r.URL.Query().Get(name))
+	if err != nil {
+		return fallback
+	}
+	return v
 }
 
 func (h *DataHandler) apiKey(r *http.Request) string {
@@ -180,6 +346,21 @@ func (h *DataHandler) apiKey(r *http.Request) string {
 	return ak.Key
 }
 
+// decodeError preserva erros de validação do payload (ex.: operador desconhecido).
+func decodeError(err error) *domain.AppError {
+	var appErr *domain.AppError
+	if errors.As(err, &appErr) {
+		return appErr
+	}
+	return domain.NewAppError(domain.ErrInvalidInput, "invalid request body", http.StatusBadRequest)
+}
+
+// asAppError preserva erros estruturados e troca os demais por um erro genérico, sem a
+// mensagem original (registrada no log da requisição).
+func asAppError(ctx context.Context, err error) *domain.AppError {
+	return data.ClientError(ctx, err)
+}
+
 func writeJSON(w http.ResponseWriter, status int, v any) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(status)
@@ -0,0 +1,226 @@
+package httpserver
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"api-database/internal/application/data"
+	"api-database/internal/domain"
+	httpmiddleware "api-database/internal/presentation/http/middleware"
+	"api-database/internal/telemetry"
+)
+
+const (
+	contentTypeNDJSON = "application/x-ndjson"
+	contentTypeCSV    = "text/csv"
+	// headerExportStatus é enviado como trailer: "complete" ou o código do erro que
+	// interrompeu a exportação depois do início da resposta.
+	headerExportStatus = "X-Export-Status"
+	headerExportRows   = "X-Export-Rows"
+	// exportFlushEvery controla a frequência de flush para o cliente.
+	exportFlushEvery = 500
+)
+
+// HandleExport exporta o resultado da consulta em NDJSON (padrão) ou CSV, conforme o Accept.
+func (h *DataHandler) HandleExport(w http.ResponseWriter, r *http.Request) {
+	source := chi.URLParam(r, "source")
+	table := chi.URLParam(r, "table")
+
+	format, ok := negotiateExport(r.Header.Get("Accept"))
+	if !ok {
+		writeError(w, domain.NewAppError(domain.ErrInvalidInput, "unsupported Accept header for export", http.StatusNotAcceptable).
+			WithDetails(map[string]interface{}{"supported": []string{contentTypeNDJSON, contentTypeCSV}}))
+		return
+	}
+
+	var req data.QueryRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, decodeError(err))
+		return
+	}
+
+	var writer exportWriter
+	if format == contentTypeCSV {
+		writer = &csvExport{w: w, csv: csv.NewWriter(w)}
+	} else {
+		writer = &ndjsonExport{w: w, enc: json.NewEncoder(w)}
+	}
+
+	start := time.Now()
+	rows, err := h.service.ExportTable(r.Context(), httpmiddleware.GetAPIKeyFromContext(r.Context()), source, table, req, writer)
+	if writer.Started() {
+		writer.Flush()
+	}
+	switch {
+	case err != nil && !writer.Started():
+		writeError(w, asAppError(r.Context(), err))
+	case err != nil:
+		// Cabeçalhos já enviados: sinalizar o corte no trailer (e numa linha final no NDJSON).
+		appErr := asAppError(r.Context(), err)
+		writer.Fail(appErr)
+		w.Header().Set(headerExportStatus, string(appErr.Code))
+		w.Header().Set(headerExportRows, strconv.Itoa(rows))
+	default:
+		w.Header().Set(headerExportStatus, "complete")
+		w.Header().Set(headerExportRows, strconv.Itoa(rows))
+	}
+
+	if h.metrics != nil {
+		status := "success"
+		if err != nil {
+			status = data.MetricStatus(err)
+		}
+		h.metrics.RecordQuery(telemetry.QueryMetric{
+			DataSource: source,
+			Table:      table,
+			Status:     status,
+			Latency:    time.Since(start).Milliseconds(),
+			Rows:       rows,
+			APIKey:     h.apiKey(r),
+			// Formato próprio: o tempo de streaming não entra no p95 das consultas síncronas.
+			Shape: "export",
+		})
+	}
+}
+
+// negotiateExport escolhe o formato pelo Accept; vazio ou */* resultam em NDJSON.
+func negotiateExport(accept string) (string, bool) {
+	if strings.TrimSpace(accept) == "" {
+		return contentTypeNDJSON, true
+	}
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, _, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		switch mediaType {
+		case contentTypeCSV:
+			return contentTypeCSV, true
+		case contentTypeNDJSON, "application/jsonl", "application/json", "*/*", "application/*":
+			return contentTypeNDJSON, true
+		}
+	}
+	return "", false
+}
+
+// exportWriter adapta a resposta HTTP a data.RowWriter.
+type exportWriter interface {
+	data.RowWriter
+	Started() bool
+	Flush()
+	Fail(err *domain.AppError)
+}
+
+// startExport envia os cabeçalhos da resposta, declarando os trailers de status.
+func startExport(w http.ResponseWriter, contentType string) {
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Trailer", headerExportStatus+", "+headerExportRows)
+	w.WriteHeader(http.StatusOK)
+}
+
+func flush(w http.ResponseWriter) {
+	if f, ok := w.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+type ndjsonExport struct {
+	w       http.ResponseWriter
+	enc     *json.Encoder
+	started bool
+	rows    int
+}
+
+func (e *ndjsonExport) Begin([]string) error {
+	startExport(e.w, contentTypeNDJSON)
+	e.started = true
+	return nil
+}
+
+func (e *ndjsonExport) Write(row map[string]any) error {
+	if err := e.enc.Encode(row); err != nil {
+		return err
+	}
+	if e.rows++; e.rows%exportFlushEvery == 0 {
+		flush(e.w)
+	}
+	return nil
+}
+
+func (e *ndjsonExport) Started() bool { return e.started }
+func (e *ndjsonExport) Flush()        { flush(e.w) }
+
+func (e *ndjsonExport) Fail(err *domain.AppError) {
+	_ = e.enc.Encode(map[string]any{"error": err})
+	flush(e.w)
+}
+
+type csvExport struct {
+	w       http.ResponseWriter
+	csv     *csv.Writer
+	columns []string
+	record  []string
+	rows    int
+}
+
+func (e *csvExport) Begin(columns []string) error {
+	startExport(e.w, contentTypeCSV+"; charset=utf-8")
+	e.columns = columns
+	e.record = make([]string, len(columns))
+	return e.csv.Write(columns)
+}
+
+func (e *csvExport) Write(row map[string]any) error {
+	for i, col := range e.columns {
+		e.record[i] = csvValue(row[col])
+	}
+	if err := e.csv.Write(e.record); err != nil {
+		return err
+	}
+	if e.rows++; e.rows%exportFlushEvery == 0 {
+		e.Flush()
+	}
+	return nil
+}
+
+func (e *csvExport) Started() bool { return e.columns != nil }
+
+func (e *csvExport) Flush() {
+	e.csv.Flush()
+	flush(e.w)
+}
+
+// Fail não tem como sinalizar no corpo CSV; o trailer X-Export-Status carrega o erro.
+func (e *csvExport) Fail(*domain.AppError) {}
+
+// csvValue formata escalares diretamente e objetos/arrays como JSON.
+func csvValue(v any) string {
+	switch val := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return val
+	case bool:
+		return strconv.FormatBool(val)
+	case float64:
+		return strconv.FormatFloat(val, 'f', -1, 64)
+	case float32:
+		return strconv.FormatFloat(float64(val), 'f', -1, 32)
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return fmt.Sprint(val)
+	default:
+		b, err := json.Marshal(val)
+		if err != nil {
+			return fmt.Sprint(val)
+		}
+		return string(b)
+	}
+}
@@ -2,29 +2,61 @@ package middleware
 
 import (
 	"context"
+	"encoding/json"
 	"net/http"
+	"strings"
 
+	"api-database/internal/domain"
 	"api-database/internal/domain/apikey"
 )
 
 const contextKeyAPIKey = "api_key"
 
+// Modos de autenticação suportados pelo AuthMiddleware.
+const (
+	AuthModeRequired = "required"
+	AuthModeOptional = "optional"
+	AuthModeDisabled = "disabled"
+)
+
+// ValidAuthMode indica se mode é um dos modos suportados (AUTH_MODE).
+func ValidAuthMode(mode string) bool {
+	switch mode {
+	case AuthModeRequired, AuthModeOptional, AuthModeDisabled:
+		return true
+	}
+	return false
+}
+
+// AuthOptions controla o comportamento do AuthMiddleware.
+type AuthOptions struct {
+	Mode string
+	// PublicPaths aceita caminhos exatos ou prefixos terminados em "*" (ex.: "/static/*").
+	PublicPaths []string
+}
+
 // AuthMiddleware valida X-API-Key header e anexa a chave ao contexto.
-func AuthMiddleware(akRepo apikey.APIKeyRepository) func(http.Handler) http.Handler {
+// Requisições sem chave são rejeitadas com 401 (exceto rotas públicas) em qualquer modo que não
+// seja optional ou disabled: um modo desconhecido se comporta como required.
+func AuthMiddleware(akRepo apikey.APIKeyRepository, opts AuthOptions) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
+		if opts.Mode == AuthModeDisabled {
+			return next
+		}
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			key := r.Header.Get("X-API-Key")
 			if key == "" {
-				// Se não houver header, deixar passar (opcional: pode rejeitar com 401)
+				if opts.Mode != AuthModeOptional && !isPublicPath(r.URL.Path, opts.PublicPaths) {
+					writeError(w, domain.NewAppError(domain.ErrMissingAPIKey, "no API key provided", http.StatusUnauthorized))
+					return
+				}
 				next.ServeHTTP(w, r)
 				return
 			}
 
 			ak, err := akRepo.GetByKey(r.Context(), key)
 			if err != nil || ak == nil {
-				w.Header().Set("Content-Type", "application/json")
-				w.WriteHeader(http.StatusUnauthorized)
-				_, _ = w.Write([]byte(`{"code":"INVALID_API_KEY","message":"invalid or expired API key"}`))
+				writeError(w, domain.NewAppError(domain.ErrInvalidAPIKey, "invalid or expired API key", http.StatusUnauthorized))
 				return
 			}
 
@@ -35,6 +67,22 @@ func AuthMiddleware(akRepo apikey.APIKeyRepository) func(http.Handler) http.Hand
 	}
 }
 
+// RequireAdmin exige uma chave com escopo admin (usado nas rotas de gestão de chaves).
+func RequireAdmin(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ak := GetAPIKeyFromContext(r.Context())
+		if ak == nil {
+			writeError(w, domain.NewAppError(domain.ErrMissingAPIKey, "no API key provided", http.StatusUnauthorized))
+			return
+		}
+		if !ak.IsAdmin() {
+			writeError(w, domain.NewAppError(domain.ErrForbidden, "admin scope required", http.StatusForbidden))
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
 // GetAPIKeyFromContext extrai a chave do contexto.
 func GetAPIKeyFromContext(ctx context.Context) *apikey.APIKey {
 	ak, ok := ctx.Value(contextKeyAPIKey).(*apikey.APIKey)
@@ -43,3 +91,24 @@ func GetAPIKeyFromContext(ctx context.Context) *apikey.APIKey {
 	}
 	return ak
 }
+
+func isPublicPath(path string, public []string) bool {
+	for _, p := range public {
+		if prefix, ok := strings.CutSuffix(p, "*"); ok {
+			if strings.HasPrefix(path, prefix) {
+				return true
+			}
+			continue
+		}
+		if path == p {
+			return true
+		}
+	}
+	return false
+}
+
+func writeError(w http.ResponseWriter, appErr *domain.AppError) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(appErr.Status())
+	_ = json.NewEncoder(w).Encode(appErr)
+}
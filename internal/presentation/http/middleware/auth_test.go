@@ -0,0 +1,88 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"api-database/internal/domain/apikey"
+)
+
+type fakeKeyRepo struct {
+	keys map[string]*apikey.APIKey
+}
+
+func (f *fakeKeyRepo) GetByKey(_ context.Context, key string) (*apikey.APIKey, error) {
+	if ak, ok := f.keys[key]; ok {
+		return ak, nil
+	}
+	return nil, errors.New("not found")
+}
+func (f *fakeKeyRepo) List(context.Context) ([]apikey.APIKey, error)        { return nil, nil }
+func (f *fakeKeyRepo) Create(context.Context, *apikey.APIKey) error         { return nil }
+func (f *fakeKeyRepo) Update(context.Context, string, *apikey.APIKey) error { return nil }
+func (f *fakeKeyRepo) Delete(context.Context, string) error                 { return nil }
+
+var okHandler = http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusOK) })
+
+func newAuthTestHandler(opts AuthOptions, next http.Handler) http.Handler {
+	repo := &fakeKeyRepo{keys: map[string]*apikey.APIKey{
+		"user":  {Key: "user"},
+		"admin": {Key: "admin", Permissions: []apikey.Permission{{Resource: "*", Level: apikey.LevelAdmin}}},
+	}}
+	return AuthMiddleware(repo, opts)(next)
+}
+
+func doRequest(h http.Handler, path, key string) int {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	if key != "" {
+		req.Header.Set("X-API-Key", key)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec.Code
+}
+
+func TestAuthMiddleware_Required(t *testing.T) {
+	h := newAuthTestHandler(AuthOptions{Mode: AuthModeRequired, PublicPaths: []string{"/health", "/static/*"}}, okHandler)
+
+	assert.Equal(t, http.StatusUnauthorized, doRequest(h, "/data/x/y", ""))
+	assert.Equal(t, http.StatusUnauthorized, doRequest(h, "/data/x/y", "unknown"))
+	assert.Equal(t, http.StatusOK, doRequest(h, "/data/x/y", "user"))
+	assert.Equal(t, http.StatusOK, doRequest(h, "/health", ""))
+	assert.Equal(t, http.StatusOK, doRequest(h, "/static/app.js", ""))
+}
+
+func TestAuthMiddleware_Optional(t *testing.T) {
+	h := newAuthTestHandler(AuthOptions{Mode: AuthModeOptional}, okHandler)
+
+	assert.Equal(t, http.StatusOK, doRequest(h, "/data/x/y", ""))
+	assert.Equal(t, http.StatusUnauthorized, doRequest(h, "/data/x/y", "unknown"))
+}
+
+func TestAuthMiddleware_Disabled(t *testing.T) {
+	h := newAuthTestHandler(AuthOptions{Mode: AuthModeDisabled}, okHandler)
+
+	assert.Equal(t, http.StatusOK, doRequest(h, "/data/x/y", "unknown"))
+}
+
+func TestAuthMiddleware_UnknownModeFailsClosed(t *testing.T) {
+	assert.False(t, ValidAuthMode("requird"))
+	h := newAuthTestHandler(AuthOptions{Mode: "requird", PublicPaths: []string{"/health"}}, okHandler)
+
+	assert.Equal(t, http.StatusUnauthorized, doRequest(h, "/data/x/y", ""))
+	assert.Equal(t, http.StatusOK, doRequest(h, "/data/x/y", "user"))
+	assert.Equal(t, http.StatusOK, doRequest(h, "/health", ""))
+}
+
+func TestRequireAdmin(t *testing.T) {
+	h := newAuthTestHandler(AuthOptions{Mode: AuthModeOptional}, RequireAdmin(okHandler))
+
+	assert.Equal(t, http.StatusUnauthorized, doRequest(h, "/api-keys", ""))
+	assert.Equal(t, http.StatusForbidden, doRequest(h, "/api-keys", "user"))
+	assert.Equal(t, http.StatusOK, doRequest(h, "/api-keys", "admin"))
+}
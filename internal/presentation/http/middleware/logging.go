@@ -8,16 +8,18 @@ import (
 	"github.com/rs/zerolog"
 )
 
-// Logging registra requisições HTTP com request-id, status e latência.
+// Logging registra requisições HTTP com request-id, status e latência. O logger (com o
+// request-id) fica no contexto para os handlers registrarem erros via zerolog.Ctx.
 func Logging(logger zerolog.Logger) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
 			start := time.Now()
+			requestID := middleware.GetReqID(r.Context())
+			reqLogger := logger.With().Str("request_id", requestID).Logger()
 
-			next.ServeHTTP(ww, r)
+			next.ServeHTTP(ww, r.WithContext(reqLogger.WithContext(r.Context())))
 
-			requestID := middleware.GetReqID(r.Context())
 			logger.Info().
 				Str("request_id", requestID).
 				Str("method", r.Method).
@@ -3,6 +3,7 @@ package httpserver
 import (
 	"encoding/json"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/go-chi/chi/v5"
@@ -24,12 +25,27 @@ func NewRouter(cfg config.Config, logger zerolog.Logger, dataHandler *DataHandle
 	r.Use(middleware.RequestID)
 	r.Use(middleware.RealIP)
 	r.Use(middleware.Recoverer)
-	r.Use(middleware.Timeout(time.Duration(cfg.Thresholds.QueryTimeoutMs) * time.Millisecond))
+	// Teto da requisição; o prazo de cada consulta vem do datasource (limits.queryTimeoutMs).
+	// Exportações têm orçamento próprio (ASYNC_QUERY_TIMEOUT_MS) e ficam fora do teto.
+	requestTimeout := middleware.Timeout(time.Duration(cfg.Thresholds.RequestTimeoutMs) * time.Millisecond)
+	r.Use(func(next http.Handler) http.Handler {
+		limited := requestTimeout(next)
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			if strings.HasPrefix(req.URL.Path, "/export/") {
+				next.ServeHTTP(w, req)
+				return
+			}
+			limited.ServeHTTP(w, req)
+		})
+	})
 	r.Use(httpmiddleware.Logging(logger))
 
-	// Auth middleware (opcional: requer X-API-Key header)
+	// Auth middleware (modo definido por AUTH_MODE; rotas públicas via AUTH_PUBLIC_PATHS)
 	if akRepo != nil {
-		r.Use(httpmiddleware.AuthMiddleware(akRepo))
+		r.Use(httpmiddleware.AuthMiddleware(akRepo, httpmiddleware.AuthOptions{
+			Mode:        cfg.Auth.Mode,
+			PublicPaths: cfg.Auth.PublicPaths,
+		}))
 	}
 
 	r.Get("/health", func(w http.ResponseWriter, _ *http.Request) {
@@ -44,6 +60,7 @@ func NewRouter(cfg config.Config, logger zerolog.Logger, dataHandler *DataHandle
 			json.NewEncoder(w).Encode(map[string]interface{}{
 				"summary": metrics.GetSummary(),
 				"total":   len(metrics.GetMetrics()),
+				"cache":   metrics.GetCacheStats(),
 			})
 		})
 	}
@@ -73,22 +90,40 @@ func NewRouter(cfg config.Config, logger zerolog.Logger, dataHandler *DataHandle
 	}
 
 	if dataHandler != nil {
-		// Legacy endpoint
-		r.Post("/data/{source}/{table}", dataHandler.HandleQuery)
+		// Legacy endpoint (consulta) e escrita: POST com "values" insere, PATCH altera, DELETE remove
+		r.Post("/data/{source}/{table}", dataHandler.HandleDataPost)
+		// Consultas independentes em paralelo
+		r.Post("/data/_batch", dataHandler.HandleQueryBatch)
+		r.Patch("/data/{source}/{table}", dataHandler.HandleUpdate)
+		r.Delete("/data/{source}/{table}", dataHandler.HandleDelete)
 		// Async-capable endpoint
 		r.Post("/queries/{source}/{table}", dataHandler.HandleQuery)
 		r.Get("/queries/{jobID}", dataHandler.HandleJobStatus)
+		r.Get("/queries/{jobID}/result", dataHandler.HandleJobResult)
 		r.Get("/queries/hash/{hash}", dataHandler.HandleJobsByHash)
+		// Operações em uma única transação
+		r.Post("/batch/{source}", dataHandler.HandleBatch)
+		// Exportação em streaming (NDJSON ou CSV conforme Accept)
+		r.Post("/export/{source}/{table}", dataHandler.HandleExport)
+		// Introspecção de schema
+		r.Get("/datasources/{source}/tables", dataHandler.HandleListTables)
+		r.Get("/datasources/{source}/tables/{table}", dataHandler.HandleDescribeTable)
 	}
 
 	// API Key CRUD endpoints
 	if akRepo != nil {
 		akHandler := handlers.NewAPIKeyHandler(akRepo)
 		r.Get("/api-keys/me", akHandler.GetMe)
-		r.Get("/api-keys", akHandler.ListKeys)
-		r.Post("/api-keys", akHandler.CreateKey)
-		r.Put("/api-keys/{key}", akHandler.UpdateKey)
-		r.Delete("/api-keys/{key}", akHandler.DeleteKey)
+		r.Group(func(r chi.Router) {
+			// Gestão de chaves exige escopo admin (exceto com autenticação desabilitada)
+			if cfg.Auth.Mode != httpmiddleware.AuthModeDisabled {
+				r.Use(httpmiddleware.RequireAdmin)
+			}
+			r.Get("/api-keys", akHandler.ListKeys)
+			r.Post("/api-keys", akHandler.CreateKey)
+			r.Put("/api-keys/{key}", akHandler.UpdateKey)
+			r.Delete("/api-keys/{key}", akHandler.DeleteKey)
+		})
 	}
 
 	// Servir dashboard e arquivos estáticos
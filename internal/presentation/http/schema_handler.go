@@ -0,0 +1,31 @@
+package httpserver
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	httpmiddleware "api-database/internal/presentation/http/middleware"
+)
+
+// HandleListTables lista as tabelas de um datasource visíveis para a chave (?schema= opcional).
+func (h *DataHandler) HandleListTables(w http.ResponseWriter, r *http.Request) {
+	resp, err := h.service.ListTables(r.Context(), httpmiddleware.GetAPIKeyFromContext(r.Context()),
+		chi.URLParam(r, "source"), r.URL.Query().Get("schema"))
+	if err != nil {
+		writeError(w, asAppError(r.Context(), err))
+		return
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// HandleDescribeTable retorna colunas, tipos, chaves e índices de uma tabela (?schema= opcional).
+func (h *DataHandler) HandleDescribeTable(w http.ResponseWriter, r *http.Request) {
+	resp, err := h.service.DescribeTable(r.Context(), httpmiddleware.GetAPIKeyFromContext(r.Context()),
+		chi.URLParam(r, "source"), r.URL.Query().Get("schema"), chi.URLParam(r, "table"))
+	if err != nil {
+		writeError(w, asAppError(r.Context(), err))
+		return
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
@@ -0,0 +1,106 @@
+package httpserver
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"api-database/internal/application/data"
+	"api-database/internal/domain"
+	httpmiddleware "api-database/internal/presentation/http/middleware"
+	"api-database/internal/telemetry"
+)
+
+// insertKeys são os campos aceitos no corpo de um insert.
+var insertKeys = map[string]bool{"schema": true, "values": true, "fields": true}
+
+// HandleDataPost atende POST /data/{source}/{table}: corpos com "values" são inserts; os
+// demais seguem como consulta (endpoint legado). Um insert com campos de consulta (filter,
+// orderBy...) é recusado em vez de ter esses campos ignorados.
+func (h *DataHandler) HandleDataPost(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		writeError(w, decodeError(err))
+		return
+	}
+	var probe map[string]json.RawMessage
+	if json.Unmarshal(body, &probe) == nil {
+		if _, ok := probe["values"]; ok {
+			for key := range probe {
+				if !insertKeys[key] {
+					writeError(w, domain.NewAppError(domain.ErrInvalidInput,
+						fmt.Sprintf("field %q is not accepted in an insert (body has values)", key), http.StatusBadRequest))
+					return
+				}
+			}
+			h.handleWrite(w, r, data.WriteInsert, body)
+			return
+		}
+	}
+	r.Body = io.NopCloser(bytes.NewReader(body))
+	h.HandleQuery(w, r)
+}
+
+// HandleUpdate atende PATCH /data/{source}/{table}.
+func (h *DataHandler) HandleUpdate(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		writeError(w, decodeError(err))
+		return
+	}
+	h.handleWrite(w, r, data.WriteUpdate, body)
+}
+
+// HandleDelete atende DELETE /data/{source}/{table}.
+func (h *DataHandler) HandleDelete(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		writeError(w, decodeError(err))
+		return
+	}
+	h.handleWrite(w, r, data.WriteDelete, body)
+}
+
+func (h *DataHandler) handleWrite(w http.ResponseWriter, r *http.Request, op data.WriteOp, body []byte) {
+	source := chi.URLParam(r, "source")
+	table := chi.URLParam(r, "table")
+
+	var req data.WriteRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		writeError(w, decodeError(err))
+		return
+	}
+
+	start := time.Now()
+	resp, err := h.service.Write(r.Context(), httpmiddleware.GetAPIKeyFromContext(r.Context()), op, source, table, req)
+	status := "success"
+	rows := 0
+	if err != nil {
+		status = data.MetricStatus(err)
+		writeError(w, asAppError(r.Context(), err))
+	} else {
+		rows = resp.Metadata.Affected
+		code := http.StatusOK
+		if op == data.WriteInsert {
+			code = http.StatusCreated
+		}
+		writeJSON(w, code, resp)
+	}
+
+	if h.metrics != nil {
+		h.metrics.RecordQuery(telemetry.QueryMetric{
+			DataSource: source,
+			Table:      table,
+			Status:     status,
+			Latency:    time.Since(start).Milliseconds(),
+			Rows:       rows,
+			APIKey:     h.apiKey(r),
+			Shape:      string(op),
+		})
+	}
+}
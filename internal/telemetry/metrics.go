@@ -1,6 +1,7 @@
 package telemetry
 
 import (
+	"sort"
 	"sync"
 	"time"
 )
@@ -9,12 +10,13 @@ import (
 type QueryMetric struct {
 	DataSource  string
 	Table       string
-	Status      string // "success", "error"
+	Status      string // "success", "error", "timeout"
 	Latency     int64  // ms
 	Rows        int
 	JobID       string
 	PayloadHash string
 	APIKey      string
+	Shape       string // hash da estrutura da query (sem valores)
 	Timestamp   time.Time
 }
 
@@ -23,6 +25,15 @@ type Metrics struct {
 	mu      sync.RWMutex
 	queries []QueryMetric
 	maxSize int // manter últimas N queries
+
+	cacheHits   int64
+	cacheMisses int64
+}
+
+// CacheStats resume acertos e falhas do cache de resultados.
+type CacheStats struct {
+	Hits   int64 `json:"hits"`
+	Misses int64 `json:"misses"`
 }
 
 // NewMetrics cria um novo collector de métricas.
@@ -50,6 +61,24 @@ func (m *Metrics) RecordQuery(metric QueryMetric) {
 	}
 }
 
+// RecordCache contabiliza um acerto (hit=true) ou falha do cache de resultados.
+func (m *Metrics) RecordCache(hit bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if hit {
+		m.cacheHits++
+	} else {
+		m.cacheMisses++
+	}
+}
+
+// GetCacheStats retorna os contadores do cache de resultados.
+func (m *Metrics) GetCacheStats() CacheStats {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return CacheStats{Hits: m.cacheHits, Misses: m.cacheMisses}
+}
+
 // GetMetrics retorna snapshot das métricas.
 func (m *Metrics) GetMetrics() []QueryMetric {
 	m.mu.RLock()
@@ -66,6 +95,7 @@ type Summary struct {
 	TotalQueries int    `json:"totalQueries"`
 	SuccessCount int    `json:"successCount"`
 	ErrorCount   int    `json:"errorCount"`
+	TimeoutCount int    `json:"timeoutCount"` // incluídos em errorCount
 	AvgLatencyMs int64  `json:"avgLatencyMs"`
 	P95LatencyMs int64  `json:"p95LatencyMs"`
 	TotalRows    int64  `json:"totalRows"`
@@ -92,6 +122,9 @@ func (m *Metrics) GetSummary() []Summary {
 		} else {
 			s.ErrorCount++
 		}
+		if q.Status == "timeout" {
+			s.TimeoutCount++
+		}
 		s.TotalRows += int64(q.Rows)
 	}
 
@@ -135,3 +168,27 @@ func (m *Metrics) GetSummary() []Summary {
 	}
 	return result
 }
+
+// LatencyP95 retorna o p95 de latência das queries de {dataSource}/{table}/{shape} e o número
+// de amostras consideradas. Timeouts contam com a latência registrada (o prazo estourado): um
+// shape que sempre estoura o prazo precisa ir para a fila, não sumir do histórico.
+func (m *Metrics) LatencyP95(dataSource, table, shape string) (int64, int) {
+	m.mu.RLock()
+	var latencies []int64
+	for _, q := range m.queries {
+		if (q.Status == "success" || q.Status == "timeout") && q.DataSource == dataSource && q.Table == table && q.Shape == shape {
+			latencies = append(latencies, q.Latency)
+		}
+	}
+	m.mu.RUnlock()
+
+	if len(latencies) == 0 {
+		return 0, 0
+	}
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+	idx := (len(latencies) * 95) / 100
+	if idx >= len(latencies) {
+		idx = len(latencies) - 1
+	}
+	return latencies[idx], len(latencies)
+}
@@ -51,13 +51,19 @@ func TestMetricsSummary(t *testing.T) {
 		Latency:    100,
 		Rows:       0,
 	})
+	m.RecordQuery(QueryMetric{
+		DataSource: "ds1",
+		Table:      "table1",
+		Status:     "timeout",
+	})
 
 	summary := m.GetSummary()
 	assert.Equal(t, 1, len(summary))
 	assert.Equal(t, "ds1", summary[0].DataSource)
-	assert.Equal(t, 11, summary[0].TotalQueries)
+	assert.Equal(t, 12, summary[0].TotalQueries)
 	assert.Equal(t, 10, summary[0].SuccessCount)
-	assert.Equal(t, 1, summary[0].ErrorCount)
+	assert.Equal(t, 2, summary[0].ErrorCount)
+	assert.Equal(t, 1, summary[0].TimeoutCount)
 	assert.Equal(t, int64(50), summary[0].TotalRows)
 }
 
@@ -76,3 +82,41 @@ func TestMetricsMaxSize(t *testing.T) {
 	metrics := m.GetMetrics()
 	assert.Equal(t, 3, len(metrics), "Should keep only last 3 queries")
 }
+
+func TestMetricsLatencyP95(t *testing.T) {
+	m := NewMetrics(100)
+
+	for i := 1; i <= 20; i++ {
+		m.RecordQuery(QueryMetric{DataSource: "ds1", Table: "t", Shape: "a", Status: "success", Latency: int64(i * 10)})
+	}
+	m.RecordQuery(QueryMetric{DataSource: "ds1", Table: "t", Shape: "a", Status: "error", Latency: 5000})
+	m.RecordQuery(QueryMetric{DataSource: "ds1", Table: "t", Shape: "b", Status: "success", Latency: 9000})
+
+	p95, samples := m.LatencyP95("ds1", "t", "a")
+	assert.Equal(t, 20, samples)
+	assert.Equal(t, int64(200), p95)
+
+	// Timeouts contam como amostras na latência do prazo
+	for i := 0; i < 5; i++ {
+		m.RecordQuery(QueryMetric{DataSource: "ds1", Table: "t", Shape: "slow", Status: "timeout", Latency: 4000})
+	}
+	p95, samples = m.LatencyP95("ds1", "t", "slow")
+	assert.Equal(t, 5, samples)
+	assert.Equal(t, int64(4000), p95)
+
+	p95, samples = m.LatencyP95("ds1", "t", "missing")
+	assert.Equal(t, 0, samples)
+	assert.Equal(t, int64(0), p95)
+}
+
+func TestMetricsCacheStats(t *testing.T) {
+	m := NewMetrics(10)
+
+	m.RecordCache(true)
+	m.RecordCache(true)
+	m.RecordCache(false)
+
+	stats := m.GetCacheStats()
+	assert.Equal(t, int64(2), stats.Hits)
+	assert.Equal(t, int64(1), stats.Misses)
+}